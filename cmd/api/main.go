@@ -2,39 +2,219 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"io"
 	"log"
+	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"os/signal"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"karhub-beer-machine/internal/application/auth"
 	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/health"
+	"karhub-beer-machine/internal/application/metrics"
+	"karhub-beer-machine/internal/application/reload"
+	"karhub-beer-machine/internal/application/tracing"
 	domain "karhub-beer-machine/internal/domain/beer"
 	cacheinfra "karhub-beer-machine/internal/infrastructure/cache"
+	"karhub-beer-machine/internal/infrastructure/cacheadmin"
+	"karhub-beer-machine/internal/infrastructure/config"
+	"karhub-beer-machine/internal/infrastructure/jwt"
+	"karhub-beer-machine/internal/infrastructure/localmusic"
+	"karhub-beer-machine/internal/infrastructure/otlp"
+	"karhub-beer-machine/internal/infrastructure/persistence/jsonfile"
 	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	"karhub-beer-machine/internal/infrastructure/prometheus"
 	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
 	httpapi "karhub-beer-machine/internal/interfaces/http"
 	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// Set at build time, e.g.
+// go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse HEAD)".
+var (
+	version = "dev"
+	commit  = ""
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+
 	ctx := context.Background()
 
+	logLevel := new(slog.LevelVar)
+	logger := mustCreateLogger(cfg.Log, logLevel)
+	// The standard log package, used at startup, goes through it too
+	slog.SetDefault(logger)
+
+	registry := prometheus.NewRegistry()
+	metricSet := prometheus.NewMetrics(registry)
+	metrics.SetDefault(metricSet)
+
+	tracer := mustCreateTracer(cfg.Tracing)
+	if tracer != nil {
+		tracing.SetDefault(tracer)
+	}
+
+	checks := health.NewRegistry()
+	// Fails readiness as soon as shutdown begins
+	shutdown := health.NewShutdownCheck()
+	checks.Register("shutdown", shutdown)
+
 	repo := mustCreateRepository()
-	spotifyGateway := mustCreateSpotifyGateway(ctx)
+	overrides := memory.NewPlaylistOverrideRepository()
+	// Cache sempre existe, independente de Spotify real ou stub
+	playlistCache := mustCreatePlaylistCache(ctx, cfg.Cache)
+	spotifyGateway := mustCreateSpotifyGateway(ctx, cfg, playlistCache, metricSet, checks)
+	music, router := mustCreateMusicProvider(cfg.Music, localCatalogOptions(cfg.Spotify), spotifyGateway, overrides)
+
+	recommendations := mustCreateRecommendationCache(cfg.Recommendation)
+
+	caches := map[string]beer.AdministrableCache{
+		"playlists":       cacheadmin.New(playlistCache),
+		"recommendations": cacheadmin.New(recommendations),
+	}
 
-	useCases := buildUseCases(repo, spotifyGateway)
-	handler := buildHTTPHandler(useCases)
+	metricSet.WatchCaches(caches)
+	metricSet.WatchCatalog(repo)
+
+	apiKeys := mustCreateAPIKeyRepository(cfg.Auth)
+
+	registerHealthChecks(checks, repo, playlistCache, spotifyGateway)
+
+	limiters := &rateLimiters{}
+	guard := mustCreateGuard(cfg.Auth, apiKeys, limiters.build(cfg.RateLimit))
+
+	findBest := beer.NewFindBestBeerStyleUseCase(repo, music,
+		beer.WithRecommendationMemo(recommendations, cfg.Recommendation.Precision),
+		beer.WithSelectionStrategy(cfg.Recommendation.Strategy),
+	)
 
-	server := buildHTTPServer(handler)
+	reloader := config.NewReloader(cfg, func() (config.Config, error) {
+		return config.Load(os.Args[1:], os.LookupEnv, io.Discard)
+	}, reloadComponents(logLevel, spotifyGateway, router, findBest, guard, limiters)...)
+
+	useCases := buildUseCases(repo, overrides, findBest, spotifyGateway, caches, apiKeys, checks, reloader)
+	handlerSet := buildHTTPHandlers(useCases)
+
+	server := buildHTTPServer(
+		cfg.HTTP,
+		handlerSet,
+		guard,
+		logger,
+		registry,
+		metricSet,
+	)
+
+	// Stop on SIGINT or SIGTERM; a second signal kills the process at once
+	stopCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stopCtx.Done()
+		stop()
+	}()
+
+	go reloadOnSIGHUP(stopCtx, useCases.reloadConfig)
+
+	// Warm in the background so a slow Spotify never delays startup
+	warmed := make(chan struct{})
+	go func() {
+		defer close(warmed)
+		warmPlaylistCache(stopCtx, cfg.Cache.Warmup, useCases.warmCache)
+	}()
+
+	opts := []httpapi.ServerOption{
+		httpapi.WithReadiness(shutdown),
+		httpapi.WithDrainDelay(cfg.Shutdown.DrainDelay),
+		httpapi.WithGracePeriod(cfg.Shutdown.GracePeriod),
+		httpapi.WithCloser("playlist_cache_warmup", func(ctx context.Context) error {
+			select {
+			case <-warmed:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}),
+		httpapi.WithCloser("music_provider", func(context.Context) error {
+			spotifyGateway.Close()
+			return nil
+		}),
+	}
+	opts = append(opts, closers(map[string]any{
+		"playlist_cache":       playlistCache,
+		"recommendation_cache": recommendations,
+	})...)
+	opts = append(opts, closers(map[string]any{
+		"repository":         repo,
+		"api_key_repository": apiKeys,
+	})...)
+	if tracer != nil {
+		// Last, so the spans of the shutdown itself are exported
+		opts = append(opts, httpapi.WithCloser("tracer", tracer.Shutdown))
+	}
 
 	log.Printf("HTTP server running on %s", server.Addr)
-	log.Fatal(server.ListenAndServe())
+	if err := httpapi.NewServer(server, opts...).ListenAndServe(stopCtx); err != nil {
+		log.Fatalf("unclean shutdown: %v", err)
+	}
+	log.Printf("shutdown complete")
 }
 
 /*
 	---------- Builders ----------
 */
 
+// mustCreateLogger writes records in the configured format (json or
+// text) to stderr, from level up, which it sets to the configured level.
+func mustCreateLogger(cfg config.Log, level *slog.LevelVar) *slog.Logger {
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		log.Fatalf("invalid log level: %v", err)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+}
+
+// mustCreateTracer exports spans to the OTLP/HTTP collector at the
+// traces endpoint, or the base endpoint followed by /v1/traces. It
+// returns nil, disabling tracing, when neither is set.
+func mustCreateTracer(cfg config.Tracing) *otlp.Tracer {
+	endpoint := cfg.TracesEndpoint
+	if endpoint == "" && cfg.Endpoint != "" {
+		endpoint = strings.TrimSuffix(cfg.Endpoint, "/") + "/v1/traces"
+	}
+	if endpoint == "" {
+		return nil
+	}
+
+	exporter := otlp.NewHTTPExporter(endpoint,
+		otlp.WithServiceName(cfg.ServiceName),
+		otlp.WithHeaders(cfg.Headers),
+	)
+
+	log.Printf("exporting traces to %s", endpoint)
+	return otlp.NewTracer(exporter, otlp.WithSampleRatio(cfg.SampleRatio))
+}
+
 func mustCreateRepository() domain.BeerStyleRepository {
 	repo, err := memory.NewBeerStyleRepository()
 	if err != nil {
@@ -43,83 +223,460 @@ func mustCreateRepository() domain.BeerStyleRepository {
 	return repo
 }
 
-func mustCreateSpotifyGateway(ctx context.Context) beer.SpotifyGateway {
-	// Cache sempre existe, independente de Spotify real ou stub
-	playlistCache, err := cacheinfra.NewRistrettoCache[string, beer.Playlist](
-		1e5,   // counters
-		1<<20, // ~1MB
-	)
+// mustCreateMusicProvider registers every available music provider
+// behind a router that picks one per request or per tenant, and lets
+// admin-curated overrides take precedence over all of them. The router
+// is returned too, so its defaults can be reloaded.
+func mustCreateMusicProvider(
+	cfg config.Music,
+	catalogOptions []localmusic.CatalogOption,
+	spotifyGateway *spotifyinfra.CachedGateway,
+	overrides beer.PlaylistOverrideRepository,
+) (beer.MusicProvider, *beer.MusicProviderRouter) {
+	providers := map[string]beer.MusicProvider{
+		spotifyinfra.ProviderName: spotifyGateway,
+	}
+
+	if cfg.CatalogFile != "" {
+		catalog, err := localmusic.NewCatalogProvider(cfg.CatalogFile, catalogOptions...)
+		if err != nil {
+			log.Fatalf("failed to load music catalog: %v", err)
+		}
+		providers[catalog.Name()] = catalog
+	}
+
+	router, err := beer.NewMusicProviderRouter(providers, cfg.DefaultProvider, cfg.TenantProviders)
 	if err != nil {
-		log.Fatalf("failed to create cache: %v", err)
+		log.Fatalf("failed to configure music providers: %v", err)
 	}
 
-	spotifyClient, err := spotifyinfra.NewSpotifyClient(ctx)
+	return beer.NewOverridingMusicProvider(router, overrides, spotifyGateway), router
+}
+
+func mustCreateSpotifyGateway(
+	ctx context.Context,
+	cfg config.Config,
+	playlistCache cacheinfra.Cache[string, beer.Playlist],
+	metricSet *prometheus.Metrics,
+	checks *health.Registry,
+) *spotifyinfra.CachedGateway {
+	var gateway beer.MusicProvider
+
+	scheduler := spotifyinfra.NewScheduler(
+		spotifyinfra.DefaultRequestsPerSecond,
+		spotifyinfra.DefaultBurst,
+		spotifyinfra.DefaultMaxQueueWait,
+	)
+	metricSet.WatchSpotifyBackoff(scheduler)
+
+	spotifyClient, err := spotifyinfra.NewSpotifyClient(
+		ctx,
+		append(
+			spotifyClientOptions(cfg.Spotify),
+			spotifyinfra.WithScheduler(scheduler),
+			spotifyinfra.WithCallObserver(metricSet.ObserveSpotifyCall),
+		)...,
+	)
 	if err != nil {
 		log.Printf(
 			"spotify unavailable (%v), falling back to stub",
 			err,
 		)
 
-		stub := spotifyinfra.NewSpotifyStub()
+		gateway = spotifyinfra.NewSpotifyStub()
+	} else {
+		gateway = spotifyClient
+		checks.Register("spotify_token", health.CheckerFunc(spotifyClient.CheckToken),
+			health.Optional(), health.WithTimeout(5*time.Second))
+	}
+
+	// Fresh for the TTL, then served stale while refreshed in the
+	// background, and kept longer as a fallback for Spotify outages
+	return spotifyinfra.NewCachedSpotifyGateway(gateway, playlistCache, cfg.Cache.TTL, playlistTTLOptions(cfg.Cache)...)
+}
 
-		return spotifyinfra.NewCachedSpotifyGateway(
-			stub,
-			playlistCache,
-			10*time.Minute,
+// playlistTTLOptions sets the TTLs of the playlist cache past the soft one.
+func playlistTTLOptions(cfg config.Cache) []spotifyinfra.CacheOption {
+	return []spotifyinfra.CacheOption{
+		spotifyinfra.WithStaleWhileRevalidate(cfg.StaleWhileRevalidate),
+		spotifyinfra.WithStaleIfError(cfg.StaleIfError),
+		spotifyinfra.WithNegativeTTL(cfg.NegativeTTL),
+	}
+}
+
+// registerHealthChecks registers the checks of the dependencies behind
+// /readyz. Only the repository is required: without the cache or Spotify,
+// recommendations are slower or served stale, but still served.
+func registerHealthChecks(
+	checks *health.Registry,
+	repo domain.BeerStyleRepository,
+	playlistCache cacheinfra.Cache[string, beer.Playlist],
+	spotifyGateway *spotifyinfra.CachedGateway,
+) {
+	checks.Register("repository", health.CheckerFunc(func(context.Context) error {
+		_, err := repo.FindAll()
+		return err
+	}))
+
+	if checker, ok := playlistCache.(health.Checker); ok {
+		checks.Register("playlist_cache", checker, health.Optional())
+	}
+
+	checks.Register("music_provider", spotifyGateway, health.Optional())
+}
+
+// buildInfo reports the version and commit set at build time, falling
+// back to the VCS revision the Go toolchain stamps into the binary.
+func buildInfo() health.Build {
+	build := health.Build{Version: version, Commit: commit}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+
+	build.GoVersion = info.GoVersion
+	if build.Commit == "" {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				build.Commit = setting.Value
+			}
+		}
+	}
+
+	return build
+}
+
+// mustCreatePlaylistCache picks the playlist cache backend: "memory"
+// (per replica) or "redis" (shared). The memory backend gets a disk tier
+// when a disk path is set.
+func mustCreatePlaylistCache(ctx context.Context, cfg config.Cache) cacheinfra.Cache[string, beer.Playlist] {
+	if cfg.Backend == "redis" {
+		client := cacheinfra.NewRedisClient(cfg.Redis.Addr,
+			cacheinfra.WithRedisPassword(cfg.Redis.Password),
+			cacheinfra.WithRedisDB(cfg.Redis.DB),
 		)
+
+		// A Redis outage only costs cache misses, so do not refuse to start
+		if err := client.Ping(ctx); err != nil {
+			log.Printf("redis unavailable at %s (%v), playlists will not be cached until it is back", cfg.Redis.Addr, err)
+		}
+
+		return cacheinfra.NewRedisCache[string](client, spotifyinfra.PlaylistCodec{}, cfg.Redis.KeyPrefix)
 	}
 
-	return spotifyinfra.NewCachedSpotifyGateway(
-		spotifyClient,
+	playlistCache, err := cacheinfra.NewRistrettoCache[string, beer.Playlist](cfg.Counters, cfg.MaxCost)
+	if err != nil {
+		log.Fatalf("failed to create cache: %v", err)
+	}
+
+	if cfg.Disk.Path == "" {
+		return playlistCache
+	}
+
+	disk, err := cacheinfra.OpenDiskStore(cfg.Disk.Path, cfg.Disk.MaxBytes)
+	if err != nil {
+		log.Fatalf("failed to open disk cache: %v", err)
+	}
+
+	return cacheinfra.NewTieredCache(
 		playlistCache,
-		10*time.Minute,
+		disk,
+		spotifyinfra.PlaylistCodec{},
+		cacheinfra.DefaultWriteBehindQueue,
 	)
 }
 
+// mustCreateRecommendationCache creates the per-replica cache memoizing
+// the best style per temperature bucket and catalog version.
+func mustCreateRecommendationCache(cfg config.Recommendation) *cacheinfra.RistrettoCache[string, domain.BeerStyle] {
+	recommendations, err := cacheinfra.NewRistrettoCache[string, domain.BeerStyle](cfg.Counters, cfg.MaxCost)
+	if err != nil {
+		log.Fatalf("failed to create recommendation cache: %v", err)
+	}
+	return recommendations
+}
+
+// mustCreateAPIKeyRepository opens the store of the API keys issued at
+// runtime: a JSON file, or memory when none is set.
+func mustCreateAPIKeyRepository(cfg config.Auth) auth.APIKeyRepository {
+	if cfg.APIKeysFile == "" {
+		log.Printf("API_KEYS_FILE not set, issued API keys will not survive a restart")
+		return memory.NewAPIKeyRepository()
+	}
+
+	keys, err := jsonfile.NewAPIKeyRepository(cfg.APIKeysFile)
+	if err != nil {
+		log.Fatalf("failed to open API key store: %v", err)
+	}
+	return keys
+}
+
+// mustCreateGuard authenticates callers with HS256 bearer tokens signed
+// with the JWT secret, with the static API keys and the admin token, and
+// with the keys issued at runtime. Callers without credentials get the
+// anonymous role ("none" requires credentials everywhere).
+func mustCreateGuard(cfg config.Auth, issued auth.APIKeyRepository, limiter *middleware.RateLimiter) *middleware.Guard {
+	keys := memory.NewAPIKeyRepository()
+
+	for _, key := range cfg.APIKeys {
+		_ = keys.Save(auth.APIKey{ID: key.Name, Name: key.Name, Role: key.Role, Hash: key.Hash})
+	}
+
+	// The former shared admin token keeps working as an admin API key
+	if cfg.AdminToken != "" {
+		_ = keys.Save(auth.APIKey{
+			ID:   "admin-token",
+			Name: "admin-token",
+			Role: auth.RoleAdmin,
+			Hash: auth.HashAPIKey(cfg.AdminToken),
+		})
+	}
+
+	var authenticators []auth.Authenticator
+
+	if cfg.JWTSecret != "" {
+		tokens, err := jwt.NewHMACAuthenticator(
+			[]byte(cfg.JWTSecret),
+			jwt.WithIssuer(cfg.JWTIssuer),
+			jwt.WithAudience(cfg.JWTAudience),
+		)
+		if err != nil {
+			log.Fatalf("invalid JWT secret: %v", err)
+		}
+		authenticators = append(authenticators, tokens)
+	}
+
+	// API keys accept any credential, so they go last
+	authenticators = append(authenticators, auth.NewAPIKeyAuthenticator(keys, issued))
+
+	anonymous := auth.RoleNone
+	if cfg.AnonymousRole != "none" {
+		role, err := auth.ParseRole(cfg.AnonymousRole)
+		if err != nil {
+			log.Fatalf("invalid anonymous role: %v", err)
+		}
+		anonymous = role
+	}
+
+	if anonymous == auth.RoleAdmin {
+		log.Printf("AUTH_ANONYMOUS_ROLE=admin: anyone can change the catalog")
+	}
+
+	return middleware.NewGuard(
+		auth.NewChainAuthenticator(authenticators...),
+		anonymous,
+		middleware.WithRateLimiter(limiter),
+	)
+}
+
+// localCatalogOptions applies the track settings of Spotify to the local
+// catalog too, so recommendations do not depend on the provider.
+func localCatalogOptions(cfg config.Spotify) []localmusic.CatalogOption {
+	opts := []localmusic.CatalogOption{
+		localmusic.WithTrackLimit(cfg.TrackLimit),
+	}
+
+	if cfg.SkipExplicit {
+		opts = append(opts, localmusic.WithoutExplicitTracks())
+	}
+
+	return opts
+}
+
+// spotifyClientOptions passes the credentials and track settings on.
+func spotifyClientOptions(cfg config.Spotify) []spotifyinfra.ClientOption {
+	opts := []spotifyinfra.ClientOption{
+		spotifyinfra.WithCredentials(cfg.ClientID, cfg.ClientSecret),
+		spotifyinfra.WithTrackLimit(cfg.TrackLimit),
+	}
+
+	if cfg.SkipExplicit {
+		opts = append(opts, spotifyinfra.WithoutExplicitTracks())
+	}
+
+	return opts
+}
+
 type useCases struct {
 	create   *beer.CreateBeerStyleUseCase
 	update   *beer.UpdateBeerStyleUseCase
 	delete   *beer.DeleteBeerStyleUseCase
 	list     *beer.ListBeerStylesUseCase
 	findBest *beer.FindBestBeerStyleUseCase
+
+	setOverride    *beer.SetPlaylistOverrideUseCase
+	getOverride    *beer.GetPlaylistOverrideUseCase
+	deleteOverride *beer.DeletePlaylistOverrideUseCase
+
+	warmCache *beer.WarmPlaylistCacheUseCase
+
+	cacheStats   *beer.GetCacheStatsUseCase
+	inspectCache *beer.InspectCacheUseCase
+	purgeCache   *beer.PurgeCacheUseCase
+
+	createAPIKey *auth.CreateAPIKeyUseCase
+	listAPIKeys  *auth.ListAPIKeysUseCase
+	revokeAPIKey *auth.RevokeAPIKeyUseCase
+	rotateAPIKey *auth.RotateAPIKeyUseCase
+
+	checkHealth *health.CheckHealthUseCase
+
+	reloadConfig *reload.ReloadConfigUseCase
 }
 
+// buildUseCases wires the catalog use cases to the playlist cache, so
+// catalog changes invalidate and pre-warm its entries. findBest is built
+// beforehand, as configuration reloads swap its strategy.
 func buildUseCases(
 	repo domain.BeerStyleRepository,
-	spotify beer.SpotifyGateway,
+	overrides beer.PlaylistOverrideRepository,
+	findBest *beer.FindBestBeerStyleUseCase,
+	playlistCache *spotifyinfra.CachedGateway,
+	caches map[string]beer.AdministrableCache,
+	apiKeys auth.APIKeyRepository,
+	checks *health.Registry,
+	reloader reload.Reloader,
 ) useCases {
 	return useCases{
-		create:   beer.NewCreateBeerStyleUseCase(repo),
-		update:   beer.NewUpdateBeerStyleUseCase(repo),
-		delete:   beer.NewDeleteBeerStyleUseCase(repo),
+		create:   beer.NewCreateBeerStyleUseCase(repo, playlistCache),
+		update:   beer.NewUpdateBeerStyleUseCase(repo, playlistCache),
+		delete:   beer.NewDeleteBeerStyleUseCase(repo, overrides, playlistCache),
 		list:     beer.NewListBeerStylesUseCase(repo),
-		findBest: beer.NewFindBestBeerStyleUseCase(repo, spotify),
+		findBest: findBest,
+
+		setOverride:    beer.NewSetPlaylistOverrideUseCase(repo, overrides),
+		getOverride:    beer.NewGetPlaylistOverrideUseCase(overrides),
+		deleteOverride: beer.NewDeletePlaylistOverrideUseCase(overrides),
+
+		warmCache: beer.NewWarmPlaylistCacheUseCase(repo, playlistCache),
+
+		cacheStats:   beer.NewGetCacheStatsUseCase(caches),
+		inspectCache: beer.NewInspectCacheUseCase(caches),
+		purgeCache:   beer.NewPurgeCacheUseCase(caches),
+
+		createAPIKey: auth.NewCreateAPIKeyUseCase(apiKeys),
+		listAPIKeys:  auth.NewListAPIKeysUseCase(apiKeys),
+		revokeAPIKey: auth.NewRevokeAPIKeyUseCase(apiKeys),
+		rotateAPIKey: auth.NewRotateAPIKeyUseCase(apiKeys),
+
+		checkHealth: health.NewCheckHealthUseCase(checks, buildInfo()),
+
+		reloadConfig: reload.NewReloadConfigUseCase(reloader),
 	}
 }
 
-func buildHTTPHandler(uc useCases) *handlers.BeerHandler {
-	return handlers.NewBeerHandler(
-		uc.create,
-		uc.update,
-		uc.delete,
-		uc.list,
-		uc.findBest,
+// warmPlaylistCache pre-loads the playlist of every catalog style within
+// the configured parallel fetches (0 disables) and time budget.
+func warmPlaylistCache(ctx context.Context, cfg config.Warmup, uc *beer.WarmPlaylistCacheUseCase) {
+	input := beer.WarmPlaylistCacheInput{
+		Concurrency: cfg.Concurrency,
+		Budget:      cfg.Timeout,
+	}
+
+	if input.Concurrency <= 0 {
+		return
+	}
+
+	start := time.Now()
+
+	output, err := uc.Execute(ctx, input)
+	if err != nil {
+		log.Printf("playlist cache warmup failed: %v", err)
+		return
+	}
+
+	log.Printf(
+		"playlist cache warmed in %s: %d warmed, %d failed, %d skipped",
+		time.Since(start).Round(time.Millisecond),
+		output.Warmed, output.Failed, output.Skipped,
 	)
 }
 
-func buildHTTPServer(handler *handlers.BeerHandler) *http.Server {
-	mux := http.NewServeMux()
-	httpapi.RegisterRoutes(mux, handler)
+// closers closes, in name order, every dependency holding resources.
+func closers(dependencies map[string]any) []httpapi.ServerOption {
+	var opts []httpapi.ServerOption
 
-	port := os.Getenv("HTTP_PORT")
-	if port == "" {
-		port = "8080"
+	for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+		if c, ok := dependencies[name].(io.Closer); ok {
+			opts = append(opts, httpapi.WithCloser(name, func(context.Context) error {
+				return c.Close()
+			}))
+		}
 	}
 
+	return opts
+}
+
+type httpHandlers struct {
+	beer       *handlers.BeerHandler
+	overrides  *handlers.PlaylistOverrideHandler
+	cacheAdmin *handlers.CacheAdminHandler
+	apiKeys    *handlers.APIKeyHandler
+	health     *handlers.HealthHandler
+	config     *handlers.ConfigHandler
+}
+
+func buildHTTPHandlers(uc useCases) httpHandlers {
+	return httpHandlers{
+		beer: handlers.NewBeerHandler(
+			uc.create,
+			uc.update,
+			uc.delete,
+			uc.list,
+			uc.findBest,
+		),
+		overrides: handlers.NewPlaylistOverrideHandler(
+			uc.setOverride,
+			uc.getOverride,
+			uc.deleteOverride,
+		),
+		cacheAdmin: handlers.NewCacheAdminHandler(
+			uc.cacheStats,
+			uc.inspectCache,
+			uc.purgeCache,
+		),
+		apiKeys: handlers.NewAPIKeyHandler(
+			uc.createAPIKey,
+			uc.listAPIKeys,
+			uc.revokeAPIKey,
+			uc.rotateAPIKey,
+		),
+		health: handlers.NewHealthHandler(uc.checkHealth),
+		config: handlers.NewConfigHandler(uc.reloadConfig),
+	}
+}
+
+// buildHTTPServer registers every route behind the guard, and wraps the
+// mux with request IDs, tracing, access logs, metrics and panic recovery.
+func buildHTTPServer(
+	cfg config.HTTP,
+	h httpHandlers,
+	guard *middleware.Guard,
+	logger *slog.Logger,
+	registry *prometheus.Registry,
+	metricSet *prometheus.Metrics,
+) *http.Server {
+	mux := http.NewServeMux()
+	httpapi.RegisterRoutes(mux, h.beer, guard)
+	httpapi.RegisterPlaylistOverrideRoutes(mux, h.overrides, guard)
+	httpapi.RegisterCacheAdminRoutes(mux, h.cacheAdmin, guard)
+	httpapi.RegisterAPIKeyRoutes(mux, h.apiKeys, guard)
+	httpapi.RegisterConfigRoutes(mux, h.config, guard)
+	httpapi.RegisterMetricsRoutes(mux, registry, guard)
+	httpapi.RegisterHealthRoutes(mux, h.health, guard)
+
 	return &http.Server{
-		Addr:         ":" + port,
-		Handler:      mux,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr: ":" + strconv.Itoa(cfg.Port),
+		Handler: middleware.Chain(mux,
+			middleware.RequestID,
+			middleware.Trace,
+			middleware.AccessLog(logger),
+			middleware.Instrument(metricSet),
+			middleware.Recover,
+		),
+		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	}
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/reload"
+	"karhub-beer-machine/internal/infrastructure/config"
+	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// reloadComponents lists what a configuration reload swaps in: the log
+// level, the playlist cache TTLs, the music provider defaults, the
+// recommendation strategy and the rate limits.
+func reloadComponents(
+	logLevel *slog.LevelVar,
+	spotifyGateway *spotifyinfra.CachedGateway,
+	router *beer.MusicProviderRouter,
+	findBest *beer.FindBestBeerStyleUseCase,
+	guard *middleware.Guard,
+	limiters *rateLimiters,
+) []config.Component {
+	return []config.Component{
+		{
+			Name: "log",
+			Apply: func(cfg config.Config) {
+				// Validated by config.Load
+				_ = logLevel.UnmarshalText([]byte(cfg.Log.Level))
+			},
+		},
+		{
+			Name: "playlist_cache",
+			Apply: func(cfg config.Config) {
+				spotifyGateway.SetTTLs(cfg.Cache.TTL, playlistTTLOptions(cfg.Cache)...)
+			},
+		},
+		{
+			Name: "music_provider",
+			Check: func(cfg config.Config) error {
+				return router.CheckDefaults(cfg.Music.DefaultProvider, cfg.Music.TenantProviders)
+			},
+			Apply: func(cfg config.Config) {
+				_ = router.SetDefaults(cfg.Music.DefaultProvider, cfg.Music.TenantProviders)
+			},
+		},
+		{
+			Name: "recommendation",
+			Check: func(cfg config.Config) error {
+				return beer.ValidateSelectionStrategy(cfg.Recommendation.Strategy)
+			},
+			Apply: func(cfg config.Config) {
+				_ = findBest.SetSelectionStrategy(cfg.Recommendation.Strategy)
+			},
+		},
+		{
+			Name: "rate_limiter",
+			Apply: func(cfg config.Config) {
+				guard.SetRateLimiter(limiters.build(cfg.RateLimit))
+			},
+		},
+	}
+}
+
+// reloadOnSIGHUP reloads the configuration on every SIGHUP until ctx is
+// done. The use case logs the outcome.
+func reloadOnSIGHUP(ctx context.Context, uc *reload.ReloadConfigUseCase) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+			_, _ = uc.Execute(ctx, reload.ReloadConfigInput{Trigger: "SIGHUP"})
+		}
+	}
+}
+
+// rateLimiters builds the rate limiter from the settings. Rebuilt on
+// reload, it keeps the buckets and quota of unchanged limits, so a
+// reload neither grants clients a new burst nor resets their quota.
+type rateLimiters struct {
+	maxClients int
+	classes    map[string]rateLimitClass
+	quota      *middleware.DailyQuota
+	quotaLimit int
+}
+
+// rateLimitClass is the bucket of a route class, and its limit.
+type rateLimitClass struct {
+	limit   config.Limit
+	limiter *middleware.TokenBucketLimiter
+}
+
+// build limits each client per route class (a rate of 0 leaves the class
+// unlimited), and each authenticated client per day. Behind reverse
+// proxies, the forwarded hops say how many to trust in X-Forwarded-For.
+// It returns nil when nothing is limited.
+func (b *rateLimiters) build(cfg config.RateLimit) *middleware.RateLimiter {
+	// Buckets are bounded by the clients they track (quotas are not)
+	if cfg.MaxClients != b.maxClients {
+		b.maxClients = cfg.MaxClients
+		b.classes = nil
+	}
+	limiterOpts := []middleware.LimiterOption{middleware.WithMaxClients(cfg.MaxClients)}
+
+	classes := make(map[string]rateLimitClass)
+	limiters := make(map[string]*middleware.TokenBucketLimiter)
+	for class, limit := range cfg.Limits {
+		if limit.Rate <= 0 {
+			continue
+		}
+
+		c, found := b.classes[class]
+		if !found || c.limit != limit {
+			c = rateLimitClass{
+				limit:   limit,
+				limiter: middleware.NewTokenBucketLimiter(middleware.Limit{Rate: limit.Rate, Burst: limit.Burst}, limiterOpts...),
+			}
+		}
+		classes[class] = c
+		limiters[class] = c.limiter
+	}
+	b.classes = classes
+
+	if cfg.DailyQuota != b.quotaLimit {
+		b.quotaLimit = cfg.DailyQuota
+		b.quota = nil
+	}
+	if b.quota == nil && cfg.DailyQuota > 0 {
+		b.quota = middleware.NewDailyQuota(cfg.DailyQuota)
+	}
+
+	opts := []middleware.RateLimiterOption{
+		middleware.WithForwardedHops(cfg.ForwardedHops),
+	}
+
+	if b.quota != nil {
+		opts = append(opts, middleware.WithDailyQuota(b.quota))
+	}
+
+	if len(limiters) == 0 && len(opts) == 1 {
+		log.Printf("rate limiting disabled")
+		return nil
+	}
+
+	return middleware.NewRateLimiter(limiters, opts...)
+}
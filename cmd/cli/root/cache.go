@@ -0,0 +1,127 @@
+package root
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type cacheStatsResponse struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRatio  float64 `json:"hitRatio"`
+	Evictions uint64  `json:"evictions"`
+	Keys      int     `json:"keys"`
+	Cost      int64   `json:"cost"`
+	MaxCost   int64   `json:"maxCost"`
+	Errors    uint64  `json:"errors"`
+}
+
+type cachePurgeResponse struct {
+	Cache  string `json:"cache"`
+	Purged int    `json:"purged"`
+}
+
+func newCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and purge the API caches",
+	}
+
+	cmd.AddCommand(
+		newCacheStatsCommand(),
+		newCachePurgeCommand(),
+	)
+
+	return cmd
+}
+
+func newCacheStatsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show hit, miss, eviction and cost statistics per cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var stats map[string]cacheStatsResponse
+			if err := apiRequest(cmd, http.MethodGet, "/admin/caches", nil, &stats); err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(stats))
+			for name := range stats {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CACHE\tHITS\tMISSES\tHIT RATIO\tEVICTIONS\tKEYS\tCOST\tERRORS")
+			for _, name := range names {
+				s := stats[name]
+				fmt.Fprintf(
+					w,
+					"%s\t%d\t%d\t%.1f%%\t%d\t%d\t%s\t%d\n",
+					name, s.Hits, s.Misses, 100*s.HitRatio, s.Evictions, s.Keys,
+					formatCost(s.Cost, s.MaxCost), s.Errors,
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newCachePurgeCommand() *cobra.Command {
+	var (
+		cacheName string
+		key       string
+		prefix    string
+		all       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Purge a cache by key, by prefix or entirely",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			switch {
+			case key != "":
+				query.Set("key", key)
+			case prefix != "":
+				query.Set("prefix", prefix)
+			case all:
+				query.Set("all", "true")
+			}
+
+			path := fmt.Sprintf("/admin/caches/%s/keys?%s", url.PathEscape(cacheName), query.Encode())
+
+			var resp cachePurgeResponse
+			if err := apiRequest(cmd, http.MethodDelete, path, nil, &resp); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Purged %d key(s) from %s\n", resp.Purged, resp.Cache)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cacheName, "cache", "playlists", "cache to purge")
+	cmd.Flags().StringVar(&key, "key", "", "purge a single key")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "purge every key starting with prefix")
+	cmd.Flags().BoolVar(&all, "all", false, "purge every key")
+	cmd.MarkFlagsMutuallyExclusive("key", "prefix", "all")
+	cmd.MarkFlagsOneRequired("key", "prefix", "all")
+
+	return cmd
+}
+
+func formatCost(cost, maxCost int64) string {
+	if maxCost <= 0 {
+		return strconv.FormatInt(cost, 10)
+	}
+	return fmt.Sprintf("%d/%d", cost, maxCost)
+}
@@ -0,0 +1,102 @@
+package root
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"karhub-beer-machine/internal/infrastructure/config"
+
+	"github.com/spf13/cobra"
+)
+
+type configChangeResponse struct {
+	Key     string `json:"key"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Applied bool   `json:"applied"`
+}
+
+type configReloadResponse struct {
+	Changes         []configChangeResponse `json:"changes"`
+	RestartRequired bool                   `json:"restartRequired"`
+}
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and reload the API configuration",
+	}
+
+	cmd.AddCommand(
+		newConfigPrintCommand(),
+		newConfigReloadCommand(),
+	)
+
+	return cmd
+}
+
+func newConfigPrintCommand() *cobra.Command {
+	var path string
+
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the configuration the API would start with, secrets redacted",
+		Long: "Load the config file, the environment and the defaults as the API does, " +
+			"validate them and print the result as YAML. Secrets are redacted.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadFile(path, os.LookupEnv)
+			if err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+
+			return cfg.Redact().WriteYAML(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "config", os.Getenv(config.FileEnv),
+		"YAML or JSON config file (defaults to $"+config.FileEnv+")")
+
+	return cmd
+}
+
+func newConfigReloadCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Make the running API reload its configuration",
+		Long: "Ask the running API to reload its config file, as SIGHUP does, and show what changed. " +
+			"An invalid configuration is rejected and the running one stays in place.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp configReloadResponse
+			if err := apiRequest(cmd, http.MethodPost, "/admin/config/reload", nil, &resp); err != nil {
+				return err
+			}
+
+			if len(resp.Changes) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Configuration reloaded, nothing changed")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "SETTING\tOLD\tNEW\tAPPLIED")
+			for _, c := range resp.Changes {
+				applied := "yes"
+				if !c.Applied {
+					applied = "on restart"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Key, c.Old, c.New, applied)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if resp.RestartRequired {
+				fmt.Fprintln(cmd.OutOrStdout(), "Some settings only take effect after a restart")
+			}
+			return nil
+		},
+	}
+}
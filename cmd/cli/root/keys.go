@@ -0,0 +1,161 @@
+package root
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type apiKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type apiKeySecretResponse struct {
+	apiKeyResponse
+	Secret string `json:"secret"`
+}
+
+func newKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Issue, list, revoke and rotate API keys (admin role)",
+	}
+
+	cmd.AddCommand(
+		newKeysCreateCommand(),
+		newKeysListCommand(),
+		newKeysRevokeCommand(),
+		newKeysRotateCommand(),
+	)
+
+	return cmd
+}
+
+func newKeysCreateCommand() *cobra.Command {
+	var (
+		name    string
+		owner   string
+		role    string
+		expires time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Issue an API key and print its secret, shown only this once",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := map[string]string{
+				"name":  name,
+				"owner": owner,
+				"role":  role,
+			}
+			if expires > 0 {
+				req["expiresIn"] = expires.String()
+			}
+
+			var resp apiKeySecretResponse
+			if err := apiRequest(cmd, http.MethodPost, "/admin/api-keys", req, &resp); err != nil {
+				return err
+			}
+
+			printSecret(cmd, "Created", resp)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "what the key is for")
+	cmd.Flags().StringVar(&owner, "owner", "", "person or team accountable for the key")
+	cmd.Flags().StringVar(&role, "role", "reader", "reader, operator or admin")
+	cmd.Flags().DurationVar(&expires, "expires", 0, "validity, e.g. 720h (0 = never expires)")
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func newKeysListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API keys, revoked ones included",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var keys []apiKeyResponse
+			if err := apiRequest(cmd, http.MethodGet, "/admin/api-keys", nil, &keys); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tOWNER\tROLE\tSTATUS\tCREATED\tEXPIRES\tLAST USED")
+			for _, k := range keys {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					k.ID, k.Name, k.Owner, k.Role, k.Status,
+					formatTime(&k.CreatedAt), formatTime(k.ExpiresAt), formatTime(k.LastUsedAt),
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newKeysRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key; it stops working at once",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/admin/api-keys/" + url.PathEscape(args[0])
+			if err := apiRequest(cmd, http.MethodDelete, path, nil, nil); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Revoked %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newKeysRotateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate <id>",
+		Short: "Replace the secret of an API key; the old one stops working at once",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp apiKeySecretResponse
+			path := "/admin/api-keys/" + url.PathEscape(args[0]) + "/rotate"
+			if err := apiRequest(cmd, http.MethodPost, path, nil, &resp); err != nil {
+				return err
+			}
+
+			printSecret(cmd, "Rotated", resp)
+			return nil
+		},
+	}
+}
+
+// printSecret shows a new secret; the API never returns it again.
+func printSecret(cmd *cobra.Command, action string, k apiKeySecretResponse) {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintf(out, "%s key %s (%s, %s)\n", action, k.ID, k.Name, k.Role)
+	fmt.Fprintf(out, "Secret: %s\n", k.Secret)
+	fmt.Fprintln(out, "Store it now: it will not be shown again.")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
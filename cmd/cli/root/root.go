@@ -1,6 +1,15 @@
 package root
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -12,14 +21,101 @@ func NewRootCommand() *cobra.Command {
 		Long:  "CLI for administrative and maintenance tasks of the Karhub Beer Machine",
 	}
 
+	cmd.PersistentFlags().String(
+		"token",
+		"",
+		"API key or JWT sent to the API (defaults to $KARHUB_TOKEN, then $ADMIN_TOKEN)",
+	)
+
 	cmd.AddCommand(
 		newSeedCommand(),
+		newCacheCommand(),
+		newTokenCommand(),
+		newKeysCommand(),
+		newConfigCommand(),
 	)
 
 	return cmd
 }
 
+// apiToken returns the credential from --token, KARHUB_TOKEN or, for
+// setups predating roles, ADMIN_TOKEN.
+func apiToken(cmd *cobra.Command) string {
+	if token, _ := cmd.Flags().GetString("token"); token != "" {
+		return token
+	}
+	if token := os.Getenv("KARHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("ADMIN_TOKEN")
+}
+
+// authorize sets the bearer credential on req, if there is one.
+func authorize(cmd *cobra.Command, req *http.Request) {
+	if token := apiToken(cmd); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
 // Execute runs the root command.
 func Execute() error {
 	return NewRootCommand().Execute()
 }
+
+// apiRequest calls the running API (API_BASE_URL) with the credential
+// from --token or the environment, sending body as JSON when it is not
+// nil and decoding the JSON response into out when it is not nil.
+func apiRequest(cmd *cobra.Command, method, path string, body, out any) error {
+	if apiToken(cmd) == "" {
+		return fmt.Errorf("credentials required: use --token or set KARHUB_TOKEN")
+	}
+
+	baseURL := os.Getenv("API_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	req, err := http.NewRequest(method, baseURL+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	authorize(cmd, req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%s %s: invalid credentials", method, path)
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s %s: your role is not allowed to do this", method, path)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%s %s: rate limited, retry in %ss", method, path, resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	case out == nil || resp.StatusCode == http.StatusNoContent:
+		return nil
+	default:
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
@@ -59,6 +59,7 @@ func newSeedCommand() *cobra.Command {
 				}
 
 				req.Header.Set("Content-Type", "application/json")
+				authorize(cmd, req)
 
 				resp, err := client.Do(req)
 				if err != nil {
@@ -66,6 +67,14 @@ func newSeedCommand() *cobra.Command {
 				}
 				defer resp.Body.Close()
 
+				if resp.StatusCode == http.StatusUnauthorized ||
+					resp.StatusCode == http.StatusForbidden {
+					return fmt.Errorf(
+						"failed to seed %s: the admin role is required (use --token or set KARHUB_TOKEN)",
+						s.Name,
+					)
+				}
+
 				// 201 = criado
 				// 400 = já existe / inválido → ignoramos (idempotente)
 				if resp.StatusCode != http.StatusCreated &&
@@ -0,0 +1,68 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/config"
+	"karhub-beer-machine/internal/infrastructure/jwt"
+
+	"github.com/spf13/cobra"
+)
+
+func newTokenCommand() *cobra.Command {
+	var (
+		subject  string
+		roleName string
+		ttl      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Issue a JWT signed with $JWT_SECRET",
+		Long: "Issue an HS256 JWT for the API, signed with the JWT secret and carrying " +
+			"the JWT issuer and audience when they are set, read from the API " +
+			"configuration ($" + config.FileEnv + ", $JWT_SECRET, $JWT_SECRET_FILE, ...)",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := auth.ParseRole(roleName)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadFile(os.Getenv(config.FileEnv), os.LookupEnv)
+			if err != nil {
+				return fmt.Errorf("invalid configuration:\n%w", err)
+			}
+			if cfg.Auth.JWTSecret == "" {
+				return fmt.Errorf("JWT_SECRET is not set")
+			}
+
+			issuer, err := jwt.NewHMACAuthenticator(
+				[]byte(cfg.Auth.JWTSecret),
+				jwt.WithIssuer(cfg.Auth.JWTIssuer),
+				jwt.WithAudience(cfg.Auth.JWTAudience),
+			)
+			if err != nil {
+				return err
+			}
+
+			token, err := issuer.Sign(subject, role, ttl)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&subject, "subject", "", "who the token is for")
+	cmd.Flags().StringVar(&roleName, "role", string(auth.RoleReader), "reader, operator or admin")
+	cmd.Flags().DurationVar(&ttl, "ttl", time.Hour, "how long the token is valid")
+	_ = cmd.MarkFlagRequired("subject")
+
+	return cmd
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"karhub-beer-machine/internal/application/logging"
+)
+
+// APIKeySecretPrefix starts every generated secret, so leaked keys are
+// easy to spot in logs and by secret scanners.
+const APIKeySecretPrefix = "kbm_"
+
+// LastUsedResolution is how stale APIKey.LastUsedAt may get: a key used
+// many times within it is only recorded once.
+const LastUsedResolution = time.Minute
+
+// APIKey is a long-lived credential. Only the SHA-256 hash of its secret
+// is stored, so a leaked store does not leak usable keys.
+type APIKey struct {
+	ID   string
+	Name string
+
+	// Owner is the person or team accountable for the key.
+	Owner string
+
+	// Role is the scope of the key: the most it is allowed to do.
+	Role Role
+
+	// Hash is the hex-encoded SHA-256 of the secret (see HashAPIKey).
+	Hash string
+
+	CreatedAt time.Time
+
+	// ExpiresAt is zero for keys that never expire.
+	ExpiresAt time.Time
+
+	// LastUsedAt is zero for keys never used, and precise to
+	// LastUsedResolution.
+	LastUsedAt time.Time
+
+	// RevokedAt is zero for keys that were not revoked. Revoked keys
+	// are kept, for the record.
+	RevokedAt time.Time
+}
+
+// Validate checks the key invariants.
+func (k APIKey) Validate() error {
+	if k.ID == "" || k.Name == "" || k.Hash == "" {
+		return ErrInvalidAPIKey
+	}
+
+	if _, err := ParseRole(string(k.Role)); err != nil {
+		return errors.Join(ErrInvalidAPIKey, err)
+	}
+
+	if !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(k.CreatedAt) {
+		return ErrInvalidAPIKey
+	}
+
+	return nil
+}
+
+// Revoked reports whether the key was revoked.
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Expired reports whether the key has expired at now.
+func (k APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 of an API key secret.
+//
+// Secrets are random and long, so a fast unsalted hash is enough: there
+// is nothing to brute-force, and looking keys up by hash does not leak
+// the secret through timing.
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKeySecret returns a random secret with 256 bits of entropy.
+func newAPIKeySecret() string {
+	return APIKeySecretPrefix + base64.RawURLEncoding.EncodeToString(randomBytes(32))
+}
+
+// newAPIKeyID returns a random, non-secret key identifier.
+func newAPIKeyID() string {
+	return hex.EncodeToString(randomBytes(8))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	// crypto/rand.Read never fails (and crashes the program if it would)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// APIKeyRepository defines the persistence contract for API keys.
+// This is an application-level port.
+type APIKeyRepository interface {
+	// Save creates or replaces an API key by ID.
+	Save(key APIKey) error
+
+	// FindByID retrieves a key by its identifier.
+	FindByID(id string) (APIKey, error)
+
+	// FindByHash retrieves the key whose secret hashes to hash.
+	FindByHash(hash string) (APIKey, error)
+
+	// FindAll retrieves every key, revoked ones included, oldest first.
+	FindAll() ([]APIKey, error)
+
+	// MarkUsed records that a key was used at the given time.
+	MarkUsed(id string, at time.Time) error
+}
+
+// APIKeyAuthenticator authenticates callers by API key.
+type APIKeyAuthenticator struct {
+	repositories []APIKeyRepository
+	now          func() time.Time
+}
+
+// NewAPIKeyAuthenticator creates a new APIKeyAuthenticator. Keys are
+// looked up in each repository in order, e.g. keys from configuration,
+// then keys issued at runtime.
+func NewAPIKeyAuthenticator(repositories ...APIKeyRepository) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		repositories: repositories,
+		now:          time.Now,
+	}
+}
+
+// Authenticate implements Authenticator. Any non-empty credential is
+// looked up, so this authenticator goes last in a chain. Revoked and
+// expired keys are invalid.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	if credential == "" {
+		return Principal{}, ErrInvalidCredential
+	}
+
+	hash := HashAPIKey(credential)
+	now := a.now()
+
+	for _, repository := range a.repositories {
+		key, err := repository.FindByHash(hash)
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+
+		if key.Revoked() || key.Expired(now) {
+			return Principal{}, ErrInvalidCredential
+		}
+
+		// Tracking usage is best effort: it must not lock callers out
+		if now.Sub(key.LastUsedAt) >= LastUsedResolution {
+			if err := repository.MarkUsed(key.ID, now); err != nil {
+				logging.FromContext(ctx).Warn("failed to record API key use", "key_id", key.ID, "error", err)
+			}
+		}
+
+		return Principal{Subject: key.Name, Role: key.Role, KeyID: key.ID}, nil
+	}
+
+	return Principal{}, ErrInvalidCredential
+}
@@ -0,0 +1,146 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+)
+
+func TestCreateAPIKeyUseCase(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   auth.CreateAPIKeyInput
+		wantErr bool
+	}{
+		{
+			name:  "reader key without expiry",
+			input: auth.CreateAPIKeyInput{Name: "dashboard", Owner: "data-team", Role: auth.RoleReader},
+		},
+		{
+			name:  "admin key with expiry",
+			input: auth.CreateAPIKeyInput{Name: "ci", Owner: "platform", Role: auth.RoleAdmin, TTL: 24 * time.Hour},
+		},
+		{
+			name:    "missing name",
+			input:   auth.CreateAPIKeyInput{Role: auth.RoleReader},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			input:   auth.CreateAPIKeyInput{Name: "ci", Role: "root"},
+			wantErr: true,
+		},
+		{
+			name:    "negative ttl",
+			input:   auth.CreateAPIKeyInput{Name: "ci", Role: auth.RoleReader, TTL: -time.Hour},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newAPIKeyRepositoryMock()
+
+			got, err := auth.NewCreateAPIKeyUseCase(repo).Execute(tt.input)
+
+			if tt.wantErr {
+				if !errors.Is(err, auth.ErrInvalidAPIKey) {
+					t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+				}
+				if repo.saveCalls != 0 {
+					t.Errorf("expected nothing to be saved")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasPrefix(got.Secret, auth.APIKeySecretPrefix) || len(got.Secret) < 40 {
+				t.Errorf("expected a long prefixed secret, got %q", got.Secret)
+			}
+
+			stored := repo.keys[got.Key.ID]
+			if stored.Hash != auth.HashAPIKey(got.Secret) || strings.Contains(stored.Hash, got.Secret) {
+				t.Errorf("expected only the hash of the secret to be stored, got %+v", stored)
+			}
+			if stored.Owner != tt.input.Owner || stored.CreatedAt.IsZero() {
+				t.Errorf("expected owner and creation time to be stored, got %+v", stored)
+			}
+			if (tt.input.TTL == 0) != stored.ExpiresAt.IsZero() {
+				t.Errorf("expected expiry only with a TTL, got %v", stored.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	repo := newAPIKeyRepositoryMock()
+	authenticator := auth.NewAPIKeyAuthenticator(repo)
+	ctx := context.Background()
+
+	created, err := auth.NewCreateAPIKeyUseCase(repo).Execute(auth.CreateAPIKeyInput{
+		Name: "ci", Owner: "platform", Role: auth.RoleAdmin,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := authenticator.Authenticate(ctx, created.Secret); err != nil {
+		t.Fatalf("expected the new key to work, got %v", err)
+	}
+
+	// Rotation
+	rotated, err := auth.NewRotateAPIKeyUseCase(repo).Execute(created.Key.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rotated.Key.ID != created.Key.ID || rotated.Secret == created.Secret {
+		t.Fatalf("expected the same key with a new secret, got %+v", rotated.Key)
+	}
+	if _, err := authenticator.Authenticate(ctx, created.Secret); !errors.Is(err, auth.ErrInvalidCredential) {
+		t.Errorf("expected the old secret to stop working, got %v", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, rotated.Secret); err != nil {
+		t.Errorf("expected the new secret to work, got %v", err)
+	}
+
+	// Revocation
+	revoked, err := auth.NewRevokeAPIKeyUseCase(repo).Execute(created.Key.ID)
+	if err != nil || !revoked.Revoked() {
+		t.Fatalf("expected the key to be revoked, got %+v (err=%v)", revoked, err)
+	}
+	if _, err := authenticator.Authenticate(ctx, rotated.Secret); !errors.Is(err, auth.ErrInvalidCredential) {
+		t.Errorf("expected a revoked key to stop working, got %v", err)
+	}
+
+	again, _ := auth.NewRevokeAPIKeyUseCase(repo).Execute(created.Key.ID)
+	if !again.RevokedAt.Equal(revoked.RevokedAt) {
+		t.Errorf("expected revoking twice to keep the first revocation time")
+	}
+
+	if _, err := auth.NewRotateAPIKeyUseCase(repo).Execute(created.Key.ID); !errors.Is(err, auth.ErrAPIKeyRevoked) {
+		t.Errorf("expected rotating a revoked key to fail, got %v", err)
+	}
+
+	// Revoked keys stay listed
+	keys, _ := auth.NewListAPIKeysUseCase(repo).Execute()
+	if len(keys) != 1 || !keys[0].Revoked() {
+		t.Errorf("expected the revoked key to be listed, got %+v", keys)
+	}
+
+	for _, uc := range []func(string) error{
+		func(id string) error { _, err := auth.NewRevokeAPIKeyUseCase(repo).Execute(id); return err },
+		func(id string) error { _, err := auth.NewRotateAPIKeyUseCase(repo).Execute(id); return err },
+	} {
+		if err := uc("missing"); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+			t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+		}
+	}
+}
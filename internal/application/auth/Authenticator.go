@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject names the caller: the API key name or the token subject.
+	Subject string
+	Role    Role
+
+	// KeyID identifies the API key of callers authenticated by one;
+	// unlike Subject, it is unique.
+	KeyID string
+}
+
+// Authenticator verifies a credential presented by a caller.
+// This is an application-level port; each credential kind is an adapter.
+//
+// Implementations return ErrUnsupportedCredential for credentials they
+// do not handle, and an error wrapping ErrInvalidCredential for the ones
+// they reject.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (Principal, error)
+}
+
+// ChainAuthenticator tries each authenticator in order and returns the
+// answer of the first one that supports the credential.
+type ChainAuthenticator struct {
+	authenticators []Authenticator
+}
+
+// NewChainAuthenticator creates a new ChainAuthenticator.
+func NewChainAuthenticator(authenticators ...Authenticator) *ChainAuthenticator {
+	return &ChainAuthenticator{
+		authenticators: authenticators,
+	}
+}
+
+// Authenticate implements Authenticator. A credential no authenticator
+// supports is invalid.
+func (c *ChainAuthenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	for _, authenticator := range c.authenticators {
+		principal, err := authenticator.Authenticate(ctx, credential)
+		if errors.Is(err, ErrUnsupportedCredential) {
+			continue
+		}
+		return principal, err
+	}
+
+	return Principal{}, ErrInvalidCredential
+}
@@ -0,0 +1,248 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+)
+
+// apiKeyRepositoryMock keeps keys by ID and finds them by hash with a scan.
+type apiKeyRepositoryMock struct {
+	keys      map[string]auth.APIKey
+	err       error
+	markUsed  int
+	saveCalls int
+}
+
+func newAPIKeyRepositoryMock() *apiKeyRepositoryMock {
+	return &apiKeyRepositoryMock{keys: map[string]auth.APIKey{}}
+}
+
+func (m *apiKeyRepositoryMock) Save(key auth.APIKey) error {
+	m.saveCalls++
+	m.keys[key.ID] = key
+	return nil
+}
+
+func (m *apiKeyRepositoryMock) FindByID(id string) (auth.APIKey, error) {
+	key, found := m.keys[id]
+	if !found {
+		return auth.APIKey{}, auth.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (m *apiKeyRepositoryMock) FindByHash(hash string) (auth.APIKey, error) {
+	if m.err != nil {
+		return auth.APIKey{}, m.err
+	}
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return auth.APIKey{}, auth.ErrAPIKeyNotFound
+}
+
+func (m *apiKeyRepositoryMock) FindAll() ([]auth.APIKey, error) {
+	keys := make([]auth.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (m *apiKeyRepositoryMock) MarkUsed(id string, at time.Time) error {
+	m.markUsed++
+	key := m.keys[id]
+	key.LastUsedAt = at
+	m.keys[id] = key
+	return nil
+}
+
+type authenticatorMock struct {
+	principal auth.Principal
+	err       error
+	calls     int
+}
+
+func (m *authenticatorMock) Authenticate(context.Context, string) (auth.Principal, error) {
+	m.calls++
+	return m.principal, m.err
+}
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     auth.Role
+		required auth.Role
+		want     bool
+	}{
+		{auth.RoleAdmin, auth.RoleAdmin, true},
+		{auth.RoleAdmin, auth.RoleReader, true},
+		{auth.RoleOperator, auth.RoleReader, true},
+		{auth.RoleOperator, auth.RoleAdmin, false},
+		{auth.RoleReader, auth.RoleOperator, false},
+		{auth.RoleNone, auth.RoleReader, false},
+		{auth.RoleNone, auth.RoleNone, true},
+		{auth.Role("root"), auth.RoleReader, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Errorf("%q.Allows(%q): expected %v, got %v", tt.role, tt.required, tt.want, got)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, name := range []string{"reader", "operator", "admin"} {
+		if role, err := auth.ParseRole(name); err != nil || string(role) != name {
+			t.Errorf("expected %q to parse, got %q (err=%v)", name, role, err)
+		}
+	}
+
+	for _, name := range []string{"", "Admin", "root"} {
+		if _, err := auth.ParseRole(name); !errors.Is(err, auth.ErrInvalidRole) {
+			t.Errorf("expected %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestAPIKeyAuthenticator_Authenticate(t *testing.T) {
+	repo := newAPIKeyRepositoryMock()
+	_ = repo.Save(auth.APIKey{ID: "1", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("s3cret")})
+	_ = repo.Save(auth.APIKey{ID: "2", Name: "old", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("revoked"), RevokedAt: time.Now()})
+	_ = repo.Save(auth.APIKey{ID: "3", Name: "temp", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("expired"), ExpiresAt: time.Now().Add(-time.Minute)})
+
+	tests := []struct {
+		name       string
+		credential string
+		repoErr    error
+		want       auth.Principal
+		wantErr    error
+	}{
+		{
+			name:       "known key",
+			credential: "s3cret",
+			want:       auth.Principal{Subject: "dashboard", Role: auth.RoleReader, KeyID: "1"},
+		},
+		{
+			name:       "unknown key",
+			credential: "guess",
+			wantErr:    auth.ErrInvalidCredential,
+		},
+		{
+			name:       "revoked key",
+			credential: "revoked",
+			wantErr:    auth.ErrInvalidCredential,
+		},
+		{
+			name:       "expired key",
+			credential: "expired",
+			wantErr:    auth.ErrInvalidCredential,
+		},
+		{
+			name:       "empty credential",
+			credential: "",
+			wantErr:    auth.ErrInvalidCredential,
+		},
+		{
+			name:       "repository failure",
+			credential: "s3cret",
+			repoErr:    errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.err = tt.repoErr
+
+			got, err := auth.NewAPIKeyAuthenticator(repo).Authenticate(context.Background(), tt.credential)
+
+			switch {
+			case tt.repoErr != nil:
+				if !errors.Is(err, tt.repoErr) {
+					t.Fatalf("expected the repository error, got %v", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("expected %+v, got %+v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestChainAuthenticator_FirstSupportingWins(t *testing.T) {
+	unsupported := &authenticatorMock{err: auth.ErrUnsupportedCredential}
+	rejecting := &authenticatorMock{err: auth.ErrInvalidCredential}
+	accepting := &authenticatorMock{principal: auth.Principal{Subject: "ci", Role: auth.RoleAdmin}}
+
+	_, err := auth.NewChainAuthenticator(unsupported, rejecting, accepting).Authenticate(context.Background(), "token")
+	if !errors.Is(err, auth.ErrInvalidCredential) {
+		t.Fatalf("expected the first supporting authenticator to reject, got %v", err)
+	}
+	if accepting.calls != 0 {
+		t.Errorf("expected later authenticators not to be tried")
+	}
+
+	got, err := auth.NewChainAuthenticator(unsupported, accepting).Authenticate(context.Background(), "token")
+	if err != nil || got.Subject != "ci" {
+		t.Fatalf("expected ci, got %+v (err=%v)", got, err)
+	}
+
+	if _, err := auth.NewChainAuthenticator(unsupported).Authenticate(context.Background(), "token"); !errors.Is(err, auth.ErrInvalidCredential) {
+		t.Errorf("expected an unsupported credential to be invalid, got %v", err)
+	}
+}
+
+func TestAPIKeyAuthenticator_TracksLastUse(t *testing.T) {
+	repo := newAPIKeyRepositoryMock()
+	_ = repo.Save(auth.APIKey{ID: "1", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("s3cret")})
+
+	authenticator := auth.NewAPIKeyAuthenticator(repo)
+
+	for range 3 {
+		if _, err := authenticator.Authenticate(context.Background(), "s3cret"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Uses within LastUsedResolution are recorded once
+	if repo.markUsed != 1 {
+		t.Errorf("expected last use to be recorded once, got %d", repo.markUsed)
+	}
+	if repo.keys["1"].LastUsedAt.IsZero() {
+		t.Errorf("expected last use to be set")
+	}
+}
+
+func TestAPIKeyAuthenticator_SeveralRepositories(t *testing.T) {
+	configured := newAPIKeyRepositoryMock()
+	_ = configured.Save(auth.APIKey{ID: "admin-token", Name: "admin-token", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("from-env")})
+
+	issued := newAPIKeyRepositoryMock()
+	_ = issued.Save(auth.APIKey{ID: "1", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("issued")})
+
+	authenticator := auth.NewAPIKeyAuthenticator(configured, issued)
+
+	for credential, want := range map[string]string{"from-env": "admin-token", "issued": "dashboard"} {
+		got, err := authenticator.Authenticate(context.Background(), credential)
+		if err != nil || got.Subject != want {
+			t.Errorf("expected %s, got %+v (err=%v)", want, got, err)
+		}
+	}
+}
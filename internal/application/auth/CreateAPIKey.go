@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"time"
+
+	"karhub-beer-machine/internal/application/metrics"
+)
+
+// CreateAPIKeyInput represents the input data to issue an API key.
+type CreateAPIKeyInput struct {
+	Name  string
+	Owner string
+	Role  Role
+
+	// TTL is how long the key is valid; zero means it never expires.
+	TTL time.Duration
+}
+
+// APIKeyWithSecret is an API key along with its plaintext secret. The
+// secret is only known when the key is created or rotated.
+type APIKeyWithSecret struct {
+	Key    APIKey
+	Secret string
+}
+
+// CreateAPIKeyUseCase issues API keys.
+type CreateAPIKeyUseCase struct {
+	repository APIKeyRepository
+	now        func() time.Time
+}
+
+// NewCreateAPIKeyUseCase creates a new CreateAPIKeyUseCase.
+func NewCreateAPIKeyUseCase(repository APIKeyRepository) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// Execute runs the use case.
+func (uc *CreateAPIKeyUseCase) Execute(input CreateAPIKeyInput) (APIKeyWithSecret, error) {
+	defer metrics.Time("create_api_key")()
+
+	if input.TTL < 0 {
+		return APIKeyWithSecret{}, ErrInvalidAPIKey
+	}
+
+	secret := newAPIKeySecret()
+	now := uc.now().UTC()
+
+	key := APIKey{
+		ID:        newAPIKeyID(),
+		Name:      input.Name,
+		Owner:     input.Owner,
+		Role:      input.Role,
+		Hash:      HashAPIKey(secret),
+		CreatedAt: now,
+	}
+	if input.TTL > 0 {
+		key.ExpiresAt = now.Add(input.TTL)
+	}
+
+	if err := key.Validate(); err != nil {
+		return APIKeyWithSecret{}, err
+	}
+
+	if err := uc.repository.Save(key); err != nil {
+		return APIKeyWithSecret{}, err
+	}
+
+	return APIKeyWithSecret{Key: key, Secret: secret}, nil
+}
@@ -0,0 +1,22 @@
+package auth
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// ListAPIKeysUseCase handles listing every API key, revoked ones included.
+type ListAPIKeysUseCase struct {
+	repository APIKeyRepository
+}
+
+// NewListAPIKeysUseCase creates a new ListAPIKeysUseCase.
+func NewListAPIKeysUseCase(repository APIKeyRepository) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{
+		repository: repository,
+	}
+}
+
+// Execute runs the use case.
+func (uc *ListAPIKeysUseCase) Execute() ([]APIKey, error) {
+	defer metrics.Time("list_api_keys")()
+
+	return uc.repository.FindAll()
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"time"
+
+	"karhub-beer-machine/internal/application/metrics"
+)
+
+// RevokeAPIKeyUseCase revokes API keys. Revoking is immediate and final;
+// revoking a key twice keeps the first revocation time.
+type RevokeAPIKeyUseCase struct {
+	repository APIKeyRepository
+	now        func() time.Time
+}
+
+// NewRevokeAPIKeyUseCase creates a new RevokeAPIKeyUseCase.
+func NewRevokeAPIKeyUseCase(repository APIKeyRepository) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// Execute runs the use case and returns the revoked key.
+func (uc *RevokeAPIKeyUseCase) Execute(id string) (APIKey, error) {
+	defer metrics.Time("revoke_api_key")()
+
+	key, err := uc.repository.FindByID(id)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	if key.Revoked() {
+		return key, nil
+	}
+
+	key.RevokedAt = uc.now().UTC()
+
+	if err := uc.repository.Save(key); err != nil {
+		return APIKey{}, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,50 @@
+package auth
+
+import "fmt"
+
+// Role is the access level granted to a caller. Roles are ordered: each
+// one can do everything the roles below it can.
+type Role string
+
+const (
+	// RoleNone grants nothing; it is the role of anonymous callers when
+	// every route requires credentials.
+	RoleNone Role = ""
+
+	// RoleReader can list styles and ask for recommendations.
+	RoleReader Role = "reader"
+
+	// RoleOperator can also inspect and purge caches.
+	RoleOperator Role = "operator"
+
+	// RoleAdmin can also change the catalog and its playlists.
+	RoleAdmin Role = "admin"
+)
+
+// ParseRole parses reader, operator or admin.
+func ParseRole(name string) (Role, error) {
+	switch role := Role(name); role {
+	case RoleReader, RoleOperator, RoleAdmin:
+		return role, nil
+	default:
+		return RoleNone, fmt.Errorf("%w: %q", ErrInvalidRole, name)
+	}
+}
+
+// Allows reports whether r grants at least the required role.
+func (r Role) Allows(required Role) bool {
+	return r.rank() >= required.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleReader:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// RotateAPIKeyUseCase replaces the secret of an API key. The key keeps
+// its ID, name, owner, role and expiry; the previous secret stops
+// working at once.
+type RotateAPIKeyUseCase struct {
+	repository APIKeyRepository
+}
+
+// NewRotateAPIKeyUseCase creates a new RotateAPIKeyUseCase.
+func NewRotateAPIKeyUseCase(repository APIKeyRepository) *RotateAPIKeyUseCase {
+	return &RotateAPIKeyUseCase{
+		repository: repository,
+	}
+}
+
+// Execute runs the use case.
+func (uc *RotateAPIKeyUseCase) Execute(id string) (APIKeyWithSecret, error) {
+	defer metrics.Time("rotate_api_key")()
+
+	key, err := uc.repository.FindByID(id)
+	if err != nil {
+		return APIKeyWithSecret{}, err
+	}
+
+	if key.Revoked() {
+		return APIKeyWithSecret{}, ErrAPIKeyRevoked
+	}
+
+	secret := newAPIKeySecret()
+	key.Hash = HashAPIKey(secret)
+
+	if err := uc.repository.Save(key); err != nil {
+		return APIKeyWithSecret{}, err
+	}
+
+	return APIKeyWithSecret{Key: key, Secret: secret}, nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "errors"
+
+// Application-level errors.
+
+var (
+	// ErrInvalidRole is returned when a role name is not one of reader,
+	// operator or admin.
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrInvalidCredential is returned when a credential is malformed,
+	// unknown, expired or carries a bad signature. Authenticators may
+	// wrap it with the reason, which must not be shown to clients.
+	ErrInvalidCredential = errors.New("invalid credential")
+
+	// ErrUnsupportedCredential is returned by an authenticator when a
+	// credential is not of the kind it verifies, so the next one in a
+	// chain gets a chance.
+	ErrUnsupportedCredential = errors.New("unsupported credential")
+
+	// ErrAPIKeyNotFound is returned when no API key matches.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidAPIKey is returned when an API key has no name, an
+	// invalid role or an expiry that is not in the future.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+
+	// ErrAPIKeyRevoked is returned when rotating a revoked API key.
+	ErrAPIKeyRevoked = errors.New("api key revoked")
+)
@@ -0,0 +1,46 @@
+package beer
+
+import "time"
+
+// AdministrableCache is a cache operators can inspect and purge.
+type AdministrableCache interface {
+	CacheStats() CacheStats
+
+	// CacheEntries returns the keys starting with prefix, sorted, up to limit.
+	CacheEntries(prefix string, limit int) []CacheEntry
+
+	// PurgeKey removes key and reports whether it was cached.
+	PurgeKey(key string) bool
+
+	// PurgePrefix removes every key starting with prefix ("" for all)
+	// and returns how many were removed.
+	PurgePrefix(prefix string) int
+}
+
+// CacheStats are the counters of a cache since it was created.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	HitRatio  float64
+	Evictions uint64
+	Keys      int
+	Cost      int64
+	MaxCost   int64
+	Errors    uint64
+}
+
+// CacheEntry is a cached key and its remaining time to live (zero means
+// no expiry).
+type CacheEntry struct {
+	Key string
+	TTL time.Duration
+}
+
+// findCache looks up a cache by name.
+func findCache(caches map[string]AdministrableCache, name string) (AdministrableCache, error) {
+	c, found := caches[name]
+	if !found {
+		return nil, ErrCacheNotFound
+	}
+	return c, nil
+}
@@ -0,0 +1,220 @@
+package beer_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+type administrableCacheMock struct {
+	stats   beer.CacheStats
+	entries []beer.CacheEntry
+
+	gotLimit int
+}
+
+func (m *administrableCacheMock) CacheStats() beer.CacheStats {
+	return m.stats
+}
+
+func (m *administrableCacheMock) CacheEntries(prefix string, limit int) []beer.CacheEntry {
+	m.gotLimit = limit
+
+	var entries []beer.CacheEntry
+	for _, e := range m.entries {
+		if strings.HasPrefix(e.Key, prefix) && len(entries) < limit {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (m *administrableCacheMock) PurgeKey(key string) bool {
+	for i, e := range m.entries {
+		if e.Key == key {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *administrableCacheMock) PurgePrefix(prefix string) int {
+	kept := m.entries[:0]
+	for _, e := range m.entries {
+		if !strings.HasPrefix(e.Key, prefix) {
+			kept = append(kept, e)
+		}
+	}
+
+	purged := len(m.entries) - len(kept)
+	m.entries = kept
+	return purged
+}
+
+func newAdministrableCacheMock() *administrableCacheMock {
+	return &administrableCacheMock{
+		stats: beer.CacheStats{Hits: 3, Misses: 1, Keys: 3},
+		entries: []beer.CacheEntry{
+			{Key: "spotify:playlist:IPA", TTL: time.Minute},
+			{Key: "spotify:playlist:IPA:notfound", TTL: time.Minute},
+			{Key: "spotify:playlist:Stout"},
+		},
+	}
+}
+
+/*
+	TESTS
+*/
+
+func TestGetCacheStatsUseCase(t *testing.T) {
+	playlists := newAdministrableCacheMock()
+
+	uc := beer.NewGetCacheStatsUseCase(map[string]beer.AdministrableCache{
+		"playlists": playlists,
+	})
+
+	stats := uc.Execute()
+
+	if len(stats) != 1 || stats["playlists"] != playlists.stats {
+		t.Errorf("expected the playlists stats, got %+v", stats)
+	}
+}
+
+func TestInspectCacheUseCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     beer.InspectCacheInput
+		wantErr   error
+		wantKeys  int
+		wantLimit int
+	}{
+		{
+			name:      "default limit",
+			input:     beer.InspectCacheInput{Cache: "playlists"},
+			wantKeys:  3,
+			wantLimit: beer.DefaultInspectLimit,
+		},
+		{
+			name:      "by prefix",
+			input:     beer.InspectCacheInput{Cache: "playlists", Prefix: "spotify:playlist:IPA"},
+			wantKeys:  2,
+			wantLimit: beer.DefaultInspectLimit,
+		},
+		{
+			name:      "limit is capped",
+			input:     beer.InspectCacheInput{Cache: "playlists", Limit: 1_000_000},
+			wantKeys:  3,
+			wantLimit: beer.MaxInspectLimit,
+		},
+		{
+			name:    "unknown cache",
+			input:   beer.InspectCacheInput{Cache: "recommendations"},
+			wantErr: beer.ErrCacheNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlists := newAdministrableCacheMock()
+
+			uc := beer.NewInspectCacheUseCase(map[string]beer.AdministrableCache{
+				"playlists": playlists,
+			})
+
+			entries, err := uc.Execute(tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(entries) != tt.wantKeys {
+				t.Errorf("expected %d entries, got %+v", tt.wantKeys, entries)
+			}
+
+			if playlists.gotLimit != tt.wantLimit {
+				t.Errorf("expected limit %d, got %d", tt.wantLimit, playlists.gotLimit)
+			}
+		})
+	}
+}
+
+func TestPurgeCacheUseCase(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      beer.PurgeCacheInput
+		wantErr    error
+		wantPurged int
+	}{
+		{
+			name:       "by key",
+			input:      beer.PurgeCacheInput{Cache: "playlists", Key: "spotify:playlist:Stout"},
+			wantPurged: 1,
+		},
+		{
+			name:       "missing key",
+			input:      beer.PurgeCacheInput{Cache: "playlists", Key: "spotify:playlist:Lager"},
+			wantPurged: 0,
+		},
+		{
+			name:       "by prefix",
+			input:      beer.PurgeCacheInput{Cache: "playlists", Prefix: "spotify:playlist:IPA"},
+			wantPurged: 2,
+		},
+		{
+			name:       "everything",
+			input:      beer.PurgeCacheInput{Cache: "playlists", All: true},
+			wantPurged: 3,
+		},
+		{
+			name:    "nothing selected",
+			input:   beer.PurgeCacheInput{Cache: "playlists"},
+			wantErr: beer.ErrInvalidCachePurge,
+		},
+		{
+			name:    "key and prefix",
+			input:   beer.PurgeCacheInput{Cache: "playlists", Key: "a", Prefix: "b"},
+			wantErr: beer.ErrInvalidCachePurge,
+		},
+		{
+			name:    "unknown cache",
+			input:   beer.PurgeCacheInput{Cache: "recommendations", All: true},
+			wantErr: beer.ErrCacheNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := beer.NewPurgeCacheUseCase(map[string]beer.AdministrableCache{
+				"playlists": newAdministrableCacheMock(),
+			})
+
+			output, err := uc.Execute(tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if output.Purged != tt.wantPurged {
+				t.Errorf("expected %d purged, got %d", tt.wantPurged, output.Purged)
+			}
+		})
+	}
+}
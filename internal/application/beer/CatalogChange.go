@@ -0,0 +1,48 @@
+package beer
+
+import "context"
+
+// CatalogChangeKind describes what happened to a beer style.
+type CatalogChangeKind string
+
+const (
+	CatalogStyleCreated CatalogChangeKind = "created"
+	CatalogStyleUpdated CatalogChangeKind = "updated"
+	CatalogStyleDeleted CatalogChangeKind = "deleted"
+)
+
+// CatalogChange is emitted after a beer style change has been persisted.
+type CatalogChange struct {
+	Kind    CatalogChangeKind
+	StyleID string
+
+	// Name is the style name after the change (empty on delete).
+	Name string
+
+	// PreviousName is the style name before the change
+	// (empty on create, and when the previous style is unknown).
+	PreviousName string
+}
+
+// Renamed reports whether the change gave the style a new name.
+func (c CatalogChange) Renamed() bool {
+	return c.PreviousName != "" && c.Name != "" && c.PreviousName != c.Name
+}
+
+// CatalogObserver is notified of catalog changes, e.g. to keep caches
+// keyed by style name consistent. Observers are called synchronously
+// by the use cases and must not block.
+type CatalogObserver interface {
+	OnCatalogChange(ctx context.Context, change CatalogChange)
+}
+
+// notifyCatalogChange fans a change out to every observer.
+func notifyCatalogChange(
+	ctx context.Context,
+	observers []CatalogObserver,
+	change CatalogChange,
+) {
+	for _, o := range observers {
+		o.OnCatalogChange(ctx, change)
+	}
+}
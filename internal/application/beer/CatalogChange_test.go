@@ -0,0 +1,148 @@
+package beer_test
+
+import (
+	"context"
+	"testing"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+type catalogObserverMock struct {
+	changes  []beer.CatalogChange
+	contexts []context.Context
+}
+
+func (m *catalogObserverMock) OnCatalogChange(ctx context.Context, change beer.CatalogChange) {
+	m.changes = append(m.changes, change)
+	m.contexts = append(m.contexts, ctx)
+}
+
+type requestKey struct{}
+
+/*
+	TESTS
+*/
+
+func TestCatalogUseCases_NotifyObservers(t *testing.T) {
+	existing := map[string]domain.BeerStyle{
+		"1": {ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+	}
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, repo domain.BeerStyleRepository, o beer.CatalogObserver) error
+		want beer.CatalogChange
+	}{
+		{
+			name: "create",
+			run: func(ctx context.Context, repo domain.BeerStyleRepository, o beer.CatalogObserver) error {
+				return beer.NewCreateBeerStyleUseCase(repo, o).Execute(ctx, beer.CreateBeerStyleInput{
+					ID: "2", Name: "Stout", MinTemp: -1, MaxTemp: 3,
+				})
+			},
+			want: beer.CatalogChange{
+				Kind:    beer.CatalogStyleCreated,
+				StyleID: "2",
+				Name:    "Stout",
+			},
+		},
+		{
+			name: "rename",
+			run: func(ctx context.Context, repo domain.BeerStyleRepository, o beer.CatalogObserver) error {
+				return beer.NewUpdateBeerStyleUseCase(repo, o).Execute(ctx, beer.UpdateBeerStyleInput{
+					ID: "1", Name: "Imperial IPA", MinTemp: -7, MaxTemp: 10,
+				})
+			},
+			want: beer.CatalogChange{
+				Kind:         beer.CatalogStyleUpdated,
+				StyleID:      "1",
+				Name:         "Imperial IPA",
+				PreviousName: "IPA",
+			},
+		},
+		{
+			name: "delete",
+			run: func(ctx context.Context, repo domain.BeerStyleRepository, o beer.CatalogObserver) error {
+				return beer.NewDeleteBeerStyleUseCase(repo, nil, o).Execute(ctx, "1")
+			},
+			want: beer.CatalogChange{
+				Kind:         beer.CatalogStyleDeleted,
+				StyleID:      "1",
+				PreviousName: "IPA",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &styleRepoByIDMock{byID: existing}
+			observer := &catalogObserverMock{}
+
+			ctx := context.WithValue(context.Background(), requestKey{}, tt.name)
+
+			if err := tt.run(ctx, repo, observer); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(observer.changes) != 1 {
+				t.Fatalf("expected 1 change, got %d", len(observer.changes))
+			}
+
+			if got := observer.changes[0]; got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+
+			if got := observer.contexts[0].Value(requestKey{}); got != tt.name {
+				t.Errorf("expected the caller's context, got value %v", got)
+			}
+		})
+	}
+}
+
+func TestCatalogUseCases_DoNotNotifyOnFailure(t *testing.T) {
+	observer := &catalogObserverMock{}
+
+	uc := beer.NewCreateBeerStyleUseCase(&beerStyleRepoMock{}, observer)
+
+	err := uc.Execute(context.Background(), beer.CreateBeerStyleInput{ID: "1", Name: "", MinTemp: 1, MaxTemp: -1})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if len(observer.changes) != 0 {
+		t.Errorf("expected no notification, got %+v", observer.changes)
+	}
+}
+
+func TestCatalogChange_Renamed(t *testing.T) {
+	tests := []struct {
+		name   string
+		change beer.CatalogChange
+		want   bool
+	}{
+		{
+			name:   "new name",
+			change: beer.CatalogChange{Name: "Imperial IPA", PreviousName: "IPA"},
+			want:   true,
+		},
+		{
+			name:   "same name",
+			change: beer.CatalogChange{Name: "IPA", PreviousName: "IPA"},
+			want:   false,
+		},
+		{
+			name:   "unknown previous name",
+			change: beer.CatalogChange{Name: "IPA"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Renamed(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package beer
 
 import (
+	"context"
+
+	"karhub-beer-machine/internal/application/metrics"
 	domain "karhub-beer-machine/internal/domain/beer"
 )
 
@@ -15,19 +18,25 @@ type CreateBeerStyleInput struct {
 // CreateBeerStyleUseCase handles creation of beer styles.
 type CreateBeerStyleUseCase struct {
 	repository domain.BeerStyleRepository
+	observers  []CatalogObserver
 }
 
 // NewCreateBeerStyleUseCase creates a new CreateBeerStyleUseCase.
+// observers are notified after the style is created.
 func NewCreateBeerStyleUseCase(
 	repository domain.BeerStyleRepository,
+	observers ...CatalogObserver,
 ) *CreateBeerStyleUseCase {
 	return &CreateBeerStyleUseCase{
 		repository: repository,
+		observers:  observers,
 	}
 }
 
-// Execute runs the use case.
-func (uc *CreateBeerStyleUseCase) Execute(input CreateBeerStyleInput) error {
+// Execute runs the use case. Observers are notified with ctx.
+func (uc *CreateBeerStyleUseCase) Execute(ctx context.Context, input CreateBeerStyleInput) error {
+	defer metrics.Time("create_beer_style")()
+
 	style, err := domain.NewBeerStyle(
 		input.ID,
 		input.Name,
@@ -38,5 +47,15 @@ func (uc *CreateBeerStyleUseCase) Execute(input CreateBeerStyleInput) error {
 		return err
 	}
 
-	return uc.repository.Create(style)
+	if err := uc.repository.Create(style); err != nil {
+		return err
+	}
+
+	notifyCatalogChange(ctx, uc.observers, CatalogChange{
+		Kind:    CatalogStyleCreated,
+		StyleID: style.ID,
+		Name:    style.Name,
+	})
+
+	return nil
 }
@@ -1,22 +1,64 @@
 package beer
 
-import domain "karhub-beer-machine/internal/domain/beer"
+import (
+	"context"
+	"errors"
+
+	"karhub-beer-machine/internal/application/metrics"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
 
 // DeleteBeerStyleUseCase handles deletion of beer styles.
 type DeleteBeerStyleUseCase struct {
 	repository domain.BeerStyleRepository
+	overrides  PlaylistOverrideRepository
+	observers  []CatalogObserver
 }
 
 // NewDeleteBeerStyleUseCase creates a new DeleteBeerStyleUseCase.
+// The playlist override of the style, if any, is deleted along with it
+// (overrides may be nil). observers are notified after the style is deleted.
 func NewDeleteBeerStyleUseCase(
 	repository domain.BeerStyleRepository,
+	overrides PlaylistOverrideRepository,
+	observers ...CatalogObserver,
 ) *DeleteBeerStyleUseCase {
 	return &DeleteBeerStyleUseCase{
 		repository: repository,
+		overrides:  overrides,
+		observers:  observers,
 	}
 }
 
-// Execute runs the use case.
-func (uc *DeleteBeerStyleUseCase) Execute(id string) error {
-	return uc.repository.Delete(id)
+// Execute runs the use case. Observers are notified with ctx.
+func (uc *DeleteBeerStyleUseCase) Execute(ctx context.Context, id string) error {
+	defer metrics.Time("delete_beer_style")()
+
+	// The name is needed to find what to invalidate once it is gone
+	var previousName string
+	if len(uc.observers) > 0 {
+		if current, err := uc.repository.FindByID(id); err == nil {
+			previousName = current.Name
+		}
+	}
+
+	if err := uc.repository.Delete(id); err != nil {
+		return err
+	}
+
+	// A style created later with the same ID must not inherit the override
+	if uc.overrides != nil {
+		err := uc.overrides.Delete(id)
+		if err != nil && !errors.Is(err, ErrPlaylistOverrideNotFound) {
+			return err
+		}
+	}
+
+	notifyCatalogChange(ctx, uc.observers, CatalogChange{
+		Kind:         CatalogStyleDeleted,
+		StyleID:      id,
+		PreviousName: previousName,
+	})
+
+	return nil
 }
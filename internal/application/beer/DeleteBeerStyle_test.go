@@ -1,6 +1,7 @@
 package beer_test
 
 import (
+	"context"
 	"testing"
 
 	"karhub-beer-machine/internal/application/beer"
@@ -22,13 +23,18 @@ func TestDeleteBeerStyleUseCase(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &beerStyleRepoMock{}
-			uc := beer.NewDeleteBeerStyleUseCase(repo)
+			overrides := &overrideRepoMock{}
+			uc := beer.NewDeleteBeerStyleUseCase(repo, overrides)
 
-			err := uc.Execute(tt.id)
+			err := uc.Execute(context.Background(), tt.id)
 
 			if tt.wantErr && err == nil {
 				t.Fatalf("expected error, got nil")
 			}
+
+			if !tt.wantErr && (len(overrides.deleted) != 1 || overrides.deleted[0] != tt.id) {
+				t.Errorf("expected override of %s to be deleted, got %v", tt.id, overrides.deleted)
+			}
 		})
 	}
 }
@@ -0,0 +1,24 @@
+package beer
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// DeletePlaylistOverrideUseCase removes the playlist pinned to a beer style.
+type DeletePlaylistOverrideUseCase struct {
+	overrides PlaylistOverrideRepository
+}
+
+// NewDeletePlaylistOverrideUseCase creates a new DeletePlaylistOverrideUseCase.
+func NewDeletePlaylistOverrideUseCase(
+	overrides PlaylistOverrideRepository,
+) *DeletePlaylistOverrideUseCase {
+	return &DeletePlaylistOverrideUseCase{
+		overrides: overrides,
+	}
+}
+
+// Execute runs the use case.
+func (uc *DeletePlaylistOverrideUseCase) Execute(styleID string) error {
+	defer metrics.Time("delete_playlist_override")()
+
+	return uc.overrides.Delete(styleID)
+}
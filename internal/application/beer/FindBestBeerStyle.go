@@ -2,32 +2,23 @@ package beer
 
 import (
 	"context"
+	"sync/atomic"
 
+	"karhub-beer-machine/internal/application/metrics"
+	"karhub-beer-machine/internal/application/tracing"
 	domain "karhub-beer-machine/internal/domain/beer"
 )
 
-// Playlist represents a simplified playlist model returned by the Spotify gateway.
-type Playlist struct {
-	Name   string
-	Tracks []Track
-}
-
-// Track represents a music track inside a playlist.
-type Track struct {
-	Name   string
-	Artist string
-	Link   string
-}
-
-// SpotifyGateway defines the contract to retrieve playlists by beer style name.
-// This is an application-level port.
-type SpotifyGateway interface {
-	FindPlaylistByStyle(ctx context.Context, styleName string) (Playlist, error)
-}
-
 // FindBestBeerStyleInput represents the input for the use case.
 type FindBestBeerStyleInput struct {
 	Temperature float64
+
+	// Provider optionally names the music provider to use (e.g. "spotify").
+	Provider string
+
+	// Tenant optionally identifies the caller, whose default provider
+	// is used when Provider is empty.
+	Tenant string
 }
 
 // FindBestBeerStyleOutput represents the output of the use case.
@@ -38,20 +29,86 @@ type FindBestBeerStyleOutput struct {
 
 // FindBestBeerStyleUseCase orchestrates the process of selecting the best beer style
 // for a given temperature and retrieving a related playlist.
+//
+// The selection strategy can be changed while it serves, with
+// SetSelectionStrategy.
 type FindBestBeerStyleUseCase struct {
 	repository domain.BeerStyleRepository
-	spotify    SpotifyGateway
+	music      MusicProvider
+	memo       *recommendationMemo
+	strategy   atomic.Pointer[string]
+}
+
+// FindBestBeerStyleOption customizes a FindBestBeerStyleUseCase.
+type FindBestBeerStyleOption func(*FindBestBeerStyleUseCase)
+
+// WithRecommendationMemo memoizes recommendations in cache, keyed by
+// strategy, temperature rounded to precision degrees (e.g. 0.1; 0 keeps
+// the exact temperature) and catalog version. It only applies to
+// repositories implementing domain.CatalogSnapshotter or
+// domain.CatalogVersioner.
+func WithRecommendationMemo(cache RecommendationCache, precision float64) FindBestBeerStyleOption {
+	return func(uc *FindBestBeerStyleUseCase) {
+		if cache == nil {
+			return
+		}
+
+		_, snapshots := uc.repository.(domain.CatalogSnapshotter)
+		_, versions := uc.repository.(domain.CatalogVersioner)
+		if !snapshots && !versions {
+			return
+		}
+
+		memo := &recommendationMemo{
+			cache: cache,
+		}
+		if precision > 0 {
+			memo.scale = 1 / precision
+		}
+
+		uc.memo = memo
+	}
+}
+
+// WithSelectionStrategy selects styles with the named strategy instead of
+// DefaultSelectionStrategy. The name must be valid (see
+// ValidateSelectionStrategy); unknown names keep the default.
+func WithSelectionStrategy(name string) FindBestBeerStyleOption {
+	return func(uc *FindBestBeerStyleUseCase) {
+		_ = uc.SetSelectionStrategy(name)
+	}
 }
 
 // NewFindBestBeerStyleUseCase creates a new instance of the use case.
 func NewFindBestBeerStyleUseCase(
 	repository domain.BeerStyleRepository,
-	spotify SpotifyGateway,
+	music MusicProvider,
+	opts ...FindBestBeerStyleOption,
 ) *FindBestBeerStyleUseCase {
-	return &FindBestBeerStyleUseCase{
+	uc := &FindBestBeerStyleUseCase{
 		repository: repository,
-		spotify:    spotify,
+		music:      music,
+	}
+	_ = uc.SetSelectionStrategy(DefaultSelectionStrategy)
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
+}
+
+// SetSelectionStrategy selects styles with the named strategy from now
+// on; requests in flight finish with the one they started with. Memoized
+// recommendations are keyed by strategy, so none of the previous one is
+// served. It returns ErrUnknownSelectionStrategy for unknown names.
+func (uc *FindBestBeerStyleUseCase) SetSelectionStrategy(name string) error {
+	if err := ValidateSelectionStrategy(name); err != nil {
+		return err
 	}
+
+	uc.strategy.Store(&name)
+	return nil
 }
 
 // Execute runs the use case.
@@ -59,23 +116,85 @@ func (uc *FindBestBeerStyleUseCase) Execute(
 	ctx context.Context,
 	input FindBestBeerStyleInput,
 ) (FindBestBeerStyleOutput, error) {
-	styles, err := uc.repository.FindAll()
-	if err != nil {
-		return FindBestBeerStyleOutput{}, err
-	}
+	defer metrics.Time("find_best_beer_style")()
 
-	bestStyle, err := domain.SelectBestStyle(styles, input.Temperature)
+	strategy := *uc.strategy.Load()
+
+	ctx, span := tracing.Start(ctx, "FindBestBeerStyleUseCase.Execute",
+		tracing.Float64("beer.temperature", input.Temperature),
+		tracing.String("beer.strategy", strategy))
+	defer span.End()
+
+	bestStyle, err := uc.selectBestStyle(ctx, strategy, input.Temperature)
 	if err != nil {
+		span.RecordError(err)
 		return FindBestBeerStyleOutput{}, err
 	}
+	span.SetAttributes(tracing.String("beer.style", bestStyle.Name))
+
+	ctx = WithMusicSelection(ctx, MusicSelection{
+		Provider: input.Provider,
+		Tenant:   input.Tenant,
+	})
+	ctx = WithStyleID(ctx, bestStyle.ID)
 
-	playlist, err := uc.spotify.FindPlaylistByStyle(ctx, bestStyle.Name)
+	playlist, err := uc.music.FindPlaylistByStyle(ctx, bestStyle.Name)
 	if err != nil {
+		span.RecordError(err)
 		return FindBestBeerStyleOutput{}, err
 	}
 
+	metrics.Default().CountRecommendation(bestStyle.Name)
+
 	return FindBestBeerStyleOutput{
 		BeerStyle: bestStyle.Name,
 		Playlist:  playlist,
 	}, nil
 }
+
+// selectBestStyle picks the best style with strategy, through the memo
+// when configured.
+// A catalog snapshot is read without copying it, and its version is the
+// memo version.
+//
+// The repository port takes no context, so its spans are started here,
+// around the calls.
+func (uc *FindBestBeerStyleUseCase) selectBestStyle(ctx context.Context, strategy string, temperature float64) (domain.BeerStyle, error) {
+	if snapshotter, ok := uc.repository.(domain.CatalogSnapshotter); ok {
+		_, span := tracing.Start(ctx, "BeerStyleRepository.Snapshot")
+		catalog := snapshotter.Snapshot()
+		span.SetAttributes(tracing.Int("catalog.styles", catalog.Len()))
+		span.End()
+
+		selectStyle := selectFromSnapshot(strategy, catalog)
+		if uc.memo == nil {
+			return selectStyle(temperature)
+		}
+		return uc.memo.recommend(strategy, catalog.Version(), temperature, selectStyle)
+	}
+
+	if uc.memo == nil {
+		return uc.selectFromCatalog(ctx, strategy, temperature)
+	}
+
+	// Read the version before the catalog: a write in between can only
+	// store a newer answer under an already outdated key
+	version := uc.repository.(domain.CatalogVersioner).CatalogVersion()
+	return uc.memo.recommend(strategy, version, temperature, func(temperature float64) (domain.BeerStyle, error) {
+		return uc.selectFromCatalog(ctx, strategy, temperature)
+	})
+}
+
+func (uc *FindBestBeerStyleUseCase) selectFromCatalog(ctx context.Context, strategy string, temperature float64) (domain.BeerStyle, error) {
+	_, span := tracing.Start(ctx, "BeerStyleRepository.FindAll")
+	styles, err := uc.repository.FindAll()
+	if err != nil {
+		span.RecordError(err)
+		span.End()
+		return domain.BeerStyle{}, err
+	}
+	span.SetAttributes(tracing.Int("catalog.styles", len(styles)))
+	span.End()
+
+	return selectFromStyles(strategy, styles, temperature)
+}
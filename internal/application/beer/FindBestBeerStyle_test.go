@@ -3,9 +3,13 @@ package beer_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/metrics"
+	"karhub-beer-machine/internal/application/tracing"
 	domain "karhub-beer-machine/internal/domain/beer"
 )
 
@@ -34,6 +38,21 @@ func (m *beerStyleRepositoryMock) FindAll() ([]domain.BeerStyle, error) {
 	return m.styles, m.err
 }
 
+// snapshotRepositoryMock publishes its styles as a catalog snapshot and
+// fails FindAll, so tests can tell which one was read.
+type snapshotRepositoryMock struct {
+	beerStyleRepositoryMock
+	catalog *domain.Catalog
+}
+
+func (m *snapshotRepositoryMock) FindAll() ([]domain.BeerStyle, error) {
+	return nil, errors.New("FindAll should not be called")
+}
+
+func (m *snapshotRepositoryMock) Snapshot() *domain.Catalog {
+	return m.catalog
+}
+
 type spotifyGatewayMock struct {
 	playlist beer.Playlist
 	err      error
@@ -46,6 +65,68 @@ func (m *spotifyGatewayMock) FindPlaylistByStyle(
 	return m.playlist, m.err
 }
 
+// spanRecordingGatewayMock remembers the span it was called within.
+type spanRecordingGatewayMock struct {
+	spotifyGatewayMock
+	span tracing.Span
+}
+
+func (m *spanRecordingGatewayMock) FindPlaylistByStyle(
+	ctx context.Context,
+	styleName string,
+) (beer.Playlist, error) {
+	m.span = tracing.SpanFromContext(ctx)
+	return m.spotifyGatewayMock.FindPlaylistByStyle(ctx, styleName)
+}
+
+type tracerMock struct {
+	mu    sync.Mutex
+	spans []*spanMock
+}
+
+func (m *tracerMock) Start(ctx context.Context, name string, _ tracing.SpanKind, _ ...tracing.Attribute) tracing.Span {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	span := &spanMock{name: name}
+	if parent, ok := tracing.SpanFromContext(ctx).(*spanMock); ok {
+		span.parent = parent
+	}
+	m.spans = append(m.spans, span)
+	return span
+}
+
+type spanMock struct {
+	name   string
+	parent *spanMock
+	err    error
+	ended  bool
+}
+
+func (s *spanMock) SpanContext() tracing.SpanContext   { return tracing.SpanContext{} }
+func (s *spanMock) SetName(name string)                { s.name = name }
+func (s *spanMock) SetAttributes(...tracing.Attribute) {}
+func (s *spanMock) RecordError(err error)              { s.err = err }
+func (s *spanMock) End()                               { s.ended = true }
+
+type recorderMock struct {
+	mu              sync.Mutex
+	useCases        []string
+	recommendations []string
+}
+
+func (m *recorderMock) ObserveUseCase(useCase string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.useCases = append(m.useCases, useCase)
+}
+
+func (m *recorderMock) CountRecommendation(style string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recommendations = append(m.recommendations, style)
+}
+
 /*
 	TESTS
 */
@@ -148,3 +229,229 @@ func TestFindBestBeerStyleUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestFindBestBeerStyleUseCase_ReadsCatalogSnapshot(t *testing.T) {
+	repo := &snapshotRepositoryMock{
+		catalog: domain.NewCatalog(7, []domain.BeerStyle{
+			{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+			{ID: "2", Name: "Dunkel", MinTemp: -8, MaxTemp: 2},
+		}),
+	}
+
+	tests := []struct {
+		name string
+		opts []beer.FindBestBeerStyleOption
+	}{
+		{name: "without memo"},
+		{name: "with memo", opts: []beer.FindBestBeerStyleOption{
+			beer.WithRecommendationMemo(newRecommendationCacheMock(), 0.1),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := beer.NewFindBestBeerStyleUseCase(repo, &spotifyGatewayMock{}, tt.opts...)
+
+			output, err := useCase.Execute(
+				context.Background(),
+				beer.FindBestBeerStyleInput{Temperature: -7},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if output.BeerStyle != "Dunkel" {
+				t.Errorf("expected beer style Dunkel, got %s", output.BeerStyle)
+			}
+		})
+	}
+}
+
+func TestFindBestBeerStyleUseCase_SelectionStrategy(t *testing.T) {
+	styles := []domain.BeerStyle{
+		{ID: "1", Name: "Dunkel", MinTemp: -8, MaxTemp: 2},
+		{ID: "2", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+		{ID: "3", Name: "Imperial Stout", MinTemp: -10, MaxTemp: 13},
+	}
+
+	repositories := map[string]func() domain.BeerStyleRepository{
+		"snapshot": func() domain.BeerStyleRepository {
+			return &snapshotRepositoryMock{catalog: domain.NewCatalog(1, styles)}
+		},
+		"find all": func() domain.BeerStyleRepository {
+			return &beerStyleRepositoryMock{styles: styles}
+		},
+	}
+
+	tests := []struct {
+		name        string
+		strategy    string
+		temperature float64
+		wantStyle   string
+		wantErr     error
+	}{
+		{
+			name:        "closest average ignores ranges",
+			strategy:    beer.ClosestAverageStrategy,
+			temperature: 11,
+			wantStyle:   "IPA",
+		},
+		{
+			name:        "containing range keeps styles containing the temperature",
+			strategy:    beer.ContainingRangeStrategy,
+			temperature: 11,
+			wantStyle:   "Imperial Stout",
+		},
+		{
+			name:        "containing range without match",
+			strategy:    beer.ContainingRangeStrategy,
+			temperature: 20,
+			wantErr:     domain.ErrNoStyleForTemperature,
+		},
+		{
+			name:        "unknown strategy keeps the default",
+			strategy:    "warmest",
+			temperature: 11,
+			wantStyle:   "IPA",
+		},
+	}
+
+	for repoName, newRepository := range repositories {
+		for _, tt := range tests {
+			t.Run(repoName+"/"+tt.name, func(t *testing.T) {
+				useCase := beer.NewFindBestBeerStyleUseCase(
+					newRepository(),
+					&spotifyGatewayMock{},
+					beer.WithSelectionStrategy(tt.strategy),
+				)
+
+				output, err := useCase.Execute(
+					context.Background(),
+					beer.FindBestBeerStyleInput{Temperature: tt.temperature},
+				)
+
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("expected %v, got %v", tt.wantErr, err)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if output.BeerStyle != tt.wantStyle {
+					t.Errorf("expected beer style %s, got %s", tt.wantStyle, output.BeerStyle)
+				}
+			})
+		}
+	}
+}
+
+func TestFindBestBeerStyleUseCase_RecordsMetrics(t *testing.T) {
+	recorder := &recorderMock{}
+	metrics.SetDefault(recorder)
+	t.Cleanup(func() { metrics.SetDefault(nil) })
+
+	repo := &beerStyleRepositoryMock{
+		styles: []domain.BeerStyle{{Name: "IPA", MinTemp: -7, MaxTemp: 10}},
+	}
+
+	_, _ = beer.NewFindBestBeerStyleUseCase(repo, &spotifyGatewayMock{}).
+		Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 0})
+
+	failing := &spotifyGatewayMock{err: errors.New("spotify down")}
+	_, _ = beer.NewFindBestBeerStyleUseCase(repo, failing).
+		Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 0})
+
+	if len(recorder.useCases) != 2 || recorder.useCases[0] != "find_best_beer_style" {
+		t.Fatalf("expected both executions timed, got %v", recorder.useCases)
+	}
+
+	// Failed recommendations were not served
+	if len(recorder.recommendations) != 1 || recorder.recommendations[0] != "IPA" {
+		t.Fatalf("expected one IPA recommendation, got %v", recorder.recommendations)
+	}
+}
+
+func TestFindBestBeerStyleUseCase_Traces(t *testing.T) {
+	tracer := &tracerMock{}
+	tracing.SetDefault(tracer)
+	t.Cleanup(func() { tracing.SetDefault(nil) })
+
+	repo := &beerStyleRepositoryMock{
+		styles: []domain.BeerStyle{{Name: "IPA", MinTemp: -7, MaxTemp: 10}},
+	}
+	gateway := &spanRecordingGatewayMock{
+		spotifyGatewayMock: spotifyGatewayMock{err: errors.New("spotify down")},
+	}
+
+	_, err := beer.NewFindBestBeerStyleUseCase(repo, gateway).
+		Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 0})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	execute, findAll := tracer.spans[0], tracer.spans[1]
+
+	if execute.name != "FindBestBeerStyleUseCase.Execute" || execute.parent != nil {
+		t.Errorf("unexpected root span %q", execute.name)
+	}
+	if findAll.name != "BeerStyleRepository.FindAll" || findAll.parent != execute {
+		t.Errorf("expected a repository span within the use case span, got %q", findAll.name)
+	}
+	if gateway.span != execute {
+		t.Errorf("expected the music provider to be called within the use case span")
+	}
+	if execute.err == nil {
+		t.Errorf("expected the provider error on the use case span")
+	}
+	if !execute.ended || !findAll.ended {
+		t.Errorf("expected every span ended")
+	}
+}
+
+func TestFindBestBeerStyleUseCase_SetSelectionStrategy(t *testing.T) {
+	repo := &versionedRepositoryMock{}
+	repo.styles = []domain.BeerStyle{
+		{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+		{ID: "2", Name: "Imperial Stout", MinTemp: -10, MaxTemp: 13},
+	}
+
+	useCase := beer.NewFindBestBeerStyleUseCase(
+		repo,
+		&spotifyGatewayMock{},
+		beer.WithRecommendationMemo(newRecommendationCacheMock(), 0.1),
+	)
+
+	recommend := func() string {
+		t.Helper()
+
+		output, err := useCase.Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 11})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return output.BeerStyle
+	}
+
+	if got := recommend(); got != "IPA" {
+		t.Fatalf("expected IPA with the default strategy, got %s", got)
+	}
+
+	if err := useCase.SetSelectionStrategy("warmest"); !errors.Is(err, beer.ErrUnknownSelectionStrategy) {
+		t.Fatalf("expected ErrUnknownSelectionStrategy, got %v", err)
+	}
+	if err := useCase.SetSelectionStrategy(beer.ContainingRangeStrategy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The memoized answer of the previous strategy must not be served
+	if got := recommend(); got != "Imperial Stout" {
+		t.Fatalf("expected Imperial Stout after the switch, got %s", got)
+	}
+}
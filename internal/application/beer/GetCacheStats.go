@@ -0,0 +1,28 @@
+package beer
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// GetCacheStatsUseCase reports the counters of every cache.
+type GetCacheStatsUseCase struct {
+	caches map[string]AdministrableCache
+}
+
+// NewGetCacheStatsUseCase creates a new GetCacheStatsUseCase.
+func NewGetCacheStatsUseCase(
+	caches map[string]AdministrableCache,
+) *GetCacheStatsUseCase {
+	return &GetCacheStatsUseCase{
+		caches: caches,
+	}
+}
+
+// Execute returns the stats of every cache, by name.
+func (uc *GetCacheStatsUseCase) Execute() map[string]CacheStats {
+	defer metrics.Time("get_cache_stats")()
+
+	stats := make(map[string]CacheStats, len(uc.caches))
+	for name, c := range uc.caches {
+		stats[name] = c.CacheStats()
+	}
+	return stats
+}
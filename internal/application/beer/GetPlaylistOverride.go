@@ -0,0 +1,24 @@
+package beer
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// GetPlaylistOverrideUseCase retrieves the playlist pinned to a beer style.
+type GetPlaylistOverrideUseCase struct {
+	overrides PlaylistOverrideRepository
+}
+
+// NewGetPlaylistOverrideUseCase creates a new GetPlaylistOverrideUseCase.
+func NewGetPlaylistOverrideUseCase(
+	overrides PlaylistOverrideRepository,
+) *GetPlaylistOverrideUseCase {
+	return &GetPlaylistOverrideUseCase{
+		overrides: overrides,
+	}
+}
+
+// Execute runs the use case.
+func (uc *GetPlaylistOverrideUseCase) Execute(styleID string) (PlaylistOverride, error) {
+	defer metrics.Time("get_playlist_override")()
+
+	return uc.overrides.FindByStyleID(styleID)
+}
@@ -0,0 +1,49 @@
+package beer
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// Bounds on the number of entries InspectCacheUseCase returns.
+const (
+	DefaultInspectLimit = 100
+	MaxInspectLimit     = 1000
+)
+
+// InspectCacheInput selects the entries to list.
+type InspectCacheInput struct {
+	Cache  string
+	Prefix string
+
+	// Limit defaults to DefaultInspectLimit and is capped at MaxInspectLimit.
+	Limit int
+}
+
+// InspectCacheUseCase lists the keys of a cache with their TTLs.
+type InspectCacheUseCase struct {
+	caches map[string]AdministrableCache
+}
+
+// NewInspectCacheUseCase creates a new InspectCacheUseCase.
+func NewInspectCacheUseCase(
+	caches map[string]AdministrableCache,
+) *InspectCacheUseCase {
+	return &InspectCacheUseCase{
+		caches: caches,
+	}
+}
+
+// Execute runs the use case.
+func (uc *InspectCacheUseCase) Execute(input InspectCacheInput) ([]CacheEntry, error) {
+	defer metrics.Time("inspect_cache")()
+
+	c, err := findCache(uc.caches, input.Cache)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultInspectLimit
+	}
+
+	return c.CacheEntries(input.Prefix, min(limit, MaxInspectLimit)), nil
+}
@@ -1,6 +1,9 @@
 package beer
 
-import domain "karhub-beer-machine/internal/domain/beer"
+import (
+	"karhub-beer-machine/internal/application/metrics"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
 
 // ListBeerStylesUseCase handles listing all beer styles.
 type ListBeerStylesUseCase struct {
@@ -18,5 +21,7 @@ func NewListBeerStylesUseCase(
 
 // Execute runs the use case.
 func (uc *ListBeerStylesUseCase) Execute() ([]domain.BeerStyle, error) {
+	defer metrics.Time("list_beer_styles")()
+
 	return uc.repository.FindAll()
 }
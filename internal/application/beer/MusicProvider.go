@@ -0,0 +1,203 @@
+package beer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Playlist represents a simplified playlist model returned by a music provider.
+type Playlist struct {
+	Name        string
+	Description string
+	Owner       string
+	Link        string
+	Images      []Image
+
+	// Provider names the music provider that supplied the playlist.
+	Provider string
+
+	// Freshness tells whether a cached playlist is current; it is empty
+	// for playlists that did not go through a cache.
+	Freshness Freshness
+
+	// FetchedAt is when the playlist was loaded from the provider.
+	FetchedAt time.Time
+
+	// TotalTracks is the number of tracks in the source playlist,
+	// which may be larger than len(Tracks).
+	TotalTracks int
+	Tracks      []Track
+}
+
+// Track represents a music track inside a playlist.
+type Track struct {
+	Name string
+
+	// Artist is the first (main) artist, kept for simple displays.
+	Artist  string
+	Artists []string
+
+	Album       string
+	AlbumImages []Image
+	Duration    time.Duration
+	Explicit    bool
+	PreviewURL  string
+	Link        string
+}
+
+// Freshness describes how current a cached playlist is.
+type Freshness string
+
+const (
+	// FreshnessFresh is served within the soft TTL, or straight from the provider.
+	FreshnessFresh Freshness = "fresh"
+
+	// FreshnessStale is served between the soft and hard TTLs while a
+	// background refresh is in progress.
+	FreshnessStale Freshness = "stale"
+
+	// FreshnessStaleIfError is served past the hard TTL because the
+	// provider failed.
+	FreshnessStaleIfError Freshness = "stale-if-error"
+)
+
+// Image represents a cover image in one of the sizes offered by the provider.
+type Image struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+// MusicProvider defines the contract to retrieve playlists by beer style name.
+// This is an application-level port; each streaming service is an adapter.
+// When nothing matches, adapters return an error wrapping ErrMusicNotFound.
+type MusicProvider interface {
+	FindPlaylistByStyle(ctx context.Context, styleName string) (Playlist, error)
+}
+
+// SpotifyGateway is the former name of MusicProvider.
+//
+// Deprecated: use MusicProvider.
+type SpotifyGateway = MusicProvider
+
+// MusicSelection carries the caller's provider preference through the context.
+type MusicSelection struct {
+	Provider string
+	Tenant   string
+}
+
+type musicSelectionKey struct{}
+
+// WithMusicSelection returns a context carrying the provider selection.
+func WithMusicSelection(ctx context.Context, sel MusicSelection) context.Context {
+	return context.WithValue(ctx, musicSelectionKey{}, sel)
+}
+
+// MusicSelectionFromContext returns the provider selection stored in ctx.
+func MusicSelectionFromContext(ctx context.Context) MusicSelection {
+	sel, _ := ctx.Value(musicSelectionKey{}).(MusicSelection)
+	return sel
+}
+
+// MusicProviderRouter is a MusicProvider that delegates to one of several
+// named providers. The provider is picked, in order, from the request
+// selection, the tenant's default, and finally the router default.
+type MusicProviderRouter struct {
+	providers map[string]MusicProvider
+	defaults  atomic.Pointer[musicDefaults]
+}
+
+// musicDefaults are the router and tenant defaults, swapped as a whole.
+type musicDefaults struct {
+	provider string
+	tenants  map[string]string
+}
+
+// NewMusicProviderRouter creates a router over the given named providers.
+// tenants maps a tenant identifier to its default provider name.
+func NewMusicProviderRouter(
+	providers map[string]MusicProvider,
+	defaultProvider string,
+	tenants map[string]string,
+) (*MusicProviderRouter, error) {
+	r := &MusicProviderRouter{providers: providers}
+
+	if err := r.SetDefaults(defaultProvider, tenants); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// CheckDefaults reports whether SetDefaults would accept the defaults:
+// every provider they name must be registered.
+func (r *MusicProviderRouter) CheckDefaults(defaultProvider string, tenants map[string]string) error {
+	if _, found := r.providers[defaultProvider]; !found {
+		return ErrUnknownMusicProvider
+	}
+
+	for _, name := range tenants {
+		if _, found := r.providers[name]; !found {
+			return ErrUnknownMusicProvider
+		}
+	}
+
+	return nil
+}
+
+// SetDefaults replaces the router and tenant defaults at once, so
+// requests in flight see either the old or the new ones. tenants must
+// not be modified afterwards.
+func (r *MusicProviderRouter) SetDefaults(defaultProvider string, tenants map[string]string) error {
+	if err := r.CheckDefaults(defaultProvider, tenants); err != nil {
+		return err
+	}
+
+	r.defaults.Store(&musicDefaults{provider: defaultProvider, tenants: tenants})
+	return nil
+}
+
+// FindPlaylistByStyle delegates to the selected provider.
+func (r *MusicProviderRouter) FindPlaylistByStyle(
+	ctx context.Context,
+	styleName string,
+) (Playlist, error) {
+	name, provider, err := r.Resolve(MusicSelectionFromContext(ctx))
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	playlist, err := provider.FindPlaylistByStyle(ctx, styleName)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	if playlist.Provider == "" {
+		playlist.Provider = name
+	}
+
+	return playlist, nil
+}
+
+// Resolve returns the provider matching the selection.
+func (r *MusicProviderRouter) Resolve(
+	sel MusicSelection,
+) (string, MusicProvider, error) {
+	defaults := r.defaults.Load()
+
+	name := sel.Provider
+	if name == "" {
+		name = defaults.tenants[sel.Tenant]
+	}
+	if name == "" {
+		name = defaults.provider
+	}
+
+	provider, found := r.providers[name]
+	if !found {
+		return "", nil, ErrUnknownMusicProvider
+	}
+
+	return name, provider, nil
+}
@@ -0,0 +1,215 @@
+package beer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+type namedProviderMock struct {
+	playlist beer.Playlist
+	calls    int
+}
+
+func (m *namedProviderMock) FindPlaylistByStyle(
+	_ context.Context,
+	_ string,
+) (beer.Playlist, error) {
+	m.calls++
+	return m.playlist, nil
+}
+
+func TestNewMusicProviderRouter(t *testing.T) {
+	providers := map[string]beer.MusicProvider{
+		"spotify": &namedProviderMock{},
+	}
+
+	tests := []struct {
+		name            string
+		defaultProvider string
+		tenants         map[string]string
+		wantErr         bool
+	}{
+		{
+			name:            "valid configuration",
+			defaultProvider: "spotify",
+			tenants:         map[string]string{"acme": "spotify"},
+			wantErr:         false,
+		},
+		{
+			name:            "unknown default provider",
+			defaultProvider: "deezer",
+			wantErr:         true,
+		},
+		{
+			name:            "unknown tenant provider",
+			defaultProvider: "spotify",
+			tenants:         map[string]string{"acme": "deezer"},
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := beer.NewMusicProviderRouter(providers, tt.defaultProvider, tt.tenants)
+
+			if tt.wantErr && !errors.Is(err, beer.ErrUnknownMusicProvider) {
+				t.Fatalf("expected ErrUnknownMusicProvider, got %v", err)
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestMusicProviderRouter_FindPlaylistByStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		selection    beer.MusicSelection
+		wantProvider string
+		wantErr      bool
+	}{
+		{
+			name:         "default provider",
+			selection:    beer.MusicSelection{},
+			wantProvider: "spotify",
+		},
+		{
+			name:         "provider requested explicitly",
+			selection:    beer.MusicSelection{Provider: "local"},
+			wantProvider: "local",
+		},
+		{
+			name:         "tenant default provider",
+			selection:    beer.MusicSelection{Tenant: "acme"},
+			wantProvider: "local",
+		},
+		{
+			name:         "request overrides tenant default",
+			selection:    beer.MusicSelection{Provider: "spotify", Tenant: "acme"},
+			wantProvider: "spotify",
+		},
+		{
+			name:      "unknown provider",
+			selection: beer.MusicSelection{Provider: "deezer"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotify := &namedProviderMock{playlist: beer.Playlist{Name: "IPA Hits"}}
+			local := &namedProviderMock{playlist: beer.Playlist{Name: "IPA Local", Provider: "local"}}
+
+			router, err := beer.NewMusicProviderRouter(
+				map[string]beer.MusicProvider{"spotify": spotify, "local": local},
+				"spotify",
+				map[string]string{"acme": "local"},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx := beer.WithMusicSelection(context.Background(), tt.selection)
+			playlist, err := router.FindPlaylistByStyle(ctx, "IPA")
+
+			if tt.wantErr {
+				if !errors.Is(err, beer.ErrUnknownMusicProvider) {
+					t.Fatalf("expected ErrUnknownMusicProvider, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if playlist.Provider != tt.wantProvider {
+				t.Errorf("expected provider %s, got %s", tt.wantProvider, playlist.Provider)
+			}
+		})
+	}
+}
+
+func TestFindBestBeerStyleUseCase_PassesSelection(t *testing.T) {
+	local := &namedProviderMock{playlist: beer.Playlist{Name: "IPA Local"}}
+
+	router, err := beer.NewMusicProviderRouter(
+		map[string]beer.MusicProvider{
+			"spotify": &namedProviderMock{},
+			"local":   local,
+		},
+		"spotify",
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := &beerStyleRepositoryMock{
+		styles: []domain.BeerStyle{{Name: "IPA", MinTemp: -7, MaxTemp: 10}},
+	}
+
+	uc := beer.NewFindBestBeerStyleUseCase(repo, router)
+
+	out, err := uc.Execute(context.Background(), beer.FindBestBeerStyleInput{
+		Temperature: 0,
+		Provider:    "local",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if local.calls != 1 || out.Playlist.Provider != "local" {
+		t.Errorf("expected local provider to be used, got %+v", out.Playlist)
+	}
+}
+
+func TestMusicProviderRouter_SetDefaults(t *testing.T) {
+	router, err := beer.NewMusicProviderRouter(
+		map[string]beer.MusicProvider{
+			"spotify": &namedProviderMock{},
+			"local":   &namedProviderMock{},
+		},
+		"spotify",
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := router.SetDefaults("deezer", nil); !errors.Is(err, beer.ErrUnknownMusicProvider) {
+		t.Fatalf("expected ErrUnknownMusicProvider, got %v", err)
+	}
+	if name, _, _ := router.Resolve(beer.MusicSelection{}); name != "spotify" {
+		t.Fatalf("expected rejected defaults to keep spotify, got %s", name)
+	}
+
+	if err := router.SetDefaults("local", map[string]string{"acme": "spotify"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		selection    beer.MusicSelection
+		wantProvider string
+	}{
+		{selection: beer.MusicSelection{}, wantProvider: "local"},
+		{selection: beer.MusicSelection{Tenant: "acme"}, wantProvider: "spotify"},
+		{selection: beer.MusicSelection{Provider: "spotify"}, wantProvider: "spotify"},
+	}
+
+	for _, tt := range tests {
+		name, _, err := router.Resolve(tt.selection)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != tt.wantProvider {
+			t.Errorf("%+v: expected provider %s, got %s", tt.selection, tt.wantProvider, name)
+		}
+	}
+}
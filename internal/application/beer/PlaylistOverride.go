@@ -0,0 +1,135 @@
+package beer
+
+import (
+	"context"
+)
+
+// CuratedProviderName identifies playlists curated by an administrator.
+const CuratedProviderName = "curated"
+
+// PlaylistOverride pins a playlist to a beer style, bypassing search.
+// It is keyed by style ID, so it survives style renames.
+//
+// Either Playlist (a curated name and track list) or ExternalURI
+// (e.g. "spotify:playlist:37i9dQZF1DX...") must be set.
+type PlaylistOverride struct {
+	StyleID     string
+	Playlist    Playlist
+	ExternalURI string
+}
+
+// Validate checks the override invariants.
+func (o PlaylistOverride) Validate() error {
+	if o.StyleID == "" {
+		return ErrInvalidPlaylistOverride
+	}
+
+	hasCurated := o.Playlist.Name != ""
+	hasExternal := o.ExternalURI != ""
+
+	if hasCurated == hasExternal {
+		return ErrInvalidPlaylistOverride
+	}
+
+	return nil
+}
+
+// PlaylistOverrideRepository defines the persistence contract for
+// playlist overrides. This is an application-level port.
+type PlaylistOverrideRepository interface {
+	// Save creates or replaces the override of a style.
+	Save(override PlaylistOverride) error
+
+	// Delete removes the override of a style.
+	Delete(styleID string) error
+
+	// FindByStyleID retrieves the override of a style.
+	FindByStyleID(styleID string) (PlaylistOverride, error)
+}
+
+// PlaylistURIResolver is implemented by music providers able to load a
+// playlist by its external URI.
+type PlaylistURIResolver interface {
+	FindPlaylistByURI(ctx context.Context, uri string) (Playlist, error)
+}
+
+type styleIDKey struct{}
+
+// WithStyleID returns a context carrying the ID of the beer style
+// whose playlist is being looked up.
+func WithStyleID(ctx context.Context, styleID string) context.Context {
+	return context.WithValue(ctx, styleIDKey{}, styleID)
+}
+
+// StyleIDFromContext returns the beer style ID stored in ctx.
+func StyleIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(styleIDKey{}).(string)
+	return id
+}
+
+// OverridingMusicProvider decorates a MusicProvider, serving the playlist
+// pinned to the style (if any) before falling back to search.
+type OverridingMusicProvider struct {
+	provider  MusicProvider
+	overrides PlaylistOverrideRepository
+	resolver  PlaylistURIResolver
+}
+
+// NewOverridingMusicProvider creates the decorator. resolver may be nil,
+// in which case overrides pointing to an external URI are ignored.
+func NewOverridingMusicProvider(
+	provider MusicProvider,
+	overrides PlaylistOverrideRepository,
+	resolver PlaylistURIResolver,
+) *OverridingMusicProvider {
+	return &OverridingMusicProvider{
+		provider:  provider,
+		overrides: overrides,
+		resolver:  resolver,
+	}
+}
+
+// FindPlaylistByStyle returns the override of the style in ctx, or the
+// playlist found by the wrapped provider when there is none.
+func (p *OverridingMusicProvider) FindPlaylistByStyle(
+	ctx context.Context,
+	styleName string,
+) (Playlist, error) {
+	if playlist, found := p.findOverride(ctx); found {
+		return playlist, nil
+	}
+
+	return p.provider.FindPlaylistByStyle(ctx, styleName)
+}
+
+func (p *OverridingMusicProvider) findOverride(ctx context.Context) (Playlist, bool) {
+	styleID := StyleIDFromContext(ctx)
+	if styleID == "" {
+		return Playlist{}, false
+	}
+
+	override, err := p.overrides.FindByStyleID(styleID)
+	if err != nil {
+		return Playlist{}, false
+	}
+
+	if override.ExternalURI == "" {
+		playlist := override.Playlist
+		if playlist.Provider == "" {
+			playlist.Provider = CuratedProviderName
+		}
+		return playlist, true
+	}
+
+	if p.resolver == nil {
+		return Playlist{}, false
+	}
+
+	// An unreachable pinned playlist falls back to search
+	playlist, err := p.resolver.FindPlaylistByURI(ctx, override.ExternalURI)
+	if err != nil {
+		return Playlist{}, false
+	}
+
+	return playlist, true
+}
@@ -0,0 +1,286 @@
+package beer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+type overrideRepoMock struct {
+	overrides map[string]beer.PlaylistOverride
+	saved     []beer.PlaylistOverride
+	deleted   []string
+}
+
+func (m *overrideRepoMock) Save(o beer.PlaylistOverride) error {
+	m.saved = append(m.saved, o)
+	return nil
+}
+
+func (m *overrideRepoMock) Delete(styleID string) error {
+	m.deleted = append(m.deleted, styleID)
+	return nil
+}
+
+func (m *overrideRepoMock) FindByStyleID(styleID string) (beer.PlaylistOverride, error) {
+	o, found := m.overrides[styleID]
+	if !found {
+		return beer.PlaylistOverride{}, beer.ErrPlaylistOverrideNotFound
+	}
+	return o, nil
+}
+
+type uriResolverMock struct {
+	playlist beer.Playlist
+	err      error
+}
+
+func (m *uriResolverMock) FindPlaylistByURI(
+	_ context.Context,
+	_ string,
+) (beer.Playlist, error) {
+	return m.playlist, m.err
+}
+
+type styleRepoByIDMock struct {
+	beerStyleRepositoryMock
+	byID map[string]domain.BeerStyle
+}
+
+func (m *styleRepoByIDMock) FindByID(id string) (domain.BeerStyle, error) {
+	s, found := m.byID[id]
+	if !found {
+		return domain.BeerStyle{}, domain.ErrBeerStyleNotFound
+	}
+	return s, nil
+}
+
+func TestPlaylistOverride_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		override beer.PlaylistOverride
+		wantErr  bool
+	}{
+		{
+			name: "curated playlist",
+			override: beer.PlaylistOverride{
+				StyleID:  "1",
+				Playlist: beer.Playlist{Name: "Staff Picks"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "external uri",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				ExternalURI: "spotify:playlist:abc",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "neither curated nor external",
+			override: beer.PlaylistOverride{StyleID: "1"},
+			wantErr:  true,
+		},
+		{
+			name: "both curated and external",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				Playlist:    beer.Playlist{Name: "Staff Picks"},
+				ExternalURI: "spotify:playlist:abc",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing style id",
+			override: beer.PlaylistOverride{
+				Playlist: beer.Playlist{Name: "Staff Picks"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.override.Validate()
+
+			if tt.wantErr && !errors.Is(err, beer.ErrInvalidPlaylistOverride) {
+				t.Fatalf("expected ErrInvalidPlaylistOverride, got %v", err)
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetPlaylistOverrideUseCase(t *testing.T) {
+	styles := &styleRepoByIDMock{
+		byID: map[string]domain.BeerStyle{"1": {ID: "1", Name: "IPA"}},
+	}
+
+	tests := []struct {
+		name      string
+		override  beer.PlaylistOverride
+		wantErrIs error
+	}{
+		{
+			name: "existing style",
+			override: beer.PlaylistOverride{
+				StyleID:  "1",
+				Playlist: beer.Playlist{Name: "Staff Picks"},
+			},
+		},
+		{
+			name: "unknown style",
+			override: beer.PlaylistOverride{
+				StyleID:  "missing",
+				Playlist: beer.Playlist{Name: "Staff Picks"},
+			},
+			wantErrIs: domain.ErrBeerStyleNotFound,
+		},
+		{
+			name: "spotify uri",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				ExternalURI: "spotify:playlist:37i9dQZF1DX",
+			},
+		},
+		{
+			name: "open.spotify.com link",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				ExternalURI: "https://open.spotify.com/playlist/37i9dQZF1DX",
+			},
+		},
+		{
+			name: "non playlist uri",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				ExternalURI: "spotify:album:37i9dQZF1DX",
+			},
+			wantErrIs: beer.ErrInvalidPlaylistURI,
+		},
+		{
+			name: "foreign link",
+			override: beer.PlaylistOverride{
+				StyleID:     "1",
+				ExternalURI: "https://example.com/playlist/37i9dQZF1DX",
+			},
+			wantErrIs: beer.ErrInvalidPlaylistURI,
+		},
+		{
+			name:      "invalid override",
+			override:  beer.PlaylistOverride{StyleID: "1"},
+			wantErrIs: beer.ErrInvalidPlaylistOverride,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := &overrideRepoMock{}
+			uc := beer.NewSetPlaylistOverrideUseCase(styles, overrides)
+
+			err := uc.Execute(tt.override)
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected %v, got %v", tt.wantErrIs, err)
+				}
+				if len(overrides.saved) != 0 {
+					t.Errorf("expected nothing to be saved")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestOverridingMusicProvider_FindPlaylistByStyle(t *testing.T) {
+	overrides := &overrideRepoMock{
+		overrides: map[string]beer.PlaylistOverride{
+			"curated": {
+				StyleID:  "curated",
+				Playlist: beer.Playlist{Name: "Staff Picks"},
+			},
+			"external": {
+				StyleID:     "external",
+				ExternalURI: "spotify:playlist:abc",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		styleID      string
+		resolver     beer.PlaylistURIResolver
+		wantPlaylist string
+		wantProvider string
+	}{
+		{
+			name:         "no override falls back to search",
+			styleID:      "plain",
+			resolver:     &uriResolverMock{},
+			wantPlaylist: "Search Result",
+		},
+		{
+			name:         "curated override",
+			styleID:      "curated",
+			resolver:     &uriResolverMock{},
+			wantPlaylist: "Staff Picks",
+			wantProvider: beer.CuratedProviderName,
+		},
+		{
+			name:    "external override",
+			styleID: "external",
+			resolver: &uriResolverMock{
+				playlist: beer.Playlist{Name: "Pinned", Provider: "spotify"},
+			},
+			wantPlaylist: "Pinned",
+			wantProvider: "spotify",
+		},
+		{
+			name:    "unresolvable external override falls back to search",
+			styleID: "external",
+			resolver: &uriResolverMock{
+				err: errors.New("spotify down"),
+			},
+			wantPlaylist: "Search Result",
+		},
+		{
+			name:         "external override without resolver falls back to search",
+			styleID:      "external",
+			resolver:     nil,
+			wantPlaylist: "Search Result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := &spotifyGatewayMock{playlist: beer.Playlist{Name: "Search Result"}}
+			provider := beer.NewOverridingMusicProvider(search, overrides, tt.resolver)
+
+			ctx := beer.WithStyleID(context.Background(), tt.styleID)
+			playlist, err := provider.FindPlaylistByStyle(ctx, "IPA")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if playlist.Name != tt.wantPlaylist {
+				t.Errorf("expected playlist %s, got %s", tt.wantPlaylist, playlist.Name)
+			}
+
+			if playlist.Provider != tt.wantProvider {
+				t.Errorf("expected provider %q, got %q", tt.wantProvider, playlist.Provider)
+			}
+		})
+	}
+}
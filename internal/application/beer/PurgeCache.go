@@ -0,0 +1,61 @@
+package beer
+
+import "karhub-beer-machine/internal/application/metrics"
+
+// PurgeCacheInput selects what to purge: exactly one of Key, Prefix or All.
+type PurgeCacheInput struct {
+	Cache  string
+	Key    string
+	Prefix string
+	All    bool
+}
+
+// PurgeCacheOutput reports how many keys were removed.
+type PurgeCacheOutput struct {
+	Purged int
+}
+
+// PurgeCacheUseCase removes entries from a cache.
+type PurgeCacheUseCase struct {
+	caches map[string]AdministrableCache
+}
+
+// NewPurgeCacheUseCase creates a new PurgeCacheUseCase.
+func NewPurgeCacheUseCase(
+	caches map[string]AdministrableCache,
+) *PurgeCacheUseCase {
+	return &PurgeCacheUseCase{
+		caches: caches,
+	}
+}
+
+// Execute runs the use case.
+func (uc *PurgeCacheUseCase) Execute(input PurgeCacheInput) (PurgeCacheOutput, error) {
+	defer metrics.Time("purge_cache")()
+
+	selectors := 0
+	for _, set := range []bool{input.Key != "", input.Prefix != "", input.All} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return PurgeCacheOutput{}, ErrInvalidCachePurge
+	}
+
+	c, err := findCache(uc.caches, input.Cache)
+	if err != nil {
+		return PurgeCacheOutput{}, err
+	}
+
+	switch {
+	case input.Key != "":
+		if c.PurgeKey(input.Key) {
+			return PurgeCacheOutput{Purged: 1}, nil
+		}
+		return PurgeCacheOutput{}, nil
+	default:
+		// All purges the empty prefix
+		return PurgeCacheOutput{Purged: c.PurgePrefix(input.Prefix)}, nil
+	}
+}
@@ -0,0 +1,72 @@
+package beer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// RecommendationCache stores memoized recommendations by key. Any
+// string-keyed cache backend of styles satisfies it.
+type RecommendationCache interface {
+	Get(key string) (domain.BeerStyle, bool)
+	Set(key string, style domain.BeerStyle, ttl time.Duration) bool
+}
+
+// recommendationMemo memoizes the best style per (strategy, temperature
+// bucket, catalog version). The strategy is part of every key, so styles
+// selected by one strategy are never served for another. A catalog
+// change moves the version, so older entries are never read again and
+// age out of the bounded cache.
+type recommendationMemo struct {
+	cache RecommendationCache
+
+	// scale is 1/precision: temperatures are rounded to the nearest
+	// 1/scale degree, 0 disables rounding
+	scale float64
+}
+
+// bucket rounds temperature to the memo precision. Recommendations are
+// computed for the rounded temperature, so every request in a bucket gets
+// the same answer whether or not it was memoized.
+func (m *recommendationMemo) bucket(temperature float64) (string, float64) {
+	if m.scale == 0 {
+		return strconv.FormatFloat(temperature, 'g', -1, 64), temperature
+	}
+
+	// Dividing by the scale (not multiplying by the precision) keeps
+	// e.g. 31/10 exactly 3.1, so ties are decided as for raw input
+	bucket := math.Round(temperature * m.scale)
+	if bucket == 0 {
+		bucket = 0 // not -0, which would be a separate key
+	}
+	return strconv.FormatFloat(bucket, 'f', 0, 64), bucket / m.scale
+}
+
+// recommend returns the memoized style for temperature at the catalog
+// version, or selects it with selectStyle and memoizes it. selectStyle
+// must read a catalog at least as recent as version.
+func (m *recommendationMemo) recommend(
+	strategy string,
+	version uint64,
+	temperature float64,
+	selectStyle func(temperature float64) (domain.BeerStyle, error),
+) (domain.BeerStyle, error) {
+	bucket, rounded := m.bucket(temperature)
+	key := fmt.Sprintf("recommendation:%s:v%d:%s", strategy, version, bucket)
+
+	if style, found := m.cache.Get(key); found {
+		return style, nil
+	}
+
+	style, err := selectStyle(rounded)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
+
+	m.cache.Set(key, style, 0)
+	return style, nil
+}
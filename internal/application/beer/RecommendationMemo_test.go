@@ -0,0 +1,290 @@
+package beer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+type versionedRepositoryMock struct {
+	beerStyleRepositoryMock
+
+	mu       sync.Mutex
+	version  uint64
+	findAlls int
+}
+
+func (m *versionedRepositoryMock) FindAll() ([]domain.BeerStyle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.findAlls++
+
+	// Copy, as a real repository would
+	return append([]domain.BeerStyle(nil), m.styles...), m.err
+}
+
+func (m *versionedRepositoryMock) CatalogVersion() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.version
+}
+
+func (m *versionedRepositoryMock) replace(styles []domain.BeerStyle) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.styles = styles
+	m.version++
+}
+
+func (m *versionedRepositoryMock) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.findAlls
+}
+
+type recommendationCacheMock struct {
+	mu      sync.RWMutex
+	entries map[string]domain.BeerStyle
+}
+
+func newRecommendationCacheMock() *recommendationCacheMock {
+	return &recommendationCacheMock{entries: make(map[string]domain.BeerStyle)}
+}
+
+func (m *recommendationCacheMock) Get(key string) (domain.BeerStyle, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	style, found := m.entries[key]
+	return style, found
+}
+
+func (m *recommendationCacheMock) Set(key string, style domain.BeerStyle, _ time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = style
+	return true
+}
+
+// benchmarkCatalog builds n styles with distinct average temperatures.
+func benchmarkCatalog(n int) []domain.BeerStyle {
+	styles := make([]domain.BeerStyle, 0, n)
+	for i := range n {
+		avg := -10 + 25*float64(i)/float64(n)
+		styles = append(styles, domain.BeerStyle{
+			ID:      fmt.Sprint(i),
+			Name:    fmt.Sprintf("Style %04d", i),
+			MinTemp: avg - 2,
+			MaxTemp: avg + 2,
+		})
+	}
+	return styles
+}
+
+/*
+	TESTS
+*/
+
+func TestFindBestBeerStyleUseCase_RecommendationMemo(t *testing.T) {
+	// Averages 2.5 (Brown Ale) and 3.5 (Amber Ale): 3.0 is a tie
+	catalog := []domain.BeerStyle{
+		{ID: "1", Name: "Brown Ale", MinTemp: 0, MaxTemp: 5},
+		{ID: "2", Name: "Amber Ale", MinTemp: 2, MaxTemp: 5},
+	}
+
+	tests := []struct {
+		name         string
+		precision    float64
+		temperatures []float64
+		wantStyles   []string
+		wantFindAlls int
+	}{
+		{
+			name:         "same temperature is memoized",
+			precision:    0.1,
+			temperatures: []float64{5, 5, 5},
+			wantStyles:   []string{"Amber Ale", "Amber Ale", "Amber Ale"},
+			wantFindAlls: 1,
+		},
+		{
+			name:      "temperatures in a bucket share the rounded answer",
+			precision: 0.1,
+			// 2.96 alone is closer to Brown Ale, but rounds to the 3.0 tie
+			temperatures: []float64{3.04, 2.96, 3},
+			wantStyles:   []string{"Amber Ale", "Amber Ale", "Amber Ale"},
+			wantFindAlls: 1,
+		},
+		{
+			name:         "exact temperatures without precision",
+			precision:    0,
+			temperatures: []float64{2.96, 2.96, 3},
+			wantStyles:   []string{"Brown Ale", "Brown Ale", "Amber Ale"},
+			wantFindAlls: 2,
+		},
+		{
+			name:         "coarse precision",
+			precision:    5,
+			temperatures: []float64{-2, 1, 2.4},
+			wantStyles:   []string{"Brown Ale", "Brown Ale", "Brown Ale"},
+			wantFindAlls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &versionedRepositoryMock{}
+			repo.styles = catalog
+
+			uc := beer.NewFindBestBeerStyleUseCase(
+				repo,
+				&spotifyGatewayMock{},
+				beer.WithRecommendationMemo(newRecommendationCacheMock(), tt.precision),
+			)
+
+			for i, temperature := range tt.temperatures {
+				output, err := uc.Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: temperature})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if output.BeerStyle != tt.wantStyles[i] {
+					t.Errorf("%v°C: expected %s, got %s", temperature, tt.wantStyles[i], output.BeerStyle)
+				}
+			}
+
+			if got := repo.calls(); got != tt.wantFindAlls {
+				t.Errorf("expected %d catalog reads, got %d", tt.wantFindAlls, got)
+			}
+		})
+	}
+}
+
+func TestFindBestBeerStyleUseCase_RecommendationMemoFollowsCatalogVersion(t *testing.T) {
+	repo := &versionedRepositoryMock{}
+	repo.styles = []domain.BeerStyle{{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10}}
+
+	uc := beer.NewFindBestBeerStyleUseCase(
+		repo,
+		&spotifyGatewayMock{},
+		beer.WithRecommendationMemo(newRecommendationCacheMock(), 0.1),
+	)
+
+	find := func() string {
+		t.Helper()
+
+		output, err := uc.Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: -8})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return output.BeerStyle
+	}
+
+	if got := find(); got != "IPA" {
+		t.Fatalf("expected IPA, got %s", got)
+	}
+
+	repo.replace([]domain.BeerStyle{
+		{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+		{ID: "2", Name: "Dunkel", MinTemp: -8, MaxTemp: 2},
+	})
+
+	if got := find(); got != "Dunkel" {
+		t.Errorf("expected the new catalog to be used, got %s", got)
+	}
+
+	if got := find(); got != "Dunkel" || repo.calls() != 2 {
+		t.Errorf("expected the new version to be memoized, got %s after %d reads", got, repo.calls())
+	}
+}
+
+func TestFindBestBeerStyleUseCase_RecommendationMemoSkipsErrors(t *testing.T) {
+	repo := &versionedRepositoryMock{}
+	repo.err = errors.New("db error")
+
+	uc := beer.NewFindBestBeerStyleUseCase(
+		repo,
+		&spotifyGatewayMock{},
+		beer.WithRecommendationMemo(newRecommendationCacheMock(), 0.1),
+	)
+
+	for range 2 {
+		if _, err := uc.Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 0}); err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+	}
+
+	if got := repo.calls(); got != 2 {
+		t.Errorf("expected errors not to be memoized, got %d reads", got)
+	}
+}
+
+func TestFindBestBeerStyleUseCase_RecommendationMemoNeedsVersion(t *testing.T) {
+	repo := &beerStyleRepositoryMock{
+		styles: []domain.BeerStyle{{Name: "IPA", MinTemp: -7, MaxTemp: 10}},
+	}
+	cache := newRecommendationCacheMock()
+
+	uc := beer.NewFindBestBeerStyleUseCase(repo, &spotifyGatewayMock{}, beer.WithRecommendationMemo(cache, 0.1))
+
+	if _, err := uc.Execute(context.Background(), beer.FindBestBeerStyleInput{Temperature: 0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cache.entries) != 0 {
+		t.Errorf("expected no memoization without a catalog version, got %v", cache.entries)
+	}
+}
+
+/*
+	BENCHMARKS
+*/
+
+// BenchmarkFindBestBeerStyleUseCase compares the hot path with and
+// without the recommendation memo, for requests spread over 251
+// temperatures (-10°C to 15°C in 0.1°C steps).
+func BenchmarkFindBestBeerStyleUseCase(b *testing.B) {
+	temperatures := make([]float64, 0, 251)
+	for i := range 251 {
+		temperatures = append(temperatures, -10+float64(i)/10)
+	}
+
+	for _, size := range []int{10, 100, 1000} {
+		for _, memoized := range []bool{false, true} {
+			name := fmt.Sprintf("styles=%d/memo=%v", size, memoized)
+
+			b.Run(name, func(b *testing.B) {
+				repo := &versionedRepositoryMock{}
+				repo.styles = benchmarkCatalog(size)
+
+				var opts []beer.FindBestBeerStyleOption
+				if memoized {
+					opts = append(opts, beer.WithRecommendationMemo(newRecommendationCacheMock(), 0.1))
+				}
+
+				uc := beer.NewFindBestBeerStyleUseCase(repo, &spotifyGatewayMock{}, opts...)
+				ctx := context.Background()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; b.Loop(); i++ {
+					input := beer.FindBestBeerStyleInput{Temperature: temperatures[i%len(temperatures)]}
+					if _, err := uc.Execute(ctx, input); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
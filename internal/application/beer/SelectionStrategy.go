@@ -0,0 +1,52 @@
+package beer
+
+import (
+	"slices"
+
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// Selection strategies, by name.
+const (
+	// ClosestAverageStrategy selects the style whose average temperature
+	// is closest to the target (domain.SelectBestStyle).
+	ClosestAverageStrategy = "closest-average"
+
+	// ContainingRangeStrategy only considers the styles whose range
+	// contains the target (domain.SelectBestContainingStyle), and fails
+	// with domain.ErrNoStyleForTemperature when there is none.
+	ContainingRangeStrategy = "containing-range"
+)
+
+// DefaultSelectionStrategy is used when no strategy is configured.
+const DefaultSelectionStrategy = ClosestAverageStrategy
+
+// SelectionStrategies returns the names of the known strategies.
+func SelectionStrategies() []string {
+	return []string{ClosestAverageStrategy, ContainingRangeStrategy}
+}
+
+// ValidateSelectionStrategy returns ErrUnknownSelectionStrategy when name
+// is not a known strategy.
+func ValidateSelectionStrategy(name string) error {
+	if !slices.Contains(SelectionStrategies(), name) {
+		return ErrUnknownSelectionStrategy
+	}
+	return nil
+}
+
+// selectFromSnapshot applies strategy to a catalog snapshot.
+func selectFromSnapshot(strategy string, catalog *domain.Catalog) func(float64) (domain.BeerStyle, error) {
+	if strategy == ContainingRangeStrategy {
+		return catalog.SelectBestContainingStyle
+	}
+	return catalog.SelectBestStyle
+}
+
+// selectFromStyles applies strategy to a list of styles.
+func selectFromStyles(strategy string, styles []domain.BeerStyle, temperature float64) (domain.BeerStyle, error) {
+	if strategy == ContainingRangeStrategy {
+		return domain.SelectBestContainingStyle(styles, temperature)
+	}
+	return domain.SelectBestStyle(styles, temperature)
+}
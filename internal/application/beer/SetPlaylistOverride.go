@@ -0,0 +1,61 @@
+package beer
+
+import (
+	"net/url"
+	"strings"
+
+	"karhub-beer-machine/internal/application/metrics"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// SetPlaylistOverrideUseCase pins a playlist to a beer style.
+type SetPlaylistOverrideUseCase struct {
+	styles    domain.BeerStyleRepository
+	overrides PlaylistOverrideRepository
+}
+
+// NewSetPlaylistOverrideUseCase creates a new SetPlaylistOverrideUseCase.
+func NewSetPlaylistOverrideUseCase(
+	styles domain.BeerStyleRepository,
+	overrides PlaylistOverrideRepository,
+) *SetPlaylistOverrideUseCase {
+	return &SetPlaylistOverrideUseCase{
+		styles:    styles,
+		overrides: overrides,
+	}
+}
+
+// Execute runs the use case.
+func (uc *SetPlaylistOverrideUseCase) Execute(override PlaylistOverride) error {
+	defer metrics.Time("set_playlist_override")()
+
+	if err := override.Validate(); err != nil {
+		return err
+	}
+
+	if override.ExternalURI != "" && !isPlaylistURI(override.ExternalURI) {
+		return ErrInvalidPlaylistURI
+	}
+
+	if _, err := uc.styles.FindByID(override.StyleID); err != nil {
+		return err
+	}
+
+	return uc.overrides.Save(override)
+}
+
+// isPlaylistURI reports whether uri is a Spotify playlist URI
+// ("spotify:playlist:<id>") or an open.spotify.com playlist link.
+func isPlaylistURI(uri string) bool {
+	if id, ok := strings.CutPrefix(uri, "spotify:playlist:"); ok {
+		return id != ""
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "https" || u.Host != "open.spotify.com" {
+		return false
+	}
+
+	id, ok := strings.CutPrefix(u.Path, "/playlist/")
+	return ok && id != ""
+}
@@ -1,6 +1,9 @@
 package beer
 
 import (
+	"context"
+
+	"karhub-beer-machine/internal/application/metrics"
 	domain "karhub-beer-machine/internal/domain/beer"
 )
 
@@ -15,19 +18,25 @@ type UpdateBeerStyleInput struct {
 // UpdateBeerStyleUseCase handles updating beer styles.
 type UpdateBeerStyleUseCase struct {
 	repository domain.BeerStyleRepository
+	observers  []CatalogObserver
 }
 
 // NewUpdateBeerStyleUseCase creates a new UpdateBeerStyleUseCase.
+// observers are notified after the style is updated.
 func NewUpdateBeerStyleUseCase(
 	repository domain.BeerStyleRepository,
+	observers ...CatalogObserver,
 ) *UpdateBeerStyleUseCase {
 	return &UpdateBeerStyleUseCase{
 		repository: repository,
+		observers:  observers,
 	}
 }
 
-// Execute runs the use case.
-func (uc *UpdateBeerStyleUseCase) Execute(input UpdateBeerStyleInput) error {
+// Execute runs the use case. Observers are notified with ctx.
+func (uc *UpdateBeerStyleUseCase) Execute(ctx context.Context, input UpdateBeerStyleInput) error {
+	defer metrics.Time("update_beer_style")()
+
 	style, err := domain.NewBeerStyle(
 		input.ID,
 		input.Name,
@@ -38,5 +47,32 @@ func (uc *UpdateBeerStyleUseCase) Execute(input UpdateBeerStyleInput) error {
 		return err
 	}
 
-	return uc.repository.Update(style)
+	previousName := uc.previousName(style.ID)
+
+	if err := uc.repository.Update(style); err != nil {
+		return err
+	}
+
+	notifyCatalogChange(ctx, uc.observers, CatalogChange{
+		Kind:         CatalogStyleUpdated,
+		StyleID:      style.ID,
+		Name:         style.Name,
+		PreviousName: previousName,
+	})
+
+	return nil
+}
+
+// previousName returns the current name of the style, so observers can
+// tell renames apart. It is only looked up when someone is listening.
+func (uc *UpdateBeerStyleUseCase) previousName(id string) string {
+	if len(uc.observers) == 0 {
+		return ""
+	}
+
+	current, err := uc.repository.FindByID(id)
+	if err != nil {
+		return ""
+	}
+	return current.Name
 }
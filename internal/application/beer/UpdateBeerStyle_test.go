@@ -1,6 +1,7 @@
 package beer_test
 
 import (
+	"context"
 	"testing"
 
 	"karhub-beer-machine/internal/application/beer"
@@ -39,7 +40,7 @@ func TestUpdateBeerStyleUseCase(t *testing.T) {
 			repo := &beerStyleRepoMock{}
 			uc := beer.NewUpdateBeerStyleUseCase(repo)
 
-			err := uc.Execute(tt.input)
+			err := uc.Execute(context.Background(), tt.input)
 
 			if tt.wantErr && err == nil {
 				t.Fatalf("expected error, got nil")
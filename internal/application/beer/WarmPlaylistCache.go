@@ -0,0 +1,123 @@
+package beer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/metrics"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// PlaylistWarmer loads the playlist for a style into a cache.
+type PlaylistWarmer interface {
+	Warm(ctx context.Context, styleName string) error
+}
+
+// WarmPlaylistCacheInput bounds a catalog warmup.
+type WarmPlaylistCacheInput struct {
+	// Concurrency is the number of styles warmed in parallel.
+	Concurrency int
+
+	// Budget bounds the whole warmup; styles not warmed in time are skipped.
+	Budget time.Duration
+}
+
+// WarmPlaylistCacheOutput summarizes a catalog warmup.
+type WarmPlaylistCacheOutput struct {
+	Warmed  int
+	Failed  int
+	Skipped int
+}
+
+// WarmPlaylistCacheUseCase pre-loads the playlist of every catalog style.
+type WarmPlaylistCacheUseCase struct {
+	repository domain.BeerStyleRepository
+	warmer     PlaylistWarmer
+}
+
+// NewWarmPlaylistCacheUseCase creates a new WarmPlaylistCacheUseCase.
+func NewWarmPlaylistCacheUseCase(
+	repository domain.BeerStyleRepository,
+	warmer PlaylistWarmer,
+) *WarmPlaylistCacheUseCase {
+	return &WarmPlaylistCacheUseCase{
+		repository: repository,
+		warmer:     warmer,
+	}
+}
+
+// Execute runs the use case. Failures to warm individual styles are
+// counted, not returned: a cold entry is only slower, never wrong.
+func (uc *WarmPlaylistCacheUseCase) Execute(
+	ctx context.Context,
+	input WarmPlaylistCacheInput,
+) (WarmPlaylistCacheOutput, error) {
+	defer metrics.Time("warm_playlist_cache")()
+
+	styles, err := uc.repository.FindAll()
+	if err != nil {
+		return WarmPlaylistCacheOutput{}, err
+	}
+
+	if input.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, input.Budget)
+		defer cancel()
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		output WarmPlaylistCacheOutput
+		wg     sync.WaitGroup
+	)
+
+	record := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case err == nil:
+			output.Warmed++
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			output.Skipped++
+		default:
+			output.Failed++
+		}
+	}
+
+	slots := make(chan struct{}, concurrency)
+
+	for _, style := range styles {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			record(ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(style domain.BeerStyle) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			styleCtx := WithStyleID(ctx, style.ID)
+			err := uc.warmer.Warm(styleCtx, style.Name)
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Warn("failed to warm playlist", "style", style.Name, "error", err)
+			}
+			record(err)
+		}(style)
+	}
+
+	wg.Wait()
+
+	return output, nil
+}
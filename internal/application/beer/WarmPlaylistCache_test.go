@@ -0,0 +1,128 @@
+package beer_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+type playlistWarmerMock struct {
+	mu      sync.Mutex
+	active  int
+	peak    int
+	delay   time.Duration
+	failFor map[string]bool
+}
+
+func (m *playlistWarmerMock) Warm(ctx context.Context, styleName string) error {
+	m.mu.Lock()
+	m.active++
+	if m.active > m.peak {
+		m.peak = m.active
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.active--
+		m.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if m.failFor[styleName] {
+		return errors.New("spotify down")
+	}
+	return nil
+}
+
+/*
+	TESTS
+*/
+
+func TestWarmPlaylistCacheUseCase(t *testing.T) {
+	styles := []domain.BeerStyle{
+		{ID: "1", Name: "IPA"},
+		{ID: "2", Name: "Stout"},
+		{ID: "3", Name: "Pilsens"},
+		{ID: "4", Name: "Dunkel"},
+		{ID: "5", Name: "Weissbier"},
+		{ID: "6", Name: "Imperial Stouts"},
+	}
+
+	tests := []struct {
+		name        string
+		input       beer.WarmPlaylistCacheInput
+		delay       time.Duration
+		failFor     map[string]bool
+		want        beer.WarmPlaylistCacheOutput
+		wantMaxPeak int
+	}{
+		{
+			name:        "warms every style within the concurrency bound",
+			input:       beer.WarmPlaylistCacheInput{Concurrency: 2, Budget: time.Second},
+			delay:       5 * time.Millisecond,
+			want:        beer.WarmPlaylistCacheOutput{Warmed: 6},
+			wantMaxPeak: 2,
+		},
+		{
+			name:        "counts failures",
+			input:       beer.WarmPlaylistCacheInput{Concurrency: 3, Budget: time.Second},
+			failFor:     map[string]bool{"IPA": true, "Stout": true},
+			want:        beer.WarmPlaylistCacheOutput{Warmed: 4, Failed: 2},
+			wantMaxPeak: 3,
+		},
+		{
+			name:        "skips what does not fit in the budget",
+			input:       beer.WarmPlaylistCacheInput{Concurrency: 1, Budget: 20 * time.Millisecond},
+			delay:       time.Second,
+			want:        beer.WarmPlaylistCacheOutput{Skipped: 6},
+			wantMaxPeak: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &beerStyleRepositoryMock{styles: styles}
+			warmer := &playlistWarmerMock{delay: tt.delay, failFor: tt.failFor}
+
+			uc := beer.NewWarmPlaylistCacheUseCase(repo, warmer)
+
+			start := time.Now()
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+
+			if warmer.peak > tt.wantMaxPeak {
+				t.Errorf("expected at most %d concurrent warms, got %d", tt.wantMaxPeak, warmer.peak)
+			}
+
+			if elapsed := time.Since(start); elapsed > tt.input.Budget+100*time.Millisecond {
+				t.Errorf("warmup overran its budget: %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestWarmPlaylistCacheUseCase_RepositoryError(t *testing.T) {
+	repo := &beerStyleRepositoryMock{err: errors.New("db down")}
+	uc := beer.NewWarmPlaylistCacheUseCase(repo, &playlistWarmerMock{})
+
+	if _, err := uc.Execute(context.Background(), beer.WarmPlaylistCacheInput{}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -0,0 +1,38 @@
+package beer
+
+import "errors"
+
+// Application-level errors.
+
+var (
+	// ErrUnknownMusicProvider is returned when a music provider is
+	// requested by a name that is not registered.
+	ErrUnknownMusicProvider = errors.New("unknown music provider")
+
+	// ErrInvalidPlaylistOverride is returned when an override has neither
+	// (or both) a curated playlist and an external playlist URI.
+	ErrInvalidPlaylistOverride = errors.New("invalid playlist override")
+
+	// ErrInvalidPlaylistURI is returned when an override points to
+	// something other than a Spotify playlist URI or link.
+	ErrInvalidPlaylistURI = errors.New("invalid playlist uri")
+
+	// ErrPlaylistOverrideNotFound is returned when a style has no override.
+	ErrPlaylistOverrideNotFound = errors.New("playlist override not found")
+
+	// ErrMusicNotFound is returned (wrapped) by music providers when they
+	// have no playlist for a beer style.
+	ErrMusicNotFound = errors.New("no music found for beer style")
+
+	// ErrUnknownSelectionStrategy is returned when a selection strategy
+	// is requested by a name that is not known.
+	ErrUnknownSelectionStrategy = errors.New("unknown selection strategy")
+
+	// ErrCacheNotFound is returned when a cache is requested by a name
+	// that is not registered.
+	ErrCacheNotFound = errors.New("cache not found")
+
+	// ErrInvalidCachePurge is returned when a purge does not name exactly
+	// one of a key, a prefix or the whole cache.
+	ErrInvalidCachePurge = errors.New("invalid cache purge")
+)
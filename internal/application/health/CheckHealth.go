@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+
+	"karhub-beer-machine/internal/application/metrics"
+)
+
+// CheckHealthInput represents the input for the use case.
+type CheckHealthInput struct {
+	// Dependencies runs the registered checks (readiness). Without it,
+	// only the process is reported, as up (liveness).
+	Dependencies bool
+}
+
+// Report is the health of the service.
+type Report struct {
+	// Status is fail when a required check failed, warn when only
+	// optional ones did, and pass otherwise.
+	Status Status
+	Build  Build
+	Checks []CheckResult
+}
+
+// CheckHealthUseCase reports the health of the service and, on demand,
+// of its dependencies.
+type CheckHealthUseCase struct {
+	registry *Registry
+	build    Build
+}
+
+// NewCheckHealthUseCase creates a new CheckHealthUseCase.
+func NewCheckHealthUseCase(registry *Registry, build Build) *CheckHealthUseCase {
+	return &CheckHealthUseCase{
+		registry: registry,
+		build:    build,
+	}
+}
+
+// Execute runs the use case.
+func (uc *CheckHealthUseCase) Execute(ctx context.Context, input CheckHealthInput) Report {
+	defer metrics.Time("check_health")()
+
+	report := Report{
+		Status: StatusPass,
+		Build:  uc.build,
+	}
+
+	if !input.Dependencies {
+		return report
+	}
+
+	report.Checks = uc.registry.Run(ctx)
+	for _, result := range report.Checks {
+		switch result.Status {
+		case StatusFail:
+			report.Status = StatusFail
+		case StatusWarn:
+			if report.Status == StatusPass {
+				report.Status = StatusWarn
+			}
+		}
+	}
+
+	return report
+}
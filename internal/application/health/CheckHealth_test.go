@@ -0,0 +1,154 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/health"
+)
+
+func passing() health.Checker {
+	return health.CheckerFunc(func(context.Context) error { return nil })
+}
+
+func failing(err error) health.Checker {
+	return health.CheckerFunc(func(context.Context) error { return err })
+}
+
+/*
+	TESTS
+*/
+
+func TestCheckHealthUseCase_Execute(t *testing.T) {
+	build := health.Build{Version: "v1.2.0", Commit: "abc123"}
+
+	tests := []struct {
+		name       string
+		register   func(r *health.Registry)
+		input      health.CheckHealthInput
+		wantStatus health.Status
+		wantChecks []health.Status
+	}{
+		{
+			name: "liveness runs no check",
+			register: func(r *health.Registry) {
+				r.Register("repository", failing(errors.New("down")))
+			},
+			wantStatus: health.StatusPass,
+		},
+		{
+			name: "every check passes",
+			register: func(r *health.Registry) {
+				r.Register("repository", passing())
+				r.Register("cache", passing(), health.Optional())
+			},
+			input:      health.CheckHealthInput{Dependencies: true},
+			wantStatus: health.StatusPass,
+			wantChecks: []health.Status{health.StatusPass, health.StatusPass},
+		},
+		{
+			name: "optional check fails",
+			register: func(r *health.Registry) {
+				r.Register("repository", passing())
+				r.Register("cache", failing(errors.New("redis down")), health.Optional())
+			},
+			input:      health.CheckHealthInput{Dependencies: true},
+			wantStatus: health.StatusWarn,
+			wantChecks: []health.Status{health.StatusWarn, health.StatusPass},
+		},
+		{
+			name: "required check fails",
+			register: func(r *health.Registry) {
+				r.Register("repository", failing(errors.New("down")))
+				r.Register("cache", failing(errors.New("redis down")), health.Optional())
+			},
+			input:      health.CheckHealthInput{Dependencies: true},
+			wantStatus: health.StatusFail,
+			wantChecks: []health.Status{health.StatusWarn, health.StatusFail},
+		},
+		{
+			name: "re-registering replaces the check",
+			register: func(r *health.Registry) {
+				r.Register("repository", failing(errors.New("down")))
+				r.Register("repository", passing())
+			},
+			input:      health.CheckHealthInput{Dependencies: true},
+			wantStatus: health.StatusPass,
+			wantChecks: []health.Status{health.StatusPass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry()
+			tt.register(registry)
+
+			report := health.NewCheckHealthUseCase(registry, build).
+				Execute(context.Background(), tt.input)
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, report.Status)
+			}
+			if report.Build != build {
+				t.Errorf("expected build %+v, got %+v", build, report.Build)
+			}
+
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Fatalf("expected %d checks, got %d", len(tt.wantChecks), len(report.Checks))
+			}
+			for i, want := range tt.wantChecks {
+				if got := report.Checks[i]; got.Status != want || (want == health.StatusPass) != (got.Err == nil) {
+					t.Errorf("expected check %s to %s, got %+v", got.Name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Run_Timeout(t *testing.T) {
+	registry := health.NewRegistry()
+
+	// Ignores its context: it must be abandoned, not waited for
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	registry.Register("hung", health.CheckerFunc(func(context.Context) error {
+		<-release
+		return nil
+	}), health.WithTimeout(20*time.Millisecond))
+
+	registry.Register("slow", health.CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), health.WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	results := registry.Run(context.Background())
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected checks to be bounded by their timeout, took %s", elapsed)
+	}
+
+	for _, r := range results {
+		if r.Status != health.StatusFail || r.Err == nil {
+			t.Errorf("expected %s to fail, got %+v", r.Name, r)
+		}
+	}
+	if !errors.Is(results[0].Err, health.ErrCheckTimeout) {
+		t.Errorf("expected hung check to time out, got %v", results[0].Err)
+	}
+}
+
+func TestRegistry_Run_Panic(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("broken", health.CheckerFunc(func(context.Context) error {
+		panic("nil map")
+	}))
+
+	results := registry.Run(context.Background())
+
+	if len(results) != 1 || results[0].Status != health.StatusFail {
+		t.Fatalf("expected the panicking check to fail, got %+v", results)
+	}
+}
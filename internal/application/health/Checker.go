@@ -0,0 +1,41 @@
+// Package health tells whether the service and the dependencies it
+// relies on work, for liveness and readiness probes.
+package health
+
+import "context"
+
+// Checker reports whether a dependency works, e.g. by pinging it. It
+// should honor the context deadline. This is an application-level port.
+type Checker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// CheckHealth calls f.
+func (f CheckerFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Status is the outcome of a check, or of all of them.
+type Status string
+
+const (
+	// StatusPass means the dependency works.
+	StatusPass Status = "pass"
+
+	// StatusWarn means an optional dependency failed: the service still
+	// works, degraded.
+	StatusWarn Status = "warn"
+
+	// StatusFail means a required dependency failed.
+	StatusFail Status = "fail"
+)
+
+// Build identifies the running binary.
+type Build struct {
+	Version   string
+	Commit    string
+	GoVersion string
+}
@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultCheckTimeout bounds a check registered without WithTimeout.
+const DefaultCheckTimeout = 2 * time.Second
+
+// ErrCheckTimeout is reported for a check still running at its timeout.
+var ErrCheckTimeout = errors.New("health check timed out")
+
+// Registry holds the checks of the service's dependencies. It is safe
+// for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]check
+}
+
+type check struct {
+	checker  Checker
+	timeout  time.Duration
+	optional bool
+}
+
+// CheckOption customizes a registered check.
+type CheckOption func(*check)
+
+// WithTimeout bounds the check (DefaultCheckTimeout otherwise).
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *check) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// Optional marks a dependency the service can work without, degraded:
+// its failure is a warning and does not make the service unready.
+func Optional() CheckOption {
+	return func(c *check) {
+		c.optional = true
+	}
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		checks: make(map[string]check),
+	}
+}
+
+// Register adds the check of a dependency, replacing any check already
+// registered under name.
+func (r *Registry) Register(name string, checker Checker, opts ...CheckOption) {
+	c := check{checker: checker, timeout: DefaultCheckTimeout}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks[name] = c
+}
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Name     string
+	Status   Status
+	Optional bool
+	Duration time.Duration
+
+	// Err is nil when the check passed.
+	Err error
+}
+
+// Run runs every check concurrently, each within its timeout, and
+// returns the results sorted by name.
+func (r *Registry) Run(ctx context.Context) []CheckResult {
+	r.mu.RLock()
+	checks := maps.Clone(r.checks)
+	r.mu.RUnlock()
+
+	names := slices.Sorted(maps.Keys(checks))
+	results := make([]CheckResult, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, name, checks[name])
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// run runs c within its timeout. Checkers ignoring their context are
+// abandoned at the timeout, so a hung dependency cannot hang probes.
+func run(ctx context.Context, name string, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("health check panicked: %v", v)
+			}
+		}()
+		done <- c.checker.CheckHealth(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrCheckTimeout
+	}
+
+	result := CheckResult{
+		Name:     name,
+		Status:   StatusPass,
+		Optional: c.optional,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	switch {
+	case err == nil:
+	case c.optional:
+		result.Status = StatusWarn
+	default:
+		result.Status = StatusFail
+	}
+
+	return result
+}
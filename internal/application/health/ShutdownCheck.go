@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrShuttingDown is reported by a ShutdownCheck once shutdown began.
+var ErrShuttingDown = errors.New("shutting down")
+
+// ShutdownCheck fails once the service starts shutting down, so load
+// balancers stop routing new requests to it while in-flight ones drain.
+type ShutdownCheck struct {
+	draining atomic.Bool
+}
+
+// NewShutdownCheck creates a passing ShutdownCheck.
+func NewShutdownCheck() *ShutdownCheck {
+	return &ShutdownCheck{}
+}
+
+// Begin makes the check fail from now on.
+func (c *ShutdownCheck) Begin() {
+	c.draining.Store(true)
+}
+
+// CheckHealth implements Checker.
+func (c *ShutdownCheck) CheckHealth(context.Context) error {
+	if c.draining.Load() {
+		return ErrShuttingDown
+	}
+	return nil
+}
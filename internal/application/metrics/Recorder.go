@@ -0,0 +1,55 @@
+// Package metrics lets use cases report measurements without depending
+// on how they are exported. Like log/slog, it has a process-wide
+// default Recorder, which discards everything until one is set.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Recorder receives the measurements of use cases.
+// This is an application-level port.
+type Recorder interface {
+	// ObserveUseCase records how long an execution of a use case took.
+	ObserveUseCase(useCase string, duration time.Duration)
+
+	// CountRecommendation records a recommendation of a beer style.
+	CountRecommendation(style string)
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) ObserveUseCase(string, time.Duration) {}
+func (nopRecorder) CountRecommendation(string)           {}
+
+type holder struct{ recorder Recorder }
+
+var defaultRecorder atomic.Pointer[holder]
+
+func init() {
+	defaultRecorder.Store(&holder{nopRecorder{}})
+}
+
+// SetDefault makes recorder the default Recorder; nil discards
+// measurements again.
+func SetDefault(recorder Recorder) {
+	if recorder == nil {
+		recorder = nopRecorder{}
+	}
+	defaultRecorder.Store(&holder{recorder})
+}
+
+// Default returns the default Recorder.
+func Default() Recorder {
+	return defaultRecorder.Load().recorder
+}
+
+// Time starts timing a use case; call the returned function when it
+// ends, e.g. defer metrics.Time("list_beer_styles")().
+func Time(useCase string) func() {
+	start := time.Now()
+	return func() {
+		Default().ObserveUseCase(useCase, time.Since(start))
+	}
+}
@@ -0,0 +1,72 @@
+package reload
+
+import (
+	"context"
+	"log/slog"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/metrics"
+)
+
+// ReloadConfigInput says who asked for the reload, for the audit log.
+type ReloadConfigInput struct {
+	// Trigger is how the reload was asked for, e.g. SIGHUP or api.
+	Trigger string
+
+	// Actor identifies the caller, when known.
+	Actor string
+}
+
+// ReloadConfigOutput lists the settings that changed, applied or not.
+type ReloadConfigOutput struct {
+	Changes []Change
+}
+
+// ReloadConfigUseCase reloads the configuration and writes an audit log
+// entry recording what changed, or why the reload was rejected.
+type ReloadConfigUseCase struct {
+	reloader Reloader
+}
+
+// NewReloadConfigUseCase creates a new ReloadConfigUseCase.
+func NewReloadConfigUseCase(reloader Reloader) *ReloadConfigUseCase {
+	return &ReloadConfigUseCase{
+		reloader: reloader,
+	}
+}
+
+// Execute runs the use case.
+func (uc *ReloadConfigUseCase) Execute(ctx context.Context, input ReloadConfigInput) (ReloadConfigOutput, error) {
+	defer metrics.Time("reload_config")()
+
+	logger := logging.FromContext(ctx).With(
+		"audit", "config_reload",
+		"trigger", input.Trigger,
+		"actor", input.Actor,
+	)
+
+	changes, err := uc.reloader.Reload(ctx)
+	if err != nil {
+		logger.Warn("configuration reload rejected, keeping the running configuration", "error", err)
+		return ReloadConfigOutput{}, err
+	}
+
+	var applied, pending []any
+	for _, change := range changes {
+		attr := slog.Group(change.Key, "old", change.Old, "new", change.New)
+		if change.Applied {
+			applied = append(applied, attr)
+		} else {
+			pending = append(pending, attr)
+		}
+	}
+
+	attrs := []any{slog.Group("changes", applied...)}
+	if len(pending) > 0 {
+		// Logged apart, so operators notice a restart is still needed
+		attrs = append(attrs, slog.Group("pending_restart", pending...))
+	}
+	logger.Info("configuration reloaded", attrs...)
+
+	return ReloadConfigOutput{Changes: changes}, nil
+}
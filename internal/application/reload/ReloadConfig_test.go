@@ -0,0 +1,129 @@
+package reload_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/reload"
+)
+
+type reloaderMock struct {
+	changes []reload.Change
+	err     error
+}
+
+func (m *reloaderMock) Reload(context.Context) ([]reload.Change, error) {
+	return m.changes, m.err
+}
+
+// auditContext returns a context whose logger writes JSON records to buf.
+func auditContext(buf *bytes.Buffer) context.Context {
+	return logging.WithLogger(context.Background(), slog.New(slog.NewJSONHandler(buf, nil)))
+}
+
+/*
+	TESTS
+*/
+
+func TestReloadConfigUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		reloader    *reloaderMock
+		wantErr     bool
+		wantLevel   string
+		wantMessage string
+		wantAttrs   map[string]any
+	}{
+		{
+			name: "changes are audited",
+			reloader: &reloaderMock{changes: []reload.Change{
+				{Key: "cache.ttl", Old: "10m0s", New: "5m0s", Applied: true},
+				{Key: "http.port", Old: "8080", New: "9090"},
+			}},
+			wantLevel:   "INFO",
+			wantMessage: "configuration reloaded",
+			wantAttrs: map[string]any{
+				"audit":   "config_reload",
+				"trigger": "api",
+				"actor":   "key:1",
+				"changes": map[string]any{
+					"cache.ttl": map[string]any{"old": "10m0s", "new": "5m0s"},
+				},
+				"pending_restart": map[string]any{
+					"http.port": map[string]any{"old": "8080", "new": "9090"},
+				},
+			},
+		},
+		{
+			name:        "reload without changes",
+			reloader:    &reloaderMock{},
+			wantLevel:   "INFO",
+			wantMessage: "configuration reloaded",
+			wantAttrs: map[string]any{
+				"audit":   "config_reload",
+				"trigger": "api",
+				"actor":   "key:1",
+			},
+		},
+		{
+			name:        "rejected reload",
+			reloader:    &reloaderMock{err: fmt.Errorf("%w: cache.ttl: must be positive", reload.ErrInvalidConfig)},
+			wantErr:     true,
+			wantLevel:   "WARN",
+			wantMessage: "configuration reload rejected, keeping the running configuration",
+			wantAttrs: map[string]any{
+				"audit":   "config_reload",
+				"trigger": "api",
+				"actor":   "key:1",
+				"error":   "invalid configuration: cache.ttl: must be positive",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			uc := reload.NewReloadConfigUseCase(tt.reloader)
+
+			output, err := uc.Execute(auditContext(&buf), reload.ReloadConfigInput{Trigger: "api", Actor: "key:1"})
+
+			if tt.wantErr {
+				if !errors.Is(err, reload.ErrInvalidConfig) {
+					t.Fatalf("expected ErrInvalidConfig, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(output.Changes, tt.reloader.changes) {
+					t.Fatalf("expected changes %v, got %v", tt.reloader.changes, output.Changes)
+				}
+			}
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("expected a single JSON audit record, got %q: %v", buf.String(), err)
+			}
+			if record["level"] != tt.wantLevel {
+				t.Fatalf("expected level %s, got %v", tt.wantLevel, record["level"])
+			}
+			if record["msg"] != tt.wantMessage {
+				t.Fatalf("expected message %q, got %v", tt.wantMessage, record["msg"])
+			}
+
+			delete(record, "time")
+			delete(record, "level")
+			delete(record, "msg")
+			if !reflect.DeepEqual(record, tt.wantAttrs) {
+				t.Fatalf("expected attributes %v, got %v", tt.wantAttrs, record)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package reload applies configuration changes to the running service,
+// without a restart, and keeps an audit trail of them.
+package reload
+
+import "context"
+
+// Reloader loads the configuration again and swaps in the components
+// built from its reloadable settings. When the new configuration is
+// invalid, it returns an error wrapping ErrInvalidConfig and the running
+// one stays in place. This is an application-level port.
+type Reloader interface {
+	Reload(ctx context.Context) ([]Change, error)
+}
+
+// Change is a setting whose value differs from the running configuration.
+type Change struct {
+	// Key is the setting's path in the config file, e.g. cache.ttl.
+	Key string
+
+	// Old and New are the values as written in a config file, with
+	// secrets redacted.
+	Old string
+	New string
+
+	// Applied is false for settings that only take effect on restart.
+	Applied bool
+}
@@ -0,0 +1,11 @@
+package reload
+
+import "errors"
+
+// Application-level errors.
+
+var (
+	// ErrInvalidConfig is returned (wrapped) when the reloaded
+	// configuration does not load, does not validate or cannot be applied.
+	ErrInvalidConfig = errors.New("invalid configuration")
+)
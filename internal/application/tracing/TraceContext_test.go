@@ -0,0 +1,116 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+/*
+	TESTS
+*/
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantOK      bool
+		wantSampled bool
+	}{
+		{
+			name:        "sampled",
+			header:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantOK:      true,
+			wantSampled: true,
+		},
+		{
+			name:   "not sampled",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			wantOK: true,
+		},
+		{
+			name:        "future version with extra fields",
+			header:      "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			wantOK:      true,
+			wantSampled: true,
+		},
+		{
+			name:   "version 00 with extra fields",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			name:   "forbidden version",
+			header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "all-zero trace id",
+			header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "all-zero span id",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+		{
+			name:   "uppercase hex",
+			header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:   "short span id",
+			header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+		},
+		{
+			name:   "empty",
+			header: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := tracing.ParseTraceParent(tt.header)
+
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if !ok {
+				return
+			}
+
+			if !sc.Remote || sc.Sampled != tt.wantSampled {
+				t.Errorf("unexpected span context %+v", sc)
+			}
+			if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+				t.Errorf("unexpected ids %s %s", sc.TraceID, sc.SpanID)
+			}
+		})
+	}
+}
+
+func TestSpanContext_TraceParent(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, _ := tracing.ParseTraceParent(header)
+	if got := sc.TraceParent(); got != header {
+		t.Fatalf("expected %q, got %q", header, got)
+	}
+
+	sc.Sampled = false
+	if got := sc.TraceParent(); got != header[:len(header)-2]+"00" {
+		t.Fatalf("expected unsampled flags, got %q", got)
+	}
+}
+
+func TestStart_WithoutTracerKeepsRemoteParent(t *testing.T) {
+	remote, _ := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.ContextWithRemoteSpanContext(context.Background(), remote)
+
+	ctx, span := tracing.Start(ctx, "operation")
+	defer span.End()
+
+	if span.SpanContext() != remote {
+		t.Fatalf("expected the remote span context, got %+v", span.SpanContext())
+	}
+	if got := tracing.SpanContextFromContext(ctx); got != remote {
+		t.Fatalf("expected the remote span context in ctx, got %+v", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package tracing lets use cases and adapters record spans without
+// depending on how they are exported. Like log/slog, it has a
+// process-wide default Tracer; until one is set, spans are not recorded
+// but incoming trace contexts are still propagated.
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// SpanKind tells whether a span serves a request, calls another
+// service, or is internal to the process.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindServer
+	SpanKindClient
+)
+
+// Attribute is a key-value pair describing a span. Values are strings,
+// bools, ints, int64s or float64s.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Float64 returns a floating point attribute.
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a timed operation within a trace.
+type Span interface {
+	SpanContext() SpanContext
+
+	// SetName renames the span, e.g. once the HTTP route is known.
+	SetName(name string)
+
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError marks the span as failed because of err.
+	RecordError(err error)
+
+	// End ends the span; later calls have no effect.
+	End()
+}
+
+// Tracer starts spans. This is an application-level port.
+type Tracer interface {
+	// Start starts a span whose parent is the span context of ctx (see
+	// SpanContextFromContext), or a new trace when it has none.
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) Span
+}
+
+type holder struct{ tracer Tracer }
+
+var defaultTracer atomic.Pointer[holder]
+
+func init() {
+	defaultTracer.Store(&holder{nopTracer{}})
+}
+
+// SetDefault makes tracer the default Tracer; nil stops recording again.
+func SetDefault(tracer Tracer) {
+	if tracer == nil {
+		tracer = nopTracer{}
+	}
+	defaultTracer.Store(&holder{tracer})
+}
+
+// Default returns the default Tracer.
+func Default() Tracer {
+	return defaultTracer.Load().tracer
+}
+
+// Start starts an internal span with the default Tracer, and returns a
+// context carrying it for the spans of nested operations.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return StartKind(ctx, SpanKindInternal, name, attrs...)
+}
+
+// StartKind is Start for spans of the given kind.
+func StartKind(ctx context.Context, kind SpanKind, name string, attrs ...Attribute) (context.Context, Span) {
+	span := Default().Start(ctx, name, kind, attrs...)
+	return ContextWithSpan(ctx, span), span
+}
+
+/*
+	---------- Context ----------
+*/
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// ContextWithSpan returns a context carrying span.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span stored in ctx, or a span that records
+// nothing.
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return nopSpan{SpanContextFromContext(ctx)}
+}
+
+// ContextWithRemoteSpanContext returns a context carrying the span
+// context of a caller, parent of the spans started from it.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the span in ctx or,
+// failing that, of the caller.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+/*
+	---------- No-op ----------
+*/
+
+type nopTracer struct{}
+
+// Start returns a span that records nothing but keeps the parent span
+// context, so the caller's trace still reaches downstream services.
+func (nopTracer) Start(ctx context.Context, _ string, _ SpanKind, _ ...Attribute) Span {
+	return nopSpan{SpanContextFromContext(ctx)}
+}
+
+type nopSpan struct {
+	sc SpanContext
+}
+
+func (s nopSpan) SpanContext() SpanContext { return s.sc }
+func (nopSpan) SetName(string)             {}
+func (nopSpan) SetAttributes(...Attribute) {}
+func (nopSpan) RecordError(error)          {}
+func (nopSpan) End()                       {}
@@ -19,3 +19,16 @@ type BeerStyleRepository interface {
 	// FindAll retrieves all beer styles.
 	FindAll() ([]BeerStyle, error)
 }
+
+// CatalogVersioner is implemented by repositories that track a catalog
+// version: a number that changes whenever a style is created, updated or
+// deleted. It lets callers tell cheaply whether the catalog changed.
+type CatalogVersioner interface {
+	CatalogVersion() uint64
+}
+
+// CatalogSnapshotter is implemented by repositories that publish the
+// catalog as an immutable snapshot, readable without locking or copying.
+type CatalogSnapshotter interface {
+	Snapshot() *Catalog
+}
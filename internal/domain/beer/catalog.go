@@ -0,0 +1,128 @@
+package beer
+
+import "sync"
+
+// Catalog is an immutable snapshot of the beer styles at a version.
+//
+// A Catalog is never modified once built, so it can be shared by any
+// number of readers without locking; writers build a new one with With or
+// Without (copy-on-write) and publish it.
+type Catalog struct {
+	version uint64
+	styles  []BeerStyle
+	byID    map[string]int
+
+	// index and tree are built on the first selection that needs them,
+	// once per version
+	indexOnce sync.Once
+	index     *StyleIndex
+	treeOnce  sync.Once
+	tree      *StyleIntervalTree
+}
+
+// NewCatalog builds a snapshot holding a copy of styles. Styles sharing
+// an ID are collapsed, the last one winning.
+func NewCatalog(version uint64, styles []BeerStyle) *Catalog {
+	c := &Catalog{
+		version: version,
+		styles:  make([]BeerStyle, 0, len(styles)),
+		byID:    make(map[string]int, len(styles)),
+	}
+
+	for _, style := range styles {
+		if i, found := c.byID[style.ID]; found {
+			c.styles[i] = style
+			continue
+		}
+		c.byID[style.ID] = len(c.styles)
+		c.styles = append(c.styles, style)
+	}
+
+	return c
+}
+
+// Version identifies the snapshot; every write produces a new version.
+func (c *Catalog) Version() uint64 {
+	return c.version
+}
+
+// Len returns the number of styles.
+func (c *Catalog) Len() int {
+	return len(c.styles)
+}
+
+// Styles returns a copy of the styles, in insertion order.
+func (c *Catalog) Styles() []BeerStyle {
+	return append([]BeerStyle(nil), c.styles...)
+}
+
+// FindByID returns the style with the given ID.
+func (c *Catalog) FindByID(id string) (BeerStyle, bool) {
+	i, found := c.byID[id]
+	if !found {
+		return BeerStyle{}, false
+	}
+	return c.styles[i], true
+}
+
+// SelectBestStyle applies SelectBestStyle to the snapshot in O(log N),
+// through a StyleIndex built on first use.
+func (c *Catalog) SelectBestStyle(targetTemp float64) (BeerStyle, error) {
+	c.indexOnce.Do(func() {
+		c.index = NewStyleIndex(c.styles)
+	})
+	return c.index.SelectBestStyle(targetTemp)
+}
+
+// SelectBestContainingStyle applies SelectBestContainingStyle to the
+// snapshot, through a StyleIntervalTree built on first use.
+func (c *Catalog) SelectBestContainingStyle(targetTemp float64) (BeerStyle, error) {
+	c.treeOnce.Do(func() {
+		c.tree = NewStyleIntervalTree(c.styles)
+	})
+	return c.tree.SelectBestContainingStyle(targetTemp)
+}
+
+// With returns the next version of the catalog with style added, or
+// replacing the style with the same ID.
+func (c *Catalog) With(style BeerStyle) *Catalog {
+	next := &Catalog{
+		version: c.version + 1,
+		styles:  make([]BeerStyle, len(c.styles), len(c.styles)+1),
+		byID:    make(map[string]int, len(c.styles)+1),
+	}
+	copy(next.styles, c.styles)
+
+	for id, i := range c.byID {
+		next.byID[id] = i
+	}
+
+	if i, found := next.byID[style.ID]; found {
+		next.styles[i] = style
+	} else {
+		next.byID[style.ID] = len(next.styles)
+		next.styles = append(next.styles, style)
+	}
+
+	return next
+}
+
+// Without returns the next version of the catalog without the style with
+// the given ID.
+func (c *Catalog) Without(id string) *Catalog {
+	next := &Catalog{
+		version: c.version + 1,
+		styles:  make([]BeerStyle, 0, len(c.styles)),
+		byID:    make(map[string]int, len(c.styles)),
+	}
+
+	for _, style := range c.styles {
+		if style.ID == id {
+			continue
+		}
+		next.byID[style.ID] = len(next.styles)
+		next.styles = append(next.styles, style)
+	}
+
+	return next
+}
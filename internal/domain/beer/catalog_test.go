@@ -0,0 +1,134 @@
+package beer_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// referenceBestStyle is the original sort-based selection, kept to check
+// SelectBestStyle against.
+func referenceBestStyle(styles []domain.BeerStyle, targetTemp float64) domain.BeerStyle {
+	sorted := append([]domain.BeerStyle(nil), styles...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, dj := sorted[i].DistanceTo(targetTemp), sorted[j].DistanceTo(targetTemp)
+		if di != dj {
+			return di < dj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted[0]
+}
+
+/*
+	TESTS
+*/
+
+func TestCatalog_CopyOnWrite(t *testing.T) {
+	input := []domain.BeerStyle{
+		{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10},
+		{ID: "2", Name: "Dunkel", MinTemp: -8, MaxTemp: 2},
+	}
+
+	v0 := domain.NewCatalog(0, input)
+	input[0].Name = "Changed"
+
+	if style, _ := v0.FindByID("1"); style.Name != "IPA" {
+		t.Fatalf("expected the catalog to copy its input, got %s", style.Name)
+	}
+
+	v1 := v0.With(domain.BeerStyle{ID: "3", Name: "Pilsens", MinTemp: -2, MaxTemp: 4})
+	v2 := v1.With(domain.BeerStyle{ID: "1", Name: "Session IPA", MinTemp: -7, MaxTemp: 10})
+	v3 := v2.Without("2")
+
+	tests := []struct {
+		name        string
+		catalog     *domain.Catalog
+		wantVersion uint64
+		wantNames   []string
+	}{
+		{"original", v0, 0, []string{"IPA", "Dunkel"}},
+		{"added", v1, 1, []string{"IPA", "Dunkel", "Pilsens"}},
+		{"replaced in place", v2, 2, []string{"Session IPA", "Dunkel", "Pilsens"}},
+		{"removed", v3, 3, []string{"Session IPA", "Pilsens"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.catalog.Version() != tt.wantVersion {
+				t.Errorf("expected version %d, got %d", tt.wantVersion, tt.catalog.Version())
+			}
+
+			styles := tt.catalog.Styles()
+			if len(styles) != len(tt.wantNames) || tt.catalog.Len() != len(tt.wantNames) {
+				t.Fatalf("expected %v, got %+v", tt.wantNames, styles)
+			}
+
+			for i, name := range tt.wantNames {
+				if styles[i].Name != name {
+					t.Errorf("expected style %d to be %s, got %s", i, name, styles[i].Name)
+				}
+
+				if got, found := tt.catalog.FindByID(styles[i].ID); !found || got.Name != name {
+					t.Errorf("expected %s by ID, got %+v (found=%v)", name, got, found)
+				}
+			}
+		})
+	}
+
+	// Styles returns a copy
+	v3.Styles()[0].Name = "Changed"
+	if style, _ := v3.FindByID("1"); style.Name != "Session IPA" {
+		t.Errorf("expected Styles to return a copy, got %s", style.Name)
+	}
+
+	if _, found := v3.FindByID("2"); found {
+		t.Errorf("expected the removed style to be gone")
+	}
+}
+
+func TestSelectBestStyle_MatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for round := range 200 {
+		n := 1 + rng.IntN(30)
+		styles := make([]domain.BeerStyle, 0, n)
+
+		for i := range n {
+			// Half-degree grid and few names: plenty of distance and name ties
+			low := float64(rng.IntN(30)-15) / 2
+			styles = append(styles, domain.BeerStyle{
+				ID:      fmt.Sprint(i),
+				Name:    fmt.Sprintf("Style %d", rng.IntN(5)),
+				MinTemp: low,
+				MaxTemp: low + float64(rng.IntN(10))/2,
+			})
+		}
+
+		catalog := domain.NewCatalog(0, styles)
+
+		for temp := -12.0; temp <= 12; temp += 0.25 {
+			want := referenceBestStyle(styles, temp)
+
+			got, err := domain.SelectBestStyle(styles, temp)
+			if err != nil || got != want {
+				t.Fatalf("round %d, %v°C: expected %+v, got %+v (err=%v)", round, temp, want, got, err)
+			}
+
+			if got, _ := catalog.SelectBestStyle(temp); got != want {
+				t.Fatalf("round %d, %v°C: expected catalog to select %+v, got %+v", round, temp, want, got)
+			}
+		}
+	}
+}
+
+func TestCatalog_SelectBestStyleEmpty(t *testing.T) {
+	if _, err := domain.NewCatalog(0, nil).SelectBestStyle(0); err != domain.ErrEmptyBeerStyleList {
+		t.Fatalf("expected ErrEmptyBeerStyleList, got %v", err)
+	}
+}
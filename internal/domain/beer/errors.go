@@ -17,4 +17,8 @@ var (
 	// ErrEmptyBeerStyleList is returned when no beer styles are available
 	// to perform a selection.
 	ErrEmptyBeerStyleList = errors.New("beer style list is empty")
+
+	// ErrNoStyleForTemperature is returned when no beer style's range
+	// contains the target temperature.
+	ErrNoStyleForTemperature = errors.New("no beer style for temperature")
 )
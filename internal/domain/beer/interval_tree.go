@@ -0,0 +1,128 @@
+package beer
+
+import "sort"
+
+// StyleIntervalTree indexes styles by their [MinTemp, MaxTemp] range, for
+// strategies that only consider styles whose range contains the target
+// temperature.
+//
+// It is an augmented interval tree laid over a slice sorted by MinTemp:
+// the node of a range [lo, hi) is its middle element, and maxEnd holds the
+// largest MaxTemp below each node, so whole subtrees that end before the
+// target are skipped. A stabbing query costs O(min(N, (K+1) log N)) for K
+// matches: each match may cost a root-to-leaf walk, but subtrees without
+// one are pruned. A StyleIntervalTree is immutable and safe for
+// concurrent use.
+type StyleIntervalTree struct {
+	entries []indexedStyle
+	maxEnd  []float64
+}
+
+// NewStyleIntervalTree builds the tree in O(N log N).
+func NewStyleIntervalTree(styles []BeerStyle) *StyleIntervalTree {
+	entries := make([]indexedStyle, 0, len(styles))
+	for i, style := range styles {
+		entries = append(entries, indexedStyle{
+			style:    style,
+			average:  style.AverageTemperature(),
+			position: i,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].style.MinTemp < entries[j].style.MinTemp
+	})
+
+	t := &StyleIntervalTree{
+		entries: entries,
+		maxEnd:  make([]float64, len(entries)),
+	}
+	if len(entries) > 0 {
+		t.build(0, len(entries))
+	}
+
+	return t
+}
+
+// build fills maxEnd for the subtree over [lo, hi) and returns its value.
+func (t *StyleIntervalTree) build(lo, hi int) float64 {
+	mid := (lo + hi) / 2
+
+	end := t.entries[mid].style.MaxTemp
+	if lo < mid {
+		end = max(end, t.build(lo, mid))
+	}
+	if mid+1 < hi {
+		end = max(end, t.build(mid+1, hi))
+	}
+
+	t.maxEnd[mid] = end
+	return end
+}
+
+// Containing returns the styles whose range contains temperature
+// (bounds included), in no particular order.
+func (t *StyleIntervalTree) Containing(temperature float64) []BeerStyle {
+	var styles []BeerStyle
+	t.stab(0, len(t.entries), temperature, func(e indexedStyle) {
+		styles = append(styles, e.style)
+	})
+	return styles
+}
+
+// SelectBestContainingStyle applies the SelectBestStyle rule to the
+// styles whose range contains targetTemp: the closest average wins, ties
+// by name. It returns ErrNoStyleForTemperature when no range contains it.
+func (t *StyleIntervalTree) SelectBestContainingStyle(targetTemp float64) (BeerStyle, error) {
+	if len(t.entries) == 0 {
+		return BeerStyle{}, ErrEmptyBeerStyleList
+	}
+
+	var (
+		best         indexedStyle
+		bestDistance float64
+		found        bool
+	)
+
+	t.stab(0, len(t.entries), targetTemp, func(e indexedStyle) {
+		distance := e.style.DistanceTo(targetTemp)
+
+		if !found || distance < bestDistance || (distance == bestDistance && e.preferredTo(best)) {
+			best, bestDistance, found = e, distance, true
+		}
+	})
+
+	if !found {
+		return BeerStyle{}, ErrNoStyleForTemperature
+	}
+	return best.style, nil
+}
+
+// stab calls fn for every entry in [lo, hi) whose range contains temperature.
+func (t *StyleIntervalTree) stab(lo, hi int, temperature float64, fn func(indexedStyle)) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+
+	// Nothing below this node reaches temperature
+	if t.maxEnd[mid] < temperature {
+		return
+	}
+
+	t.stab(lo, mid, temperature, fn)
+
+	e := t.entries[mid]
+
+	// This node and everything to its right start after temperature
+	if e.style.MinTemp > temperature {
+		return
+	}
+
+	if temperature <= e.style.MaxTemp {
+		fn(e)
+	}
+
+	t.stab(mid+1, hi, temperature, fn)
+}
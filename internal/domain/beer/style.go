@@ -1,9 +1,6 @@
 package beer
 
-import (
-	"math"
-	"sort"
-)
+import "math"
 
 // BeerStyle represents a beer style and its ideal temperature range.
 // This is a core domain entity and must not depend on external layers.
@@ -47,31 +44,56 @@ func (b BeerStyle) DistanceTo(targetTemp float64) float64 {
 // Selection rules:
 // 1. Choose the style whose average temperature is closest to the target.
 // 2. In case of a tie, select the style by alphabetical order (lexicographical).
+//
+// It scans styles once, without sorting or copying them.
 func SelectBestStyle(styles []BeerStyle, targetTemp float64) (BeerStyle, error) {
 	if len(styles) == 0 {
 		return BeerStyle{}, ErrEmptyBeerStyleList
 	}
 
-	type candidate struct {
-		style    BeerStyle
-		distance float64
+	best := styles[0]
+	bestDistance := best.DistanceTo(targetTemp)
+
+	for _, style := range styles[1:] {
+		distance := style.DistanceTo(targetTemp)
+
+		if distance < bestDistance || (distance == bestDistance && style.Name < best.Name) {
+			best = style
+			bestDistance = distance
+		}
 	}
 
-	candidates := make([]candidate, 0, len(styles))
+	return best, nil
+}
 
-	for _, style := range styles {
-		candidates = append(candidates, candidate{
-			style:    style,
-			distance: style.DistanceTo(targetTemp),
-		})
+// SelectBestContainingStyle applies the SelectBestStyle rules to the
+// styles whose range contains targetTemp (bounds included). It returns
+// ErrNoStyleForTemperature when no range contains it.
+func SelectBestContainingStyle(styles []BeerStyle, targetTemp float64) (BeerStyle, error) {
+	if len(styles) == 0 {
+		return BeerStyle{}, ErrEmptyBeerStyleList
 	}
 
-	sort.SliceStable(candidates, func(i, j int) bool {
-		if candidates[i].distance != candidates[j].distance {
-			return candidates[i].distance < candidates[j].distance
+	var (
+		best         BeerStyle
+		bestDistance float64
+		found        bool
+	)
+
+	for _, style := range styles {
+		if targetTemp < style.MinTemp || targetTemp > style.MaxTemp {
+			continue
 		}
-		return candidates[i].style.Name < candidates[j].style.Name
-	})
 
-	return candidates[0].style, nil
+		distance := style.DistanceTo(targetTemp)
+
+		if !found || distance < bestDistance || (distance == bestDistance && style.Name < best.Name) {
+			best, bestDistance, found = style, distance, true
+		}
+	}
+
+	if !found {
+		return BeerStyle{}, ErrNoStyleForTemperature
+	}
+	return best, nil
 }
@@ -0,0 +1,124 @@
+package beer
+
+import "sort"
+
+// StyleIndex answers SelectBestStyle in O(log N): styles are sorted once
+// by average temperature and the nearest ones are found by binary search.
+//
+// It gives exactly the answers of SelectBestStyle on the styles it was
+// built from, ties included. A StyleIndex is immutable and safe for
+// concurrent use.
+type StyleIndex struct {
+	entries []indexedStyle
+}
+
+// indexedStyle is a style with its precomputed average temperature and
+// its position in the input, the last tie-breaker of SelectBestStyle.
+type indexedStyle struct {
+	style    BeerStyle
+	average  float64
+	position int
+}
+
+// before orders entries by average temperature, then as SelectBestStyle
+// breaks ties: by name, then by input position.
+func (e indexedStyle) before(other indexedStyle) bool {
+	if e.average != other.average {
+		return e.average < other.average
+	}
+	return e.preferredTo(other)
+}
+
+// preferredTo breaks a distance tie as SelectBestStyle does.
+func (e indexedStyle) preferredTo(other indexedStyle) bool {
+	if e.style.Name != other.style.Name {
+		return e.style.Name < other.style.Name
+	}
+	return e.position < other.position
+}
+
+// NewStyleIndex builds the index in O(N log N).
+func NewStyleIndex(styles []BeerStyle) *StyleIndex {
+	entries := make([]indexedStyle, 0, len(styles))
+	for i, style := range styles {
+		entries = append(entries, indexedStyle{
+			style:    style,
+			average:  style.AverageTemperature(),
+			position: i,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].before(entries[j])
+	})
+
+	return &StyleIndex{entries: entries}
+}
+
+// Len returns the number of indexed styles.
+func (x *StyleIndex) Len() int {
+	return len(x.entries)
+}
+
+// SelectBestStyle returns the style SelectBestStyle would select.
+//
+// The nearest averages are the last one below targetTemp and the first
+// one at or above it. Styles sharing an average are contiguous and sorted
+// by name, so only the first entry of each group is a candidate. Groups
+// are visited outwards while their distance still ties: distances are
+// rounded, so two different averages can be exactly as far.
+func (x *StyleIndex) SelectBestStyle(targetTemp float64) (BeerStyle, error) {
+	n := len(x.entries)
+	if n == 0 {
+		return BeerStyle{}, ErrEmptyBeerStyleList
+	}
+
+	var (
+		best         indexedStyle
+		bestDistance float64
+		found        bool
+	)
+
+	consider := func(e indexedStyle, distance float64) {
+		if !found || distance < bestDistance || (distance == bestDistance && e.preferredTo(best)) {
+			best, bestDistance, found = e, distance, true
+		}
+	}
+
+	// First entry whose average is >= targetTemp
+	above := sort.Search(n, func(i int) bool {
+		return x.entries[i].average >= targetTemp
+	})
+
+	// Groups below, nearest first
+	if above > 0 {
+		// Computed as in BeerStyle.DistanceTo, so ties match exactly
+		distance := x.entries[above-1].style.DistanceTo(targetTemp)
+
+		for end := above; end > 0 && x.entries[end-1].style.DistanceTo(targetTemp) == distance; {
+			average := x.entries[end-1].average
+			start := sort.Search(end, func(i int) bool {
+				return x.entries[i].average >= average
+			})
+
+			consider(x.entries[start], distance)
+			end = start
+		}
+	}
+
+	// Groups at or above, nearest first
+	if above < n {
+		distance := x.entries[above].style.DistanceTo(targetTemp)
+
+		for start := above; start < n && x.entries[start].style.DistanceTo(targetTemp) == distance; {
+			consider(x.entries[start], distance)
+
+			average := x.entries[start].average
+			start += sort.Search(n-start, func(i int) bool {
+				return x.entries[start+i].average > average
+			})
+		}
+	}
+
+	return best.style, nil
+}
@@ -0,0 +1,259 @@
+package beer_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// randomStyles builds n styles on a half-degree grid with few names, so
+// distance ties and name ties are frequent.
+func randomStyles(rng *rand.Rand, n, names int) []domain.BeerStyle {
+	styles := make([]domain.BeerStyle, 0, n)
+	for i := range n {
+		low := float64(rng.IntN(40)-20) / 2
+		styles = append(styles, domain.BeerStyle{
+			ID:      fmt.Sprint(i),
+			Name:    fmt.Sprintf("Style %d", rng.IntN(names)),
+			MinTemp: low,
+			MaxTemp: low + float64(rng.IntN(12))/2,
+		})
+	}
+	return styles
+}
+
+// containingReference filters the styles whose range contains temp and
+// applies SelectBestStyle to them.
+func containingReference(styles []domain.BeerStyle, temp float64) (domain.BeerStyle, error) {
+	var containing []domain.BeerStyle
+	for _, style := range styles {
+		if style.MinTemp <= temp && temp <= style.MaxTemp {
+			containing = append(containing, style)
+		}
+	}
+
+	if len(containing) == 0 {
+		return domain.BeerStyle{}, domain.ErrNoStyleForTemperature
+	}
+	return domain.SelectBestStyle(containing, temp)
+}
+
+/*
+	TESTS
+*/
+
+func TestStyleIndex_MatchesSelectBestStyle(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	tests := []struct {
+		name  string
+		sizes []int
+		names int
+	}{
+		{name: "small catalogs, many ties", sizes: []int{1, 2, 3, 5, 8}, names: 3},
+		{name: "medium catalogs", sizes: []int{20, 50, 100}, names: 10},
+		{name: "large catalogs", sizes: []int{1000}, names: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for round := range 50 {
+				for _, size := range tt.sizes {
+					styles := randomStyles(rng, size, tt.names)
+					index := domain.NewStyleIndex(styles)
+
+					// Grid points, exact averages and points between them
+					for temp := -13.0; temp <= 13; temp += 0.125 {
+						want, _ := domain.SelectBestStyle(styles, temp)
+
+						got, err := index.SelectBestStyle(temp)
+						if err != nil || got != want {
+							t.Fatalf("round %d, %d styles, %v°C: expected %+v, got %+v (err=%v)",
+								round, size, temp, want, got, err)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStyleIndex_TieGroups(t *testing.T) {
+	styles := []domain.BeerStyle{
+		{ID: "1", Name: "Weizenbier", MinTemp: -4, MaxTemp: 6}, // avg 1
+		{ID: "2", Name: "Red Ale", MinTemp: -5, MaxTemp: 5},    // avg 0
+		{ID: "3", Name: "Pilsens", MinTemp: -2, MaxTemp: 4},    // avg 1
+		{ID: "4", Name: "Dunkel", MinTemp: -8, MaxTemp: 2},     // avg -3
+		{ID: "5", Name: "Amber", MinTemp: 2, MaxTemp: 2},       // avg 2
+		{ID: "6", Name: "Pilsens", MinTemp: 0, MaxTemp: 2},     // avg 1, same name as 3
+	}
+
+	tests := []struct {
+		name   string
+		temp   float64
+		wantID string
+	}{
+		{"exact average, group sorted by name", 1, "3"},
+		{"equidistant groups, alphabetical wins", 1.5, "5"},
+		{"equidistant groups below and above", 0.5, "3"},
+		{"below every average", -20, "4"},
+		{"above every average", 20, "5"},
+		{"single group", 0, "2"},
+	}
+
+	index := domain.NewStyleIndex(styles)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _ := domain.SelectBestStyle(styles, tt.temp)
+			if want.ID != tt.wantID {
+				t.Fatalf("bad test case: SelectBestStyle picks %s", want.ID)
+			}
+
+			got, err := index.SelectBestStyle(tt.temp)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("expected style %s, got %s (%s)", tt.wantID, got.ID, got.Name)
+			}
+		})
+	}
+}
+
+func TestStyleIndex_RoundedDistanceTies(t *testing.T) {
+	// Both averages are so close to each other, relative to the target,
+	// that their distances round to the same value: the name decides
+	styles := []domain.BeerStyle{
+		{ID: "1", Name: "Zwickel", MinTemp: -2e-17, MaxTemp: -2e-17},
+		{ID: "2", Name: "Altbier", MinTemp: -4e-17, MaxTemp: -4e-17},
+		{ID: "3", Name: "Kölsch", MinTemp: 0.2 + 1e-17, MaxTemp: 0.2 + 1e-17},
+	}
+
+	for _, temp := range []float64{0.1, 1e3, -1e3, math.Nextafter(0.1, 1)} {
+		want, _ := domain.SelectBestStyle(styles, temp)
+
+		got, err := domain.NewStyleIndex(styles).SelectBestStyle(temp)
+		if err != nil || got != want {
+			t.Errorf("%v°C: expected %s, got %s (err=%v)", temp, want.Name, got.Name, err)
+		}
+	}
+}
+
+func TestStyleIndex_Empty(t *testing.T) {
+	if _, err := domain.NewStyleIndex(nil).SelectBestStyle(0); !errors.Is(err, domain.ErrEmptyBeerStyleList) {
+		t.Fatalf("expected ErrEmptyBeerStyleList, got %v", err)
+	}
+}
+
+func TestStyleIntervalTree_MatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+
+	for round := range 100 {
+		styles := randomStyles(rng, 1+rng.IntN(200), 8)
+		tree := domain.NewStyleIntervalTree(styles)
+		catalog := domain.NewCatalog(0, styles)
+
+		for temp := -13.0; temp <= 17; temp += 0.25 {
+			want, wantErr := containingReference(styles, temp)
+
+			got, err := tree.SelectBestContainingStyle(temp)
+			if !errors.Is(err, wantErr) || got != want {
+				t.Fatalf("round %d, %v°C: expected %+v (err=%v), got %+v (err=%v)",
+					round, temp, want, wantErr, got, err)
+			}
+
+			got, err = domain.SelectBestContainingStyle(styles, temp)
+			if !errors.Is(err, wantErr) || got != want {
+				t.Fatalf("round %d, %v°C: expected linear scan to select %+v (err=%v), got %+v (err=%v)",
+					round, temp, want, wantErr, got, err)
+			}
+
+			got, err = catalog.SelectBestContainingStyle(temp)
+			if !errors.Is(err, wantErr) || got != want {
+				t.Fatalf("round %d, %v°C: expected catalog to select %+v (err=%v), got %+v (err=%v)",
+					round, temp, want, wantErr, got, err)
+			}
+
+			containing := 0
+			for _, style := range styles {
+				if style.MinTemp <= temp && temp <= style.MaxTemp {
+					containing++
+				}
+			}
+			if got := len(tree.Containing(temp)); got != containing {
+				t.Fatalf("round %d, %v°C: expected %d containing styles, got %d", round, temp, containing, got)
+			}
+		}
+	}
+}
+
+func TestStyleIntervalTree_Errors(t *testing.T) {
+	if _, err := domain.NewStyleIntervalTree(nil).SelectBestContainingStyle(0); !errors.Is(err, domain.ErrEmptyBeerStyleList) {
+		t.Errorf("expected ErrEmptyBeerStyleList, got %v", err)
+	}
+
+	tree := domain.NewStyleIntervalTree([]domain.BeerStyle{{Name: "IPA", MinTemp: -7, MaxTemp: 10}})
+	if _, err := tree.SelectBestContainingStyle(11); !errors.Is(err, domain.ErrNoStyleForTemperature) {
+		t.Errorf("expected ErrNoStyleForTemperature, got %v", err)
+	}
+}
+
+/*
+	BENCHMARKS
+*/
+
+func BenchmarkSelectBestStyle(b *testing.B) {
+	for _, size := range []int{10, 100, 1000, 10000} {
+		styles := randomStyles(rand.New(rand.NewPCG(7, 8)), size, size)
+		index := domain.NewStyleIndex(styles)
+
+		b.Run(fmt.Sprintf("styles=%d/linear", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				_, _ = domain.SelectBestStyle(styles, float64(i%26)-13)
+			}
+		})
+
+		b.Run(fmt.Sprintf("styles=%d/index", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				_, _ = index.SelectBestStyle(float64(i%26) - 13)
+			}
+		})
+	}
+}
+
+func BenchmarkSelectBestContainingStyle(b *testing.B) {
+	for _, size := range []int{100, 10000} {
+		styles := randomStyles(rand.New(rand.NewPCG(9, 10)), size, size)
+		tree := domain.NewStyleIntervalTree(styles)
+
+		b.Run(fmt.Sprintf("styles=%d/filter", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				_, _ = containingReference(styles, float64(i%26)-13)
+			}
+		})
+
+		b.Run(fmt.Sprintf("styles=%d/interval-tree", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				_, _ = tree.SelectBestContainingStyle(float64(i%26) - 13)
+			}
+		})
+	}
+}
+
+func BenchmarkNewStyleIndex(b *testing.B) {
+	styles := randomStyles(rand.New(rand.NewPCG(11, 12)), 10000, 10000)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		domain.NewStyleIndex(styles)
+	}
+}
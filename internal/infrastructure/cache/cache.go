@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Cache is the contract every cache backend implements, so decorators
+// such as the playlist cache do not depend on a concrete store.
+//
+// Backends are best effort: a failing backend reports misses (and counts
+// the failure in Stats) instead of failing the caller.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored under key, if any.
+	Get(key K) (V, bool)
+
+	// Set stores value under key for ttl (zero means no expiry).
+	// It reports whether the value was accepted.
+	Set(key K, value V, ttl time.Duration) bool
+
+	// Delete removes key.
+	Delete(key K)
+
+	// DeleteByPrefix removes every key starting with prefix and
+	// returns how many were removed.
+	DeleteByPrefix(prefix string) int
+
+	// Entries returns the live keys starting with prefix, sorted, with
+	// their remaining TTL. A positive limit caps how many are returned.
+	Entries(prefix string, limit int) []Entry
+
+	// Stats returns the cache counters.
+	Stats() Stats
+}
+
+// Stats are cache counters since the cache was created.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+
+	// Keys is the number of live keys.
+	Keys int
+
+	// Evictions counts entries the cache dropped on its own (capacity or
+	// expiry), not explicit deletes. Zero for backends that do not report it.
+	Evictions uint64
+
+	// Cost is the cost of the live entries and MaxCost the capacity, in the
+	// backend's unit. Zero for backends that do not report it.
+	Cost    int64
+	MaxCost int64
+
+	// Errors counts backend failures reported as misses or dropped writes.
+	Errors uint64
+}
+
+// Entry describes a live cache key.
+type Entry struct {
+	Key string
+
+	// TTL is the remaining time to live; zero means no expiry.
+	TTL time.Duration
+}
+
+// HitRatio returns hits / (hits + misses), or 0 before any lookup.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Codec serializes values for backends that store bytes.
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec is a Codec using encoding/json.
+type JSONCodec[V any] struct{}
+
+func (JSONCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// limitKeys sorts keys and keeps at most limit of them (all when limit <= 0).
+func limitKeys(keys []string, limit int) []string {
+	sort.Strings(keys)
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
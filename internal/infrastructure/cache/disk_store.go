@@ -0,0 +1,573 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrDiskStoreClosed is reported by the checks of a closed DiskStore.
+	ErrDiskStoreClosed = errors.New("disk store closed")
+
+	// ErrRecordTooLarge is returned by Put for a key or value longer than
+	// maxRecordField, which could not be read back.
+	ErrRecordTooLarge = errors.New("disk record too large")
+)
+
+// DiskStore is an embedded key-value file: an append-only log of
+// checksummed records, each carrying its expiry, indexed in memory.
+//
+// The index is built in the background when the store is opened, so
+// opening never blocks on a large file; until then every read is a miss.
+// Values stay on disk and are read on demand.
+//
+// When the file grows past maxBytes it is compacted: expired, deleted and
+// overwritten records are dropped and, if still too large, the entries
+// closest to expiry are evicted. Corrupt records (bad checksum, truncated
+// writes) are detected and skipped.
+type DiskStore struct {
+	path     string
+	maxBytes int64
+
+	mu    sync.RWMutex
+	file  *os.File
+	size  int64
+	index map[string]diskEntry
+
+	loaded  chan struct{}
+	corrupt atomic.Uint64
+	evicted atomic.Uint64
+}
+
+// diskEntry locates a live value in the file.
+type diskEntry struct {
+	offset    int64 // of the value
+	length    int
+	expiresAt time.Time
+}
+
+func (e diskEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Record layout (little endian):
+//
+//	magic     uint32
+//	checksum  uint32  CRC-32 of everything after it
+//	flags     uint8
+//	expiresAt int64   unix nanoseconds, 0 = never
+//	keyLen    uint32
+//	valueLen  uint32
+//	key, value
+const (
+	recordMagic      uint32 = 0x4b484331 // "KHC1"
+	recordHeaderSize        = 4 + 4 + 1 + 8 + 4 + 4
+
+	flagTombstone uint8 = 1
+
+	// maxRecordField bounds key and value sizes, both written and read
+	// back from disk, so a corrupt length cannot trigger a huge allocation.
+	maxRecordField = 16 << 20
+
+	// compactTarget is the fraction of maxBytes compaction shrinks to.
+	compactTarget = 0.8
+)
+
+// OpenDiskStore opens (or creates) the store at path, bounded to maxBytes.
+func OpenDiskStore(path string, maxBytes int64) (*DiskStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DiskStore{
+		path:     path,
+		maxBytes: maxBytes,
+		file:     file,
+		index:    make(map[string]diskEntry),
+		loaded:   make(chan struct{}),
+	}
+
+	go s.load()
+
+	return s, nil
+}
+
+// Loaded is closed once the index has been built.
+func (s *DiskStore) Loaded() <-chan struct{} {
+	return s.loaded
+}
+
+// Get returns the value stored under key and its expiry.
+func (s *DiskStore) Get(key string) ([]byte, time.Time, bool) {
+	if !s.isLoaded() {
+		return nil, time.Time{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, found := s.index[key]
+	if !found || e.expired(time.Now()) || s.file == nil {
+		return nil, time.Time{}, false
+	}
+
+	value := make([]byte, e.length)
+	if _, err := s.file.ReadAt(value, e.offset); err != nil {
+		s.corrupt.Add(1)
+		return nil, time.Time{}, false
+	}
+
+	return value, e.expiresAt, true
+}
+
+// Put appends a record for key. It waits for the index to be loaded.
+func (s *DiskStore) Put(key string, value []byte, expiresAt time.Time) error {
+	if len(key) > maxRecordField || len(value) > maxRecordField {
+		return ErrRecordTooLarge
+	}
+
+	<-s.loaded
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(key, value, expiresAt, 0); err != nil {
+		return err
+	}
+
+	if s.maxBytes > 0 && s.size > s.maxBytes {
+		return s.compactLocked()
+	}
+	return nil
+}
+
+// Delete appends a tombstone for key, if it is stored.
+func (s *DiskStore) Delete(key string) error {
+	<-s.loaded
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.index[key]; !found {
+		return nil
+	}
+
+	return s.appendLocked(key, nil, time.Time{}, flagTombstone)
+}
+
+// Keys returns the live keys starting with prefix, sorted.
+func (s *DiskStore) Keys(prefix string) []string {
+	if !s.isLoaded() {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]string, 0, len(s.index))
+	for key, e := range s.index {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns how many keys are indexed, without walking them. Expired
+// keys are counted until the next compaction drops them.
+func (s *DiskStore) Len() int {
+	if !s.isLoaded() {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.index)
+}
+
+// ExpiresAt returns the expiry of key (zero for no expiry).
+func (s *DiskStore) ExpiresAt(key string) (time.Time, bool) {
+	if !s.isLoaded() {
+		return time.Time{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, found := s.index[key]
+	if !found || e.expired(time.Now()) {
+		return time.Time{}, false
+	}
+	return e.expiresAt, true
+}
+
+// Size returns the size of the file in bytes.
+func (s *DiskStore) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.size
+}
+
+// Corrupt returns how many corrupt records were skipped.
+func (s *DiskStore) Corrupt() uint64 {
+	return s.corrupt.Load()
+}
+
+// Evicted returns how many live entries compaction dropped to stay
+// within maxBytes.
+func (s *DiskStore) Evicted() uint64 {
+	return s.evicted.Load()
+}
+
+// Compact rewrites the file with only the live entries.
+func (s *DiskStore) Compact() error {
+	<-s.loaded
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compactLocked()
+}
+
+// Close closes the file.
+func (s *DiskStore) Close() error {
+	<-s.loaded
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// CheckHealth fails once the store is closed or its file is gone.
+func (s *DiskStore) CheckHealth(context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.file == nil {
+		return ErrDiskStoreClosed
+	}
+
+	if _, err := os.Stat(s.path); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *DiskStore) isLoaded() bool {
+	select {
+	case <-s.loaded:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+	---------- Loading ----------
+*/
+
+// load builds the index from the file, skipping corrupt records, and
+// truncates a torn write at the end of the file. Records are read one at
+// a time, so loading a large file does not hold it all in memory.
+func (s *DiskStore) load() {
+	defer close(s.loaded)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	r := bufio.NewReader(io.NewSectionReader(s.file, 0, 1<<62))
+	var offset, end int64 // end of the last valid record
+
+	for {
+		if _, err := r.Peek(1); err != nil {
+			break
+		}
+
+		rec, size, ok := readRecord(r)
+		if !ok {
+			s.corrupt.Add(1)
+
+			// Resynchronize on the next record marker
+			r.Reset(io.NewSectionReader(s.file, offset+1, 1<<62))
+			skipped, found := skipToMagic(r)
+			if !found {
+				break
+			}
+			offset += 1 + skipped
+			continue
+		}
+
+		if rec.flags&flagTombstone != 0 || (!rec.expiresAt.IsZero() && !now.Before(rec.expiresAt)) {
+			delete(s.index, rec.key)
+		} else {
+			s.index[rec.key] = diskEntry{
+				offset:    offset + int64(recordHeaderSize+len(rec.key)),
+				length:    rec.valueLen,
+				expiresAt: rec.expiresAt,
+			}
+		}
+
+		offset += int64(size)
+		end = offset
+	}
+
+	// Drop trailing garbage so new records are not appended after it
+	if info, err := s.file.Stat(); err == nil && info.Size() > end {
+		_ = s.file.Truncate(end)
+	}
+	s.size = end
+}
+
+/*
+	---------- Writing ----------
+*/
+
+func (s *DiskStore) appendLocked(
+	key string,
+	value []byte,
+	expiresAt time.Time,
+	flags uint8,
+) error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	rec := encodeRecord(key, value, expiresAt, flags)
+
+	if _, err := s.file.WriteAt(rec, s.size); err != nil {
+		return err
+	}
+
+	if flags&flagTombstone != 0 {
+		delete(s.index, key)
+	} else {
+		s.index[key] = diskEntry{
+			offset:    s.size + int64(recordHeaderSize+len(key)),
+			length:    len(value),
+			expiresAt: expiresAt,
+		}
+	}
+
+	s.size += int64(len(rec))
+	return nil
+}
+
+// compactLocked rewrites the live entries to a new file and swaps it in
+// atomically, evicting the entries closest to expiry while over budget.
+func (s *DiskStore) compactLocked() error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	now := time.Now()
+
+	type liveEntry struct {
+		key   string
+		entry diskEntry
+	}
+
+	live := make([]liveEntry, 0, len(s.index))
+	for key, e := range s.index {
+		if !e.expired(now) {
+			live = append(live, liveEntry{key, e})
+		}
+	}
+
+	// Keep entries that never expire, then the longest-lived ones
+	sort.Slice(live, func(i, j int) bool {
+		a, b := live[i].entry.expiresAt, live[j].entry.expiresAt
+		if a.IsZero() != b.IsZero() {
+			return a.IsZero()
+		}
+		return a.After(b)
+	})
+
+	target := int64(float64(s.maxBytes) * compactTarget)
+
+	tmpPath := s.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]diskEntry, len(live))
+	var size int64
+
+	for _, le := range live {
+		recordSize := int64(recordHeaderSize + len(le.key) + le.entry.length)
+		if s.maxBytes > 0 && size+recordSize > target {
+			s.evicted.Add(1)
+			continue
+		}
+
+		value := make([]byte, le.entry.length)
+		if _, err := s.file.ReadAt(value, le.entry.offset); err != nil {
+			s.corrupt.Add(1)
+			continue
+		}
+
+		rec := encodeRecord(le.key, value, le.entry.expiresAt, 0)
+		if _, err := tmp.WriteAt(rec, size); err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+
+		index[le.key] = diskEntry{
+			offset:    size + int64(recordHeaderSize+len(le.key)),
+			length:    le.entry.length,
+			expiresAt: le.entry.expiresAt,
+		}
+		size += int64(len(rec))
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	s.file.Close()
+	s.file = tmp
+	s.index = index
+	s.size = size
+
+	return nil
+}
+
+/*
+	---------- Encoding ----------
+*/
+
+type diskRecord struct {
+	flags     uint8
+	expiresAt time.Time
+	key       string
+	valueLen  int
+}
+
+func magicBytes() []byte {
+	return binary.LittleEndian.AppendUint32(nil, recordMagic)
+}
+
+func encodeRecord(key string, value []byte, expiresAt time.Time, flags uint8) []byte {
+	var expires int64
+	if !expiresAt.IsZero() {
+		expires = expiresAt.UnixNano()
+	}
+
+	buf := make([]byte, recordHeaderSize, recordHeaderSize+len(key)+len(value))
+	binary.LittleEndian.PutUint32(buf[0:4], recordMagic)
+	buf[8] = flags
+	binary.LittleEndian.PutUint64(buf[9:17], uint64(expires))
+	binary.LittleEndian.PutUint32(buf[17:21], uint32(len(key)))
+	binary.LittleEndian.PutUint32(buf[21:25], uint32(len(value)))
+	buf = append(buf, key...)
+	buf = append(buf, value...)
+
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(buf[8:]))
+	return buf
+}
+
+// readRecord reads the record at the start of r and returns its total
+// size. The value is only checksummed, not kept. ok is false for
+// truncated or corrupt records, leaving r anywhere inside them.
+func readRecord(r *bufio.Reader) (rec diskRecord, size int, ok bool) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return diskRecord{}, 0, false
+	}
+
+	if binary.LittleEndian.Uint32(header[0:4]) != recordMagic {
+		return diskRecord{}, 0, false
+	}
+
+	keyLen := int(binary.LittleEndian.Uint32(header[17:21]))
+	valueLen := int(binary.LittleEndian.Uint32(header[21:25]))
+	if keyLen > maxRecordField || valueLen > maxRecordField {
+		return diskRecord{}, 0, false
+	}
+
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return diskRecord{}, 0, false
+	}
+
+	checksum := crc32.NewIEEE()
+	checksum.Write(header[8:])
+	checksum.Write(key)
+	if _, err := io.CopyN(checksum, r, int64(valueLen)); err != nil {
+		return diskRecord{}, 0, false
+	}
+
+	if checksum.Sum32() != binary.LittleEndian.Uint32(header[4:8]) {
+		return diskRecord{}, 0, false
+	}
+
+	rec.flags = header[8]
+	if expires := int64(binary.LittleEndian.Uint64(header[9:17])); expires != 0 {
+		rec.expiresAt = time.Unix(0, expires)
+	}
+	rec.key = string(key)
+	rec.valueLen = valueLen
+
+	return rec, recordHeaderSize + keyLen + valueLen, true
+}
+
+// skipToMagic discards bytes from r up to the next record marker and
+// returns how many it skipped. found is false if there is none.
+func skipToMagic(r *bufio.Reader) (skipped int64, found bool) {
+	magic := magicBytes()
+
+	for {
+		if _, err := r.Peek(len(magic)); err != nil {
+			return skipped, false
+		}
+
+		buffered, _ := r.Peek(r.Buffered())
+		if i := bytes.Index(buffered, magic); i >= 0 {
+			_, _ = r.Discard(i)
+			return skipped + int64(i), true
+		}
+
+		// Keep the last bytes, a marker may straddle the next read
+		n := len(buffered) - len(magic) + 1
+		_, _ = r.Discard(n)
+		skipped += int64(n)
+	}
+}
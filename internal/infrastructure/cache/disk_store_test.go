@@ -0,0 +1,272 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+)
+
+func openDiskStore(t *testing.T, path string, maxBytes int64) *cache.DiskStore {
+	t.Helper()
+
+	store, err := cache.OpenDiskStore(path, maxBytes)
+	if err != nil {
+		t.Fatalf("failed to open disk store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	select {
+	case <-store.Loaded():
+	case <-time.After(time.Second):
+		t.Fatalf("disk store did not load in time")
+	}
+
+	return store
+}
+
+/*
+	TESTS
+*/
+
+func TestDiskStore_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+
+	store := openDiskStore(t, path, 1<<20)
+
+	_ = store.Put("spotify:playlist:IPA", []byte("IPA Party"), time.Now().Add(time.Hour))
+	_ = store.Put("spotify:playlist:Stout", []byte("Dark"), time.Time{})
+	_ = store.Put("spotify:playlist:Pilsen", []byte("old"), time.Now().Add(time.Hour))
+	_ = store.Put("spotify:playlist:Pilsen", []byte("new"), time.Now().Add(time.Hour))
+	_ = store.Put("spotify:playlist:Dunkel", []byte("gone"), time.Now().Add(time.Hour))
+	_ = store.Delete("spotify:playlist:Dunkel")
+	_ = store.Put("spotify:playlist:Lager", []byte("expiring"), time.Now().Add(20*time.Millisecond))
+	store.Close()
+
+	time.Sleep(30 * time.Millisecond)
+
+	reopened := openDiskStore(t, path, 1<<20)
+
+	tests := []struct {
+		key       string
+		want      string
+		wantFound bool
+	}{
+		{key: "spotify:playlist:IPA", want: "IPA Party", wantFound: true},
+		{key: "spotify:playlist:Stout", want: "Dark", wantFound: true},
+		{key: "spotify:playlist:Pilsen", want: "new", wantFound: true},
+		{key: "spotify:playlist:Dunkel", wantFound: false},
+		{key: "spotify:playlist:Lager", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, _, found := reopened.Get(tt.key)
+
+			if found != tt.wantFound {
+				t.Fatalf("expected found=%v, got %v", tt.wantFound, found)
+			}
+
+			if found && string(got) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiskStore_SkipsCorruptRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		want    []string
+	}{
+		{
+			name: "flipped byte in a record",
+			corrupt: func(data []byte) []byte {
+				i := bytes.Index(data, []byte("second-value"))
+				data[i] ^= 0xff
+				return data
+			},
+			want: []string{"first", "third"},
+		},
+		{
+			name: "torn write at the end",
+			corrupt: func(data []byte) []byte {
+				return data[:len(data)-5]
+			},
+			want: []string{"first", "second"},
+		},
+		{
+			name: "garbage between records",
+			corrupt: func(data []byte) []byte {
+				i := bytes.Index(data, []byte("second-value")) + len("second-value")
+				return append(data[:i:i], append([]byte("garbage!"), data[i:]...)...)
+			},
+			want: []string{"first", "second", "third"},
+		},
+		{
+			name: "length spanning the next records",
+			corrupt: func(data []byte) []byte {
+				// valueLen sits in the last 4 bytes of the 25-byte header
+				i := bytes.Index(data, []byte("second-value")) - len("second") - 4
+				binary.LittleEndian.PutUint32(data[i:], 1<<10)
+				return append(data, make([]byte, 1<<10)...)
+			},
+			want: []string{"first", "third"},
+		},
+		{
+			name: "garbage longer than a read buffer",
+			corrupt: func(data []byte) []byte {
+				i := bytes.Index(data, []byte("second-value")) + len("second-value")
+				garbage := bytes.Repeat([]byte("KHC"), 10<<10)
+				return append(data[:i:i], append(garbage, data[i:]...)...)
+			},
+			want: []string{"first", "second", "third"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "playlists.db")
+
+			store := openDiskStore(t, path, 1<<20)
+			for _, key := range []string{"first", "second", "third"} {
+				_ = store.Put(key, []byte(key+"-value"), time.Time{})
+			}
+			store.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read store: %v", err)
+			}
+			if err := os.WriteFile(path, tt.corrupt(data), 0o644); err != nil {
+				t.Fatalf("failed to write store: %v", err)
+			}
+
+			reopened := openDiskStore(t, path, 1<<20)
+
+			keys := reopened.Keys("")
+			if fmt.Sprint(keys) != fmt.Sprint(tt.want) {
+				t.Fatalf("expected keys %v, got %v", tt.want, keys)
+			}
+
+			if reopened.Corrupt() == 0 {
+				t.Errorf("expected the corrupt record to be counted")
+			}
+
+			// The store keeps working after skipping corrupt data
+			if err := reopened.Put("fourth", []byte("fourth-value"), time.Time{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, _, found := reopened.Get("fourth"); !found || string(got) != "fourth-value" {
+				t.Errorf("expected new record to be readable, got %q", got)
+			}
+		})
+	}
+}
+
+func TestDiskStore_SizeBoundedEviction(t *testing.T) {
+	const maxBytes = 4 << 10
+
+	path := filepath.Join(t.TempDir(), "playlists.db")
+	store := openDiskStore(t, path, maxBytes)
+
+	value := bytes.Repeat([]byte("x"), 200)
+	now := time.Now()
+
+	// Later keys expire later, so they are the ones worth keeping
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("spotify:playlist:%03d", i)
+		if err := store.Put(key, value, now.Add(time.Hour+time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if size := store.Size(); size > maxBytes {
+		t.Errorf("expected store to stay under %d bytes, got %d", maxBytes, size)
+	}
+
+	if _, _, found := store.Get("spotify:playlist:099"); !found {
+		t.Errorf("expected the longest-lived entry to be kept")
+	}
+
+	if _, _, found := store.Get("spotify:playlist:000"); found {
+		t.Errorf("expected the entry closest to expiry to be evicted")
+	}
+
+	if live, evicted := len(store.Keys("")), store.Evicted(); evicted == 0 || live+int(evicted) != 100 {
+		t.Errorf("expected every entry to be either live or evicted, got %d live and %d evicted", live, evicted)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat store: %v", err)
+	}
+	if info.Size() != store.Size() {
+		t.Errorf("expected file size %d, got %d", store.Size(), info.Size())
+	}
+}
+
+func TestDiskStore_RejectsOversizedRecords(t *testing.T) {
+	const maxField = 16 << 20
+
+	tests := []struct {
+		name  string
+		key   string
+		value []byte
+	}{
+		{
+			name:  "oversized key",
+			key:   string(bytes.Repeat([]byte("k"), maxField+1)),
+			value: []byte("value"),
+		},
+		{
+			name:  "oversized value",
+			key:   "spotify:playlist:IPA",
+			value: make([]byte, maxField+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "playlists.db")
+			store := openDiskStore(t, path, 0)
+
+			if err := store.Put(tt.key, tt.value, time.Time{}); !errors.Is(err, cache.ErrRecordTooLarge) {
+				t.Fatalf("expected ErrRecordTooLarge, got %v", err)
+			}
+
+			if size := store.Size(); size != 0 {
+				t.Errorf("expected nothing to be written, got %d bytes", size)
+			}
+		})
+	}
+}
+
+func TestDiskStore_CheckHealth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+	store := openDiskStore(t, path, 1<<20)
+
+	if err := store.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	if err := store.CheckHealth(context.Background()); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	store.Close()
+	if err := store.CheckHealth(context.Background()); !errors.Is(err, cache.ErrDiskStoreClosed) {
+		t.Fatalf("expected ErrDiskStoreClosed, got %v", err)
+	}
+}
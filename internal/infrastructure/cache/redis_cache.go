@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// scanBatch is the COUNT hint for SCAN when walking keys.
+const scanBatch = 100
+
+// RedisCache is a Cache stored in Redis, shared by every API replica.
+// Values are serialized with a Codec; keys are stored under a namespace
+// so several applications can share one Redis database.
+type RedisCache[K ~string, V any] struct {
+	client    *RedisClient
+	codec     Codec[V]
+	namespace string
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// NewRedisCache creates a Redis-backed cache whose keys are prefixed
+// with namespace (e.g. "karhub:").
+func NewRedisCache[K ~string, V any](
+	client *RedisClient,
+	codec Codec[V],
+	namespace string,
+) *RedisCache[K, V] {
+	return &RedisCache[K, V]{
+		client:    client,
+		codec:     codec,
+		namespace: namespace,
+	}
+}
+
+func (r *RedisCache[K, V]) Get(key K) (V, bool) {
+	var zero V
+
+	reply, err := r.client.Do(context.Background(), "GET", r.key(string(key)))
+	if errors.Is(err, ErrRedisNil) {
+		r.misses.Add(1)
+		return zero, false
+	}
+	if err != nil {
+		r.failedGet()
+		return zero, false
+	}
+
+	data, ok := reply.([]byte)
+	if !ok {
+		r.failedGet()
+		return zero, false
+	}
+
+	value, err := r.codec.Unmarshal(data)
+	if err != nil {
+		// An undecodable entry (e.g. an older format) is just a miss
+		r.failedGet()
+		return zero, false
+	}
+
+	r.hits.Add(1)
+	return value, true
+}
+
+func (r *RedisCache[K, V]) Set(key K, value V, ttl time.Duration) bool {
+	data, err := r.codec.Marshal(value)
+	if err != nil {
+		r.fail()
+		return false
+	}
+
+	args := []string{"SET", r.key(string(key)), string(data)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+
+	if _, err := r.client.Do(context.Background(), args...); err != nil {
+		r.fail()
+		return false
+	}
+	return true
+}
+
+func (r *RedisCache[K, V]) Delete(key K) {
+	if _, err := r.client.Do(context.Background(), "DEL", r.key(string(key))); err != nil {
+		r.fail()
+	}
+}
+
+// DeleteByPrefix walks the namespace with SCAN (never KEYS, which
+// blocks the server) and deletes the matching keys in batches.
+func (r *RedisCache[K, V]) DeleteByPrefix(prefix string) int {
+	deleted := 0
+
+	err := r.scan(prefix, func(keys []string) error {
+		reply, err := r.client.Do(context.Background(), append([]string{"DEL"}, keys...)...)
+		if err != nil {
+			return err
+		}
+		if n, ok := reply.(int64); ok {
+			deleted += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		r.fail()
+	}
+
+	return deleted
+}
+
+// Entries lists the matching keys with SCAN, then reads each TTL with
+// PTTL. Keys are returned without the namespace.
+func (r *RedisCache[K, V]) Entries(prefix string, limit int) []Entry {
+	var keys []string
+	if err := r.scan(prefix, func(batch []string) error {
+		for _, key := range batch {
+			keys = append(keys, strings.TrimPrefix(key, r.namespace))
+		}
+		return nil
+	}); err != nil {
+		r.fail()
+		return nil
+	}
+
+	keys = limitKeys(keys, limit)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		reply, err := r.client.Do(context.Background(), "PTTL", r.key(key))
+		if err != nil {
+			r.fail()
+			continue
+		}
+
+		ms, ok := reply.(int64)
+		switch {
+		case !ok:
+			r.fail()
+		case ms == -1: // no expiry
+			entries = append(entries, Entry{Key: key})
+		case ms >= 0:
+			entries = append(entries, Entry{Key: key, TTL: time.Duration(ms) * time.Millisecond})
+		}
+		// -2: deleted or expired since listed
+	}
+	return entries
+}
+
+// Stats returns the counters of this client. Keys is not reported:
+// counting them walks the whole namespace with SCAN, which metrics would
+// do on every scrape, at a cost growing with the cache; Entries lists
+// them on demand. Evictions and cost are server-wide in Redis, so they
+// are not reported either.
+func (r *RedisCache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+		Errors: r.errors.Load(),
+	}
+}
+
+// Close closes the idle connections to Redis.
+func (r *RedisCache[K, V]) Close() error {
+	return r.client.Close()
+}
+
+// CheckHealth pings Redis.
+func (r *RedisCache[K, V]) CheckHealth(ctx context.Context) error {
+	return r.client.Ping(ctx)
+}
+
+// scan calls fn with each non-empty batch of namespaced keys starting
+// with prefix.
+func (r *RedisCache[K, V]) scan(prefix string, fn func(keys []string) error) error {
+	pattern := escapeGlob(r.key(prefix)) + "*"
+	cursor := "0"
+
+	for {
+		reply, err := r.client.Do(
+			context.Background(),
+			"SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(scanBatch),
+		)
+		if err != nil {
+			return err
+		}
+
+		next, keys, err := parseScanReply(reply)
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		if next == "0" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (r *RedisCache[K, V]) key(key string) string {
+	return r.namespace + key
+}
+
+func (r *RedisCache[K, V]) fail() {
+	r.errors.Add(1)
+}
+
+// failedGet counts a lookup that failed as both an error and a miss.
+func (r *RedisCache[K, V]) failedGet() {
+	r.errors.Add(1)
+	r.misses.Add(1)
+}
+
+// parseScanReply splits a SCAN reply into the next cursor and the keys.
+func parseScanReply(reply any) (string, []string, error) {
+	items, ok := reply.([]any)
+	if !ok || len(items) != 2 {
+		return "", nil, errors.New("redis: malformed SCAN reply")
+	}
+
+	cursor, ok := items[0].([]byte)
+	if !ok {
+		return "", nil, errors.New("redis: malformed SCAN cursor")
+	}
+
+	rawKeys, ok := items[1].([]any)
+	if !ok {
+		return "", nil, errors.New("redis: malformed SCAN keys")
+	}
+
+	keys := make([]string, 0, len(rawKeys))
+	for _, k := range rawKeys {
+		if b, ok := k.([]byte); ok {
+			keys = append(keys, string(b))
+		}
+	}
+
+	return string(cursor), keys, nil
+}
+
+// escapeGlob escapes the characters Redis MATCH patterns treat specially.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
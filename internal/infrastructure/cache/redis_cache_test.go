@@ -0,0 +1,283 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+	"karhub-beer-machine/internal/infrastructure/cache/redistest"
+)
+
+type playlistValue struct {
+	Name   string `json:"name"`
+	Tracks int    `json:"tracks"`
+}
+
+func newRedisCache(
+	t *testing.T,
+	server *redistest.Server,
+	namespace string,
+	opts ...cache.RedisOption,
+) *cache.RedisCache[string, playlistValue] {
+	t.Helper()
+
+	client := cache.NewRedisClient(server.Addr(), opts...)
+	t.Cleanup(func() { client.Close() })
+
+	return cache.NewRedisCache[string](client, cache.JSONCodec[playlistValue]{}, namespace)
+}
+
+/*
+	TESTS
+*/
+
+func TestRedisCache_SetGetDelete(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:")
+
+	want := playlistValue{Name: "IPA Party", Tracks: 10}
+
+	if !c.Set("spotify:playlist:IPA", want, time.Minute) {
+		t.Fatalf("expected set to succeed")
+	}
+
+	got, found := c.Get("spotify:playlist:IPA")
+	if !found || got != want {
+		t.Fatalf("expected %+v, got %+v (found=%v)", want, got, found)
+	}
+
+	if keys := server.Keys(); len(keys) != 1 || keys[0] != "karhub:spotify:playlist:IPA" {
+		t.Errorf("expected namespaced key, got %v", keys)
+	}
+
+	c.Delete("spotify:playlist:IPA")
+
+	if _, found := c.Get("spotify:playlist:IPA"); found {
+		t.Fatalf("expected key to be deleted")
+	}
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:")
+
+	c.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	if _, found := c.Get("spotify:playlist:IPA"); found {
+		t.Fatalf("expected key to expire")
+	}
+}
+
+func TestRedisCache_DeleteByPrefix(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:")
+	other := newRedisCache(t, server, "other:")
+
+	// More keys than a single SCAN batch
+	for i := 0; i < 250; i++ {
+		c.Set("spotify:playlist:style-"+time.Duration(i).String(), playlistValue{}, time.Minute)
+	}
+	c.Set("spotify:playlist-uri:abc", playlistValue{}, time.Minute)
+	c.Set("spotify:playlist:a*b", playlistValue{}, time.Minute)
+	other.Set("spotify:playlist:IPA", playlistValue{}, time.Minute)
+
+	if got := c.DeleteByPrefix("spotify:playlist:style-"); got != 250 {
+		t.Errorf("expected 250 deleted, got %d", got)
+	}
+
+	// Glob characters in the prefix are literal
+	if got := c.DeleteByPrefix("spotify:playlist:a*"); got != 1 {
+		t.Errorf("expected 1 deleted, got %d", got)
+	}
+
+	if _, found := c.Get("spotify:playlist-uri:abc"); !found {
+		t.Errorf("expected key outside the prefix to be kept")
+	}
+
+	if _, found := other.Get("spotify:playlist:IPA"); !found {
+		t.Errorf("expected key in another namespace to be kept")
+	}
+
+	if entries := c.Entries("", 0); len(entries) != 1 {
+		t.Errorf("expected 1 key left in namespace, got %d", len(entries))
+	}
+}
+
+func TestRedisCache_StatsDoNotScan(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:")
+	c.Set("spotify:playlist:IPA", playlistValue{}, time.Minute)
+	c.Get("spotify:playlist:IPA")
+
+	for range 3 {
+		if stats := c.Stats(); stats.Hits != 1 || stats.Keys != 0 {
+			t.Fatalf("expected 1 hit and no key count, got %+v", stats)
+		}
+	}
+
+	if got := server.Commands("SCAN"); got != 0 {
+		t.Errorf("expected stats not to scan Redis, got %d SCAN commands", got)
+	}
+}
+
+func TestRedisCache_SharedAcrossClients(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	replicaA := newRedisCache(t, server, "karhub:")
+	replicaB := newRedisCache(t, server, "karhub:")
+
+	replicaA.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Minute)
+
+	if got, found := replicaB.Get("spotify:playlist:IPA"); !found || got.Name != "IPA Party" {
+		t.Fatalf("expected replica B to see replica A's entry, got %+v (found=%v)", got, found)
+	}
+}
+
+func TestRedisCache_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(server *redistest.Server)
+	}{
+		{
+			name:    "server down",
+			prepare: func(server *redistest.Server) { server.SetDown(true) },
+		},
+		{
+			name: "corrupt entry",
+			prepare: func(server *redistest.Server) {
+				server.Put("karhub:spotify:playlist:IPA", "{not json")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := redistest.NewServer()
+			defer server.Close()
+
+			c := newRedisCache(t, server, "karhub:", cache.WithRedisTimeout(100*time.Millisecond))
+
+			tt.prepare(server)
+
+			if _, found := c.Get("spotify:playlist:IPA"); found {
+				t.Fatalf("expected a miss")
+			}
+
+			stats := c.Stats()
+			if stats.Errors == 0 || stats.Misses != 1 {
+				t.Errorf("expected the failure to be counted as an error and a miss, got %+v", stats)
+			}
+		})
+	}
+}
+
+func TestRedisCache_RecoversAfterOutage(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:", cache.WithRedisTimeout(100*time.Millisecond))
+
+	// Leave a pooled connection behind, then break it
+	c.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Minute)
+	server.SetDown(true)
+
+	if c.Set("spotify:playlist:Stout", playlistValue{}, time.Minute) {
+		t.Fatalf("expected set to fail while the server is down")
+	}
+
+	server.SetDown(false)
+
+	if _, found := c.Get("spotify:playlist:IPA"); !found {
+		t.Fatalf("expected the cache to work again once the server is back")
+	}
+}
+
+func TestRedisClient_Auth(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "valid password",
+			password: "s3cret",
+		},
+		{
+			name:     "wrong password",
+			password: "nope",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := redistest.NewServer()
+			defer server.Close()
+			server.RequirePassword("s3cret")
+
+			client := cache.NewRedisClient(
+				server.Addr(),
+				cache.WithRedisPassword(tt.password),
+				cache.WithRedisDB(2),
+			)
+			defer client.Close()
+
+			err := client.Ping(context.Background())
+
+			if tt.wantErr {
+				var redisErr cache.RedisError
+				if !errors.As(err, &redisErr) {
+					t.Fatalf("expected a RedisError, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if server.Commands("SELECT") != 1 {
+				t.Errorf("expected the database to be selected")
+			}
+		})
+	}
+}
+
+func TestRedisCache_Entries(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	c := newRedisCache(t, server, "karhub:")
+	other := newRedisCache(t, server, "other:")
+
+	c.Set("spotify:playlist:Stout", playlistValue{Name: "Dark"}, time.Hour)
+	c.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Minute)
+	c.Set("spotify:playlist-uri:abc", playlistValue{Name: "abc"}, 0)
+	other.Set("spotify:playlist:IPA", playlistValue{Name: "Not ours"}, 0)
+
+	entries := c.Entries("spotify:playlist:", 0)
+	if len(entries) != 2 || entries[0].Key != "spotify:playlist:IPA" || entries[1].Key != "spotify:playlist:Stout" {
+		t.Fatalf("expected the two namespaced style playlists, got %+v", entries)
+	}
+
+	if ttl := entries[0].TTL; ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected a TTL up to a minute, got %s", ttl)
+	}
+
+	all := c.Entries("", 1)
+	if len(all) != 1 || all[0].Key != "spotify:playlist-uri:abc" || all[0].TTL != 0 {
+		t.Errorf("expected the first key without expiry, got %+v", all)
+	}
+}
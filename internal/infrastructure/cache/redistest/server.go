@@ -0,0 +1,525 @@
+// Package redistest provides an in-process fake Redis server speaking
+// RESP2 over TCP, so the Redis cache adapter can be tested offline.
+//
+// It implements the handful of commands the adapter uses: PING, AUTH,
+// SELECT, GET, SET (with EX/PX), DEL, PTTL, SCAN (with MATCH/COUNT),
+// DBSIZE and FLUSHALL.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+
+	// seq orders keys for SCAN, so deleting keys mid-scan does not
+	// make the cursor skip others (as in real Redis)
+	seq int
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Server is a fake Redis server.
+type Server struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string]entry
+	nextSeq  int
+	commands map[string]int
+	down     bool
+	conns    map[net.Conn]struct{}
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a fake Redis server on a random local port.
+// Callers must call Close when done.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redistest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		listener: listener,
+		data:     make(map[string]entry),
+		commands: make(map[string]int),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+// Addr returns the "host:port" the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// RequirePassword makes the server reject commands until AUTH succeeds.
+func (s *Server) RequirePassword(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.password = password
+}
+
+// SetDown simulates an outage: while down, every connection is dropped.
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+	if down {
+		for conn := range s.conns {
+			conn.Close()
+		}
+	}
+}
+
+// Commands returns how many times a command (e.g. "GET") was received.
+func (s *Server) Commands(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.commands[strings.ToUpper(name)]
+}
+
+// Put stores a raw value, e.g. to simulate a corrupt or foreign entry.
+func (s *Server) Put(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = s.newEntryLocked(key, value)
+}
+
+// Keys returns the live keys, sorted.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.liveKeysLocked(time.Now())
+}
+
+// Close stops the server and drops every connection.
+func (s *Server) Close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+/*
+	---------- Connection handling ----------
+*/
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.down {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	s.mu.Lock()
+	authenticated := s.password == ""
+	s.mu.Unlock()
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				writeError(writer, "ERR Protocol error: "+err.Error())
+				_ = writer.Flush()
+			}
+			return
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+
+		switch {
+		case name == "AUTH":
+			authenticated = s.auth(writer, args)
+		case !authenticated:
+			writeError(writer, "NOAUTH Authentication required.")
+		default:
+			s.exec(writer, name, args[1:])
+		}
+
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) auth(w *bufio.Writer, args []string) bool {
+	s.mu.Lock()
+	s.commands["AUTH"]++
+	password := s.password
+	s.mu.Unlock()
+
+	if len(args) != 2 || args[1] != password {
+		writeError(w, "WRONGPASS invalid username-password pair or user is disabled.")
+		return false
+	}
+
+	writeSimple(w, "OK")
+	return true
+}
+
+/*
+	---------- Commands ----------
+*/
+
+func (s *Server) exec(w *bufio.Writer, name string, args []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands[name]++
+	now := time.Now()
+
+	switch name {
+	case "PING":
+		writeSimple(w, "PONG")
+
+	case "SELECT":
+		writeSimple(w, "OK")
+
+	case "GET":
+		if len(args) != 1 {
+			writeArity(w, name)
+			return
+		}
+		e, found := s.data[args[0]]
+		if !found || e.expired(now) {
+			delete(s.data, args[0])
+			writeNull(w)
+			return
+		}
+		writeBulk(w, e.value)
+
+	case "SET":
+		s.set(w, args, now)
+
+	case "DEL":
+		if len(args) == 0 {
+			writeArity(w, name)
+			return
+		}
+		deleted := 0
+		for _, key := range args {
+			if e, found := s.data[key]; found {
+				if !e.expired(now) {
+					deleted++
+				}
+				delete(s.data, key)
+			}
+		}
+		writeInt(w, int64(deleted))
+
+	case "PTTL":
+		if len(args) != 1 {
+			writeArity(w, name)
+			return
+		}
+		e, found := s.data[args[0]]
+		switch {
+		case !found || e.expired(now):
+			writeInt(w, -2)
+		case e.expiresAt.IsZero():
+			writeInt(w, -1)
+		default:
+			writeInt(w, e.expiresAt.Sub(now).Milliseconds())
+		}
+
+	case "SCAN":
+		s.scan(w, args, now)
+
+	case "DBSIZE":
+		writeInt(w, int64(len(s.liveKeysLocked(now))))
+
+	case "FLUSHALL", "FLUSHDB":
+		s.data = make(map[string]entry)
+		writeSimple(w, "OK")
+
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(name)))
+	}
+}
+
+func (s *Server) set(w *bufio.Writer, args []string, now time.Time) {
+	if len(args) < 2 {
+		writeArity(w, "SET")
+		return
+	}
+
+	e := s.newEntryLocked(args[0], args[1])
+
+	for i := 2; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		if (option != "EX" && option != "PX") || i+1 >= len(args) {
+			writeError(w, "ERR syntax error")
+			return
+		}
+
+		n, err := strconv.ParseInt(args[i+1], 10, 64)
+		if err != nil || n <= 0 {
+			writeError(w, "ERR invalid expire time in 'set' command")
+			return
+		}
+
+		unit := time.Millisecond
+		if option == "EX" {
+			unit = time.Second
+		}
+		e.expiresAt = now.Add(time.Duration(n) * unit)
+		i++
+	}
+
+	s.data[args[0]] = e
+	writeSimple(w, "OK")
+}
+
+// scan walks keys in creation order; the cursor is the sequence number
+// to resume from.
+func (s *Server) scan(w *bufio.Writer, args []string, now time.Time) {
+	if len(args) < 1 {
+		writeArity(w, "SCAN")
+		return
+	}
+
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil || cursor < 0 {
+		writeError(w, "ERR invalid cursor")
+		return
+	}
+
+	pattern, count := "*", 10
+	for i := 1; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if n, err := strconv.Atoi(args[i+1]); err == nil && n > 0 {
+				count = n
+			}
+		}
+	}
+
+	type seqKey struct {
+		key string
+		seq int
+	}
+
+	pending := make([]seqKey, 0, len(s.data))
+	for key, e := range s.data {
+		if e.seq >= cursor && !e.expired(now) {
+			pending = append(pending, seqKey{key, e.seq})
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].seq < pending[j].seq })
+
+	next := 0
+	if len(pending) > count {
+		next = pending[count].seq
+		pending = pending[:count]
+	}
+
+	matched := make([]string, 0, len(pending))
+	for _, k := range pending {
+		if globMatch(pattern, k.key) {
+			matched = append(matched, k.key)
+		}
+	}
+
+	fmt.Fprintf(w, "*2\r\n")
+	writeBulk(w, strconv.Itoa(next))
+	fmt.Fprintf(w, "*%d\r\n", len(matched))
+	for _, key := range matched {
+		writeBulk(w, key)
+	}
+}
+
+// newEntryLocked creates an entry for key, keeping its SCAN position
+// when the key already exists.
+func (s *Server) newEntryLocked(key, value string) entry {
+	if e, found := s.data[key]; found {
+		return entry{value: value, seq: e.seq}
+	}
+
+	s.nextSeq++
+	return entry{value: value, seq: s.nextSeq}
+}
+
+func (s *Server) liveKeysLocked(now time.Time) []string {
+	keys := make([]string, 0, len(s.data))
+	for key, e := range s.data {
+		if !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// globMatch implements the subset of Redis glob patterns used by SCAN
+// MATCH: '*', '?' and backslash escapes.
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if globMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+/*
+	---------- RESP ----------
+*/
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "*") {
+		// Inline command (e.g. typed in telnet)
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid multibulk length")
+	}
+
+	args := make([]string, 0, n)
+	for range n {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, fmt.Errorf("expected '$', got '%s'", header)
+		}
+
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid bulk length")
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeSimple(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "+%s\r\n", s)
+}
+
+func writeError(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "-%s\r\n", s)
+}
+
+func writeInt(w *bufio.Writer, n int64) {
+	fmt.Fprintf(w, ":%d\r\n", n)
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+func writeNull(w *bufio.Writer) {
+	fmt.Fprint(w, "$-1\r\n")
+}
+
+func writeArity(w *bufio.Writer, name string) {
+	writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
@@ -0,0 +1,298 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Default Redis client settings.
+const (
+	DefaultRedisPoolSize = 8
+	DefaultRedisTimeout  = 500 * time.Millisecond
+)
+
+// ErrRedisNil is returned by RedisClient.Do when the reply is a null bulk string.
+var ErrRedisNil = errors.New("redis: nil reply")
+
+// RedisError is an error reply sent by the server (e.g. "ERR unknown command").
+type RedisError string
+
+func (e RedisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// RedisClient is a minimal Redis client speaking RESP2 over TCP, with a
+// small pool of reusable connections.
+type RedisClient struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+
+	pool chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// redisConfig holds the optional RedisClient settings.
+type redisConfig struct {
+	password string
+	db       int
+	poolSize int
+	timeout  time.Duration
+}
+
+// RedisOption customizes a RedisClient.
+type RedisOption func(*redisConfig)
+
+// WithRedisPassword authenticates every new connection with AUTH.
+func WithRedisPassword(password string) RedisOption {
+	return func(c *redisConfig) {
+		c.password = password
+	}
+}
+
+// WithRedisDB selects a logical database on every new connection.
+func WithRedisDB(db int) RedisOption {
+	return func(c *redisConfig) {
+		c.db = db
+	}
+}
+
+// WithRedisPoolSize bounds the number of idle connections kept around.
+func WithRedisPoolSize(size int) RedisOption {
+	return func(c *redisConfig) {
+		c.poolSize = size
+	}
+}
+
+// WithRedisTimeout bounds dialing and each command round trip.
+func WithRedisTimeout(timeout time.Duration) RedisOption {
+	return func(c *redisConfig) {
+		c.timeout = timeout
+	}
+}
+
+// NewRedisClient creates a client for the server at addr ("host:port").
+// Connections are opened lazily.
+func NewRedisClient(addr string, opts ...RedisOption) *RedisClient {
+	cfg := redisConfig{
+		poolSize: DefaultRedisPoolSize,
+		timeout:  DefaultRedisTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &RedisClient{
+		addr:     addr,
+		password: cfg.password,
+		db:       cfg.db,
+		timeout:  cfg.timeout,
+		pool:     make(chan *redisConn, max(cfg.poolSize, 1)),
+	}
+}
+
+// Do sends a command and returns its reply: string (simple strings),
+// int64, []byte (bulk strings) or []any (arrays). Null bulk strings
+// return ErrRedisNil and error replies a RedisError.
+func (c *RedisClient) Do(ctx context.Context, args ...string) (any, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.conn.SetDeadline(deadline)
+
+	reply, err := conn.roundTrip(args)
+
+	// Error replies leave the connection in a clean state; anything
+	// else (I/O, protocol) may not
+	var redisErr RedisError
+	if err == nil || errors.Is(err, ErrRedisNil) || errors.As(err, &redisErr) {
+		c.put(conn)
+	} else {
+		conn.conn.Close()
+	}
+
+	return reply, err
+}
+
+// Ping checks the server is reachable.
+func (c *RedisClient) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections.
+func (c *RedisClient) Close() error {
+	for {
+		select {
+		case conn := <-c.pool:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *RedisClient) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.pool:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: dial %s: %w", c.addr, err)
+	}
+
+	conn := &redisConn{
+		conn:   netConn,
+		reader: bufio.NewReader(netConn),
+		writer: bufio.NewWriter(netConn),
+	}
+
+	_ = netConn.SetDeadline(time.Now().Add(c.timeout))
+
+	if c.password != "" {
+		if _, err := conn.roundTrip([]string{"AUTH", c.password}); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	if c.db != 0 {
+		if _, err := conn.roundTrip([]string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *RedisClient) put(conn *redisConn) {
+	select {
+	case c.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+/*
+	---------- RESP ----------
+*/
+
+func (c *redisConn) roundTrip(args []string) (any, error) {
+	if err := writeRESPCommand(c.writer, args); err != nil {
+		return nil, err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readRESP(c.reader)
+}
+
+// writeRESPCommand writes args as a RESP array of bulk strings.
+func writeRESPCommand(w *bufio.Writer, args []string) error {
+	if _, err := fmt.Fprintf(w, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readRESP reads one RESP value. See RedisClient.Do for the types returned.
+func readRESP(r *bufio.Reader) (any, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	payload := line[1:]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, RedisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", payload)
+		}
+		if size < 0 {
+			return nil, ErrRedisNil
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", payload)
+		}
+		if size < 0 {
+			return nil, ErrRedisNil
+		}
+
+		items := make([]any, 0, size)
+		for range size {
+			item, err := readRESP(r)
+			if err != nil && !errors.Is(err, ErrRedisNil) {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %q", line[0])
+	}
+}
+
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line %q", line)
+	}
+
+	return line[:len(line)-2], nil
+}
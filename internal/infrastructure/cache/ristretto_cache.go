@@ -1,18 +1,47 @@
 package cache
 
 import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/ristretto/v2"
 )
 
 // RistrettoCache is a generic in-memory cache wrapper around ristretto v2.
-type RistrettoCache[K ristretto.Key, V any] struct {
+//
+// Ristretto does NOT support iteration, so inserted keys are tracked
+// to support prefix deletes. The index is a sync.Map, so writers never
+// contend on a lock; keys evicted by ristretto are pruned from it lazily,
+// in the background once it has doubled in size, or when stats are read.
+type RistrettoCache[K Key, V any] struct {
 	cache *ristretto.Cache[K, V]
+
+	// keys maps each indexed key to the generation of its last Set, so a
+	// prune never drops a key written after it started
+	keys       sync.Map
+	size       atomic.Int64
+	generation atomic.Uint64
+	pruneSize  atomic.Int64
+	pruning    atomic.Bool
+
+	// deleted counts explicit deletes, which ristretto reports as evictions
+	deleted atomic.Uint64
+}
+
+// Key is the set of key types RistrettoCache supports.
+type Key interface {
+	ristretto.Key
+	comparable
 }
 
+// minPruneSize is the key index size below which it is never pruned.
+const minPruneSize = 1024
+
 // NewRistrettoCache creates a new Ristretto cache instance.
-func NewRistrettoCache[K ristretto.Key, V any](
+func NewRistrettoCache[K Key, V any](
 	numCounters int64,
 	maxCost int64,
 ) (*RistrettoCache[K, V], error) {
@@ -21,22 +50,177 @@ func NewRistrettoCache[K ristretto.Key, V any](
 		NumCounters: numCounters,
 		MaxCost:     maxCost,
 		BufferItems: 64,
+		Metrics:     true,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &RistrettoCache[K, V]{cache: c}, nil
+	r := &RistrettoCache[K, V]{cache: c}
+	r.pruneSize.Store(minPruneSize)
+
+	return r, nil
 }
 
 func (r *RistrettoCache[K, V]) Get(key K) (V, bool) {
 	return r.cache.Get(key)
 }
 
-func (r *RistrettoCache[K, V]) SetWithTTL(
+func (r *RistrettoCache[K, V]) Set(
 	key K,
 	value V,
 	ttl time.Duration,
 ) bool {
-	return r.cache.SetWithTTL(key, value, 1, ttl)
+	if !r.cache.SetWithTTL(key, value, 1, ttl) {
+		return false
+	}
+
+	generation := r.generation.Add(1)
+	if _, loaded := r.keys.Swap(key, generation); !loaded {
+		if r.size.Add(1) > r.pruneSize.Load() {
+			go r.prune()
+		}
+	}
+	return true
+}
+
+// Delete removes key from the cache.
+func (r *RistrettoCache[K, V]) Delete(key K) {
+	// Apply a buffered Set first, so the delete is counted
+	r.cache.Wait()
+
+	r.del(key)
+}
+
+// DeleteByPrefix removes every string key starting with prefix.
+func (r *RistrettoCache[K, V]) DeleteByPrefix(prefix string) int {
+	r.cache.Wait()
+
+	deleted := 0
+	r.keys.Range(func(k, _ any) bool {
+		if s, ok := k.(string); ok && strings.HasPrefix(s, prefix) && r.del(k.(K)) {
+			deleted++
+		}
+		return true
+	})
+
+	return deleted
+}
+
+// keysWithPrefix returns the live string keys starting with prefix.
+func (r *RistrettoCache[K, V]) keysWithPrefix(prefix string) []string {
+	r.cache.Wait()
+
+	keys := make([]string, 0)
+	r.keys.Range(func(k, _ any) bool {
+		s, ok := k.(string)
+		if !ok || !strings.HasPrefix(s, prefix) {
+			return true
+		}
+
+		if _, found := r.cache.GetTTL(k.(K)); found {
+			keys = append(keys, s)
+		}
+		return true
+	})
+	return keys
+}
+
+// Entries returns the live string keys starting with prefix and their TTLs.
+func (r *RistrettoCache[K, V]) Entries(prefix string, limit int) []Entry {
+	keys := limitKeys(r.keysWithPrefix(prefix), limit)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		k, _ := any(key).(K)
+
+		ttl, found := r.cache.GetTTL(k)
+		if !found {
+			continue // expired since listed
+		}
+		entries = append(entries, Entry{Key: key, TTL: ttl})
+	}
+	return entries
+}
+
+// Stats returns the cache counters. Cost and MaxCost are in ristretto's
+// unit: each entry costs 1 plus its internal overhead in bytes.
+func (r *RistrettoCache[K, V]) Stats() Stats {
+	r.prune()
+	keys := int(r.size.Load())
+
+	metrics := r.cache.Metrics
+
+	// Ristretto counts explicit deletes as evictions too
+	removed := metrics.KeysEvicted()
+	evictions := removed - min(removed, r.deleted.Load())
+
+	return Stats{
+		Hits:      metrics.Hits(),
+		Misses:    metrics.Misses(),
+		Keys:      keys,
+		Evictions: evictions,
+		Cost:      int64(metrics.CostAdded() - min(metrics.CostAdded(), metrics.CostEvicted())),
+		MaxCost:   r.cache.MaxCost(),
+	}
+}
+
+// Wait blocks until all buffered writes have been applied.
+func (r *RistrettoCache[K, V]) Wait() {
+	r.cache.Wait()
+}
+
+// Close stops ristretto's goroutines. The cache must not be used after.
+func (r *RistrettoCache[K, V]) Close() error {
+	r.cache.Close()
+	return nil
+}
+
+// CheckHealth always passes: an in-process cache has nothing to reach.
+func (r *RistrettoCache[K, V]) CheckHealth(context.Context) error {
+	return nil
+}
+
+// del deletes key from ristretto and the index, counting it if it was
+// stored. It reports whether the key was stored.
+func (r *RistrettoCache[K, V]) del(key K) bool {
+	generation, indexed := r.keys.Load(key)
+
+	_, found := r.cache.GetTTL(key)
+	if found {
+		r.deleted.Add(1)
+	}
+	r.cache.Del(key)
+
+	// A Set racing this delete keeps its newer index entry
+	if indexed && r.keys.CompareAndDelete(key, generation) {
+		r.size.Add(-1)
+	}
+	return found
+}
+
+// prune drops keys that ristretto evicted, rejected or expired from the
+// index. Only one prune runs at a time; others return right away.
+func (r *RistrettoCache[K, V]) prune() {
+	if !r.pruning.CompareAndSwap(false, true) {
+		return
+	}
+	defer r.pruning.Store(false)
+
+	// Keys set after this point may still be buffered: leave them alone
+	upTo := r.generation.Load()
+	r.cache.Wait()
+
+	r.keys.Range(func(k, generation any) bool {
+		if generation.(uint64) > upTo {
+			return true
+		}
+
+		if _, found := r.cache.GetTTL(k.(K)); !found && r.keys.CompareAndDelete(k, generation) {
+			r.size.Add(-1)
+		}
+		return true
+	})
+
+	r.pruneSize.Store(max(2*r.size.Load(), minPruneSize))
 }
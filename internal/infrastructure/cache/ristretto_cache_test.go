@@ -0,0 +1,248 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+)
+
+func newRistretto(t *testing.T) *cache.RistrettoCache[string, string] {
+	t.Helper()
+
+	c, err := cache.NewRistrettoCache[string, string](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	return c
+}
+
+/*
+	TESTS
+*/
+
+func TestRistrettoCache_SetGetDelete(t *testing.T) {
+	c := newRistretto(t)
+
+	c.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	c.Wait()
+
+	if got, found := c.Get("spotify:playlist:IPA"); !found || got != "IPA Party" {
+		t.Fatalf("expected cached value, got %q (found=%v)", got, found)
+	}
+
+	c.Delete("spotify:playlist:IPA")
+
+	if _, found := c.Get("spotify:playlist:IPA"); found {
+		t.Fatalf("expected key to be deleted")
+	}
+}
+
+func TestRistrettoCache_Expiry(t *testing.T) {
+	c := newRistretto(t)
+
+	c.Set("spotify:playlist:IPA", "IPA Party", 20*time.Millisecond)
+	c.Wait()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if _, found := c.Get("spotify:playlist:IPA"); found {
+		t.Fatalf("expected key to expire")
+	}
+
+	if keys := c.Stats().Keys; keys != 0 {
+		t.Errorf("expected expired key to be pruned, got %d keys", keys)
+	}
+}
+
+func TestRistrettoCache_DeleteByPrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		wantDeleted int
+		wantLeft    []string
+	}{
+		{
+			name:        "style playlists",
+			prefix:      "spotify:playlist:",
+			wantDeleted: 2,
+			wantLeft:    []string{"spotify:playlist-uri:abc"},
+		},
+		{
+			name:        "single style",
+			prefix:      "spotify:playlist:IPA",
+			wantDeleted: 1,
+			wantLeft:    []string{"spotify:playlist:Stout", "spotify:playlist-uri:abc"},
+		},
+		{
+			name:        "no match",
+			prefix:      "local:",
+			wantDeleted: 0,
+			wantLeft:    []string{"spotify:playlist:IPA", "spotify:playlist:Stout", "spotify:playlist-uri:abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newRistretto(t)
+
+			for _, key := range []string{"spotify:playlist:IPA", "spotify:playlist:Stout", "spotify:playlist-uri:abc"} {
+				c.Set(key, key, time.Minute)
+			}
+
+			if got := c.DeleteByPrefix(tt.prefix); got != tt.wantDeleted {
+				t.Errorf("expected %d deleted, got %d", tt.wantDeleted, got)
+			}
+
+			for _, key := range tt.wantLeft {
+				if _, found := c.Get(key); !found {
+					t.Errorf("expected %q to be kept", key)
+				}
+			}
+
+			if got := c.Stats().Keys; got != len(tt.wantLeft) {
+				t.Errorf("expected %d keys left, got %d", len(tt.wantLeft), got)
+			}
+		})
+	}
+}
+
+func TestRistrettoCache_ConcurrentSets(t *testing.T) {
+	c := newRistretto(t)
+
+	// Enough keys to trigger background prunes while writers are running
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				c.Set(fmt.Sprintf("spotify:playlist:%d-%d", w, i), "playlist", time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Ristretto may drop a few sets under contention
+	stored := c.Stats().Keys
+	if stored == 0 {
+		t.Fatalf("expected keys to be stored")
+	}
+
+	if got := c.DeleteByPrefix("spotify:playlist:"); got != stored {
+		t.Errorf("expected %d deleted, got %d", stored, got)
+	}
+
+	if got := c.Stats().Keys; got != 0 {
+		t.Errorf("expected no keys left, got %d", got)
+	}
+}
+
+func TestRistrettoCache_Stats(t *testing.T) {
+	c := newRistretto(t)
+
+	c.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	c.Wait()
+
+	c.Get("spotify:playlist:IPA")
+	c.Get("spotify:playlist:IPA")
+	c.Get("spotify:playlist:Stout")
+
+	stats := c.Stats()
+
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("expected 2 hits and 1 miss, got %+v", stats)
+	}
+
+	if stats.Keys != 1 {
+		t.Errorf("expected 1 key, got %d", stats.Keys)
+	}
+
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Errorf("expected hit ratio 2/3, got %f", ratio)
+	}
+}
+
+func TestRistrettoCache_StatsEvictionsAndCost(t *testing.T) {
+	c := newRistretto(t)
+
+	c.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	c.Wait()
+
+	stats := c.Stats()
+	if stats.Cost <= 0 || stats.MaxCost != 1<<20 {
+		t.Errorf("expected a positive cost out of %d, got %d of %d", 1<<20, stats.Cost, stats.MaxCost)
+	}
+
+	// An explicit delete frees its cost but is not an eviction
+	c.Delete("spotify:playlist:IPA")
+
+	stats = c.Stats()
+	if stats.Cost != 0 {
+		t.Errorf("expected no cost left, got %d", stats.Cost)
+	}
+	if stats.Evictions != 0 {
+		t.Errorf("expected no evictions, got %d", stats.Evictions)
+	}
+}
+
+func TestRistrettoCache_Entries(t *testing.T) {
+	c := newRistretto(t)
+
+	c.Set("spotify:playlist:Stout", "Dark", time.Hour)
+	c.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	c.Set("spotify:playlist-uri:abc", "abc", 0)
+
+	tests := []struct {
+		name     string
+		prefix   string
+		limit    int
+		wantKeys []string
+	}{
+		{
+			name:     "all keys, sorted",
+			wantKeys: []string{"spotify:playlist-uri:abc", "spotify:playlist:IPA", "spotify:playlist:Stout"},
+		},
+		{
+			name:     "by prefix",
+			prefix:   "spotify:playlist:",
+			wantKeys: []string{"spotify:playlist:IPA", "spotify:playlist:Stout"},
+		},
+		{
+			name:     "limited",
+			prefix:   "spotify:playlist:",
+			limit:    1,
+			wantKeys: []string{"spotify:playlist:IPA"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := c.Entries(tt.prefix, tt.limit)
+
+			if len(entries) != len(tt.wantKeys) {
+				t.Fatalf("expected %d entries, got %+v", len(tt.wantKeys), entries)
+			}
+			for i, key := range tt.wantKeys {
+				if entries[i].Key != key {
+					t.Errorf("expected entry %d to be %q, got %q", i, key, entries[i].Key)
+				}
+			}
+		})
+	}
+
+	for _, e := range c.Entries("", 0) {
+		switch e.Key {
+		case "spotify:playlist-uri:abc":
+			if e.TTL != 0 {
+				t.Errorf("expected no expiry for %q, got %s", e.Key, e.TTL)
+			}
+		case "spotify:playlist:IPA":
+			if e.TTL <= 0 || e.TTL > time.Minute {
+				t.Errorf("expected a TTL up to a minute for %q, got %s", e.Key, e.TTL)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Group coalesces concurrent calls for the same key into a single
+// execution whose result (or error) is shared by every caller.
+//
+// The shared call runs detached from the callers' cancellation: a caller
+// whose context is done stops waiting and gets its context error, while
+// the call keeps running for the others.
+type Group[V any] struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call[V]
+}
+
+// call is an in-flight or completed Group call.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	dups  int
+}
+
+// NewGroup creates a Group. timeout bounds each shared call, since no
+// single caller's context does; zero means no bound.
+func NewGroup[V any](timeout time.Duration) *Group[V] {
+	return &Group[V]{
+		timeout: timeout,
+		calls:   make(map[string]*call[V]),
+	}
+}
+
+// Do executes fn once for all concurrent callers of key. shared reports
+// whether the result was delivered to more than one caller.
+func (g *Group[V]) Do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) (V, error),
+) (value V, err error, shared bool) {
+	g.mu.Lock()
+	c, inFlight := g.calls[key]
+	if inFlight {
+		c.dups++
+	} else {
+		c = &call[V]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(ctx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = c.dups > 0
+		g.mu.Unlock()
+		return c.value, c.err, shared
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err(), inFlight
+	}
+}
+
+// InFlight returns the number of keys currently being fetched.
+func (g *Group[V]) InFlight() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.calls)
+}
+
+func (g *Group[V]) run(
+	ctx context.Context,
+	key string,
+	c *call[V],
+	fn func(ctx context.Context) (V, error),
+) {
+	// Keep request values but not the caller's cancellation
+	callCtx := context.WithoutCancel(ctx)
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, g.timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		// A panic must not leave the other callers waiting forever
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("shared call for %q panicked: %v", key, r)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.value, c.err = fn(callCtx)
+}
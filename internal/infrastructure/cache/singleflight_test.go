@@ -0,0 +1,177 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+)
+
+func TestGroup_Do_CoalescesConcurrentCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		callers int
+	}{
+		{
+			name:    "shared value",
+			callers: 50,
+		},
+		{
+			name:    "shared error",
+			err:     errors.New("upstream down"),
+			callers: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := cache.NewGroup[string](time.Second)
+
+			var calls atomic.Int32
+			release := make(chan struct{})
+
+			fn := func(context.Context) (string, error) {
+				calls.Add(1)
+				<-release
+				return "playlist", tt.err
+			}
+
+			var wg sync.WaitGroup
+			results := make(chan error, tt.callers)
+			values := make(chan string, tt.callers)
+
+			for i := 0; i < tt.callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					v, err, _ := group.Do(context.Background(), "IPA", fn)
+					values <- v
+					results <- err
+				}()
+			}
+
+			// Wait until every caller is parked on the same call
+			waitFor(t, func() bool { return group.InFlight() == 1 && calls.Load() == 1 })
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+			wg.Wait()
+			close(results)
+			close(values)
+
+			if got := calls.Load(); got != 1 {
+				t.Fatalf("expected 1 execution, got %d", got)
+			}
+
+			for err := range results {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("expected error %v, got %v", tt.err, err)
+				}
+			}
+
+			if tt.err == nil {
+				for v := range values {
+					if v != "playlist" {
+						t.Errorf("expected shared value, got %q", v)
+					}
+				}
+			}
+
+			if group.InFlight() != 0 {
+				t.Errorf("expected no call in flight after completion")
+			}
+		})
+	}
+}
+
+func TestGroup_Do_CallerCancellationDoesNotCancelSharedCall(t *testing.T) {
+	group := cache.NewGroup[string](time.Second)
+
+	release := make(chan struct{})
+	var fnCtxErr atomic.Value
+
+	fn := func(ctx context.Context) (string, error) {
+		<-release
+		if err := ctx.Err(); err != nil {
+			fnCtxErr.Store(err)
+		}
+		return "playlist", nil
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err, _ := group.Do(leaderCtx, "IPA", fn)
+		leaderErr <- err
+	}()
+	waitFor(t, func() bool { return group.InFlight() == 1 })
+
+	followerValue := make(chan string, 1)
+	go func() {
+		v, _, _ := group.Do(context.Background(), "IPA", fn)
+		followerValue <- v
+	}()
+
+	cancelLeader()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled caller to get context.Canceled, got %v", err)
+	}
+
+	close(release)
+
+	select {
+	case v := <-followerValue:
+		if v != "playlist" {
+			t.Errorf("expected follower to get the shared value, got %q", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("follower never received the shared result")
+	}
+
+	if err := fnCtxErr.Load(); err != nil {
+		t.Errorf("shared call context was cancelled: %v", err)
+	}
+}
+
+func TestGroup_Do_Timeout(t *testing.T) {
+	group := cache.NewGroup[string](20 * time.Millisecond)
+
+	_, err, _ := group.Do(context.Background(), "IPA", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGroup_Do_Panic(t *testing.T) {
+	group := cache.NewGroup[string](time.Second)
+
+	_, err, _ := group.Do(context.Background(), "IPA", func(context.Context) (string, error) {
+		panic("boom")
+	})
+
+	if err == nil {
+		t.Fatalf("expected error from panicking call")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,359 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWriteBehindQueue is the number of pending disk writes a
+// TieredCache buffers before dropping new ones.
+const DefaultWriteBehindQueue = 1024
+
+// TieredCache is a memory cache backed by a DiskStore, so entries
+// survive restarts.
+//
+// Reads are served from memory first; disk hits are promoted back into
+// memory for their remaining TTL. Writes go to memory synchronously and
+// to disk asynchronously (write-behind) through a bounded queue: when
+// the queue is full the disk write is dropped, which only costs a cold
+// entry after the next restart. Deletes share the queue, so every key's
+// disk operations land in the order they were made.
+type TieredCache[V any] struct {
+	memory *RistrettoCache[string, V]
+	disk   *DiskStore
+	codec  Codec[V]
+
+	mu     sync.RWMutex
+	writes chan diskWrite
+	done   chan struct{}
+	closed bool
+
+	// deleting counts the queued deletes per key, so a key deleted in
+	// memory is not promoted back from disk before its delete lands.
+	// deletes counts every delete ever started: a disk read that saw it
+	// change may have read a deleted entry, and is not promoted.
+	deletingMu sync.Mutex
+	deleting   map[string]int
+	deletes    uint64
+
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// diskWrite is a pending write-behind operation.
+type diskWrite struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	delete    bool
+}
+
+// NewTieredCache layers memory over disk. Values are serialized with
+// codec; queueSize bounds the pending write-behind operations.
+func NewTieredCache[V any](
+	memory *RistrettoCache[string, V],
+	disk *DiskStore,
+	codec Codec[V],
+	queueSize int,
+) *TieredCache[V] {
+	if queueSize <= 0 {
+		queueSize = DefaultWriteBehindQueue
+	}
+
+	t := &TieredCache[V]{
+		memory:   memory,
+		disk:     disk,
+		codec:    codec,
+		writes:   make(chan diskWrite, queueSize),
+		done:     make(chan struct{}),
+		deleting: make(map[string]int),
+	}
+
+	go t.writeBehind()
+
+	return t
+}
+
+func (t *TieredCache[V]) Get(key string) (V, bool) {
+	if value, found := t.memory.Get(key); found {
+		t.hits.Add(1)
+		return value, true
+	}
+
+	var zero V
+
+	epoch, deleting := t.deleteEpoch(key)
+	if deleting {
+		t.misses.Add(1)
+		return zero, false
+	}
+
+	data, expiresAt, found := t.disk.Get(key)
+	if !found {
+		t.misses.Add(1)
+		return zero, false
+	}
+
+	value, err := t.codec.Unmarshal(data)
+	if err != nil {
+		// Unreadable on disk: forget it rather than failing every read
+		t.failed.Add(1)
+		t.misses.Add(1)
+		t.markDeleting(key)
+		t.enqueueDelete(key)
+		return zero, false
+	}
+
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt)
+		if ttl <= 0 {
+			t.misses.Add(1)
+			return zero, false
+		}
+	}
+
+	t.promote(key, value, ttl, epoch)
+	t.hits.Add(1)
+	return value, true
+}
+
+// promote copies a disk hit into memory, unless a delete started since
+// epoch: the entry read may have been deleted since, and promoting it
+// would bring it back until its TTL. The check and the memory write are
+// made under the lock deletes are marked with, and deletes are marked
+// before they clear memory, so either the delete clears the promoted
+// entry or the promotion sees the delete.
+func (t *TieredCache[V]) promote(key string, value V, ttl time.Duration, epoch uint64) {
+	t.deletingMu.Lock()
+	defer t.deletingMu.Unlock()
+
+	if t.deletes == epoch {
+		t.memory.Set(key, value, ttl)
+	}
+}
+
+func (t *TieredCache[V]) Set(key string, value V, ttl time.Duration) bool {
+	if !t.memory.Set(key, value, ttl) {
+		return false
+	}
+
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		t.failed.Add(1)
+		return true
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	t.enqueue(diskWrite{key: key, value: data, expiresAt: expiresAt})
+	return true
+}
+
+func (t *TieredCache[V]) Delete(key string) {
+	t.markDeleting(key)
+	t.memory.Delete(key)
+	t.enqueueDelete(key)
+}
+
+// DeleteByPrefix removes the matching keys from both tiers and returns
+// how many distinct keys were removed. Keys only in memory get a disk
+// delete too, as their write may still be queued.
+func (t *TieredCache[V]) DeleteByPrefix(prefix string) int {
+	deleted := make(map[string]struct{})
+
+	for _, key := range t.memory.keysWithPrefix(prefix) {
+		deleted[key] = struct{}{}
+	}
+	for _, key := range t.disk.Keys(prefix) {
+		deleted[key] = struct{}{}
+	}
+
+	for key := range deleted {
+		t.markDeleting(key)
+	}
+	t.memory.DeleteByPrefix(prefix)
+
+	for key := range deleted {
+		t.enqueueDelete(key)
+	}
+
+	return len(deleted)
+}
+
+// Entries lists the keys of both tiers. The TTL comes from memory when
+// the key is there, from its disk expiry otherwise.
+func (t *TieredCache[V]) Entries(prefix string, limit int) []Entry {
+	ttls := make(map[string]time.Duration)
+
+	for _, e := range t.memory.Entries(prefix, 0) {
+		ttls[e.Key] = e.TTL
+	}
+
+	for _, key := range t.disk.Keys(prefix) {
+		if _, found := ttls[key]; found || t.isDeleting(key) {
+			continue
+		}
+
+		expiresAt, found := t.disk.ExpiresAt(key)
+		if !found {
+			continue
+		}
+
+		var ttl time.Duration
+		if !expiresAt.IsZero() {
+			ttl = time.Until(expiresAt)
+		}
+		ttls[key] = ttl
+	}
+
+	keys := make([]string, 0, len(ttls))
+	for key := range ttls {
+		keys = append(keys, key)
+	}
+	keys = limitKeys(keys, limit)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, Entry{Key: key, TTL: ttls[key]})
+	}
+	return entries
+}
+
+// Stats counts a disk hit as a hit; Keys is the larger of the two tiers
+// (the disk normally holds everything memory does, and its count may
+// include expired entries awaiting compaction). Cost is the memory
+// tier's; evictions add up both tiers.
+func (t *TieredCache[V]) Stats() Stats {
+	memory := t.memory.Stats()
+
+	return Stats{
+		Hits:      t.hits.Load(),
+		Misses:    t.misses.Load(),
+		Keys:      max(memory.Keys, t.disk.Len()),
+		Evictions: memory.Evictions + t.disk.Evicted(),
+		Cost:      memory.Cost,
+		MaxCost:   memory.MaxCost,
+		Errors:    t.failed.Load() + t.dropped.Load() + t.disk.Corrupt(),
+	}
+}
+
+// Wait blocks until buffered memory writes have been applied.
+func (t *TieredCache[V]) Wait() {
+	t.memory.Wait()
+}
+
+// Close flushes the pending disk writes and closes both tiers.
+func (t *TieredCache[V]) Close() error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.writes)
+	}
+	t.mu.Unlock()
+
+	<-t.done
+	err := t.disk.Close()
+	_ = t.memory.Close()
+	return err
+}
+
+// CheckHealth checks the disk tier.
+func (t *TieredCache[V]) CheckHealth(ctx context.Context) error {
+	return t.disk.CheckHealth(ctx)
+}
+
+func (t *TieredCache[V]) enqueue(w diskWrite) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		t.dropped.Add(1)
+		return false
+	}
+
+	select {
+	case t.writes <- w:
+		return true
+	default:
+		t.dropped.Add(1)
+		return false
+	}
+}
+
+// markDeleting records that a delete of key started; enqueueDelete must
+// follow.
+func (t *TieredCache[V]) markDeleting(key string) {
+	t.deletingMu.Lock()
+	defer t.deletingMu.Unlock()
+
+	t.deleting[key]++
+	t.deletes++
+}
+
+// enqueueDelete queues the disk delete of a key marked as deleting.
+// Unlike writes, deletes are not dropped when the queue is full: a
+// resurrected entry would be wrong, not just cold. Nor can they bypass
+// the queue, or a write of the same key still queued would land after
+// them, so the caller waits for room.
+func (t *TieredCache[V]) enqueueDelete(key string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		t.dropped.Add(1)
+		t.deleted(key)
+		return
+	}
+
+	t.writes <- diskWrite{key: key, delete: true}
+}
+
+func (t *TieredCache[V]) isDeleting(key string) bool {
+	_, deleting := t.deleteEpoch(key)
+	return deleting
+}
+
+// deleteEpoch returns the number of deletes started so far, and whether
+// one of key is still queued.
+func (t *TieredCache[V]) deleteEpoch(key string) (uint64, bool) {
+	t.deletingMu.Lock()
+	defer t.deletingMu.Unlock()
+
+	return t.deletes, t.deleting[key] > 0
+}
+
+// deleted records that a queued delete of key has been applied.
+func (t *TieredCache[V]) deleted(key string) {
+	t.deletingMu.Lock()
+	defer t.deletingMu.Unlock()
+
+	if t.deleting[key]--; t.deleting[key] <= 0 {
+		delete(t.deleting, key)
+	}
+}
+
+func (t *TieredCache[V]) writeBehind() {
+	defer close(t.done)
+
+	for w := range t.writes {
+		var err error
+		if w.delete {
+			err = t.disk.Delete(w.key)
+			t.deleted(w.key)
+		} else {
+			err = t.disk.Put(w.key, w.value, w.expiresAt)
+		}
+
+		if err != nil {
+			t.failed.Add(1)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package cache_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+)
+
+func newTieredCache(t *testing.T, path string) *cache.TieredCache[playlistValue] {
+	t.Helper()
+
+	memory, err := cache.NewRistrettoCache[string, playlistValue](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	disk := openDiskStore(t, path, 1<<20)
+
+	return cache.NewTieredCache(memory, disk, cache.JSONCodec[playlistValue]{}, 0)
+}
+
+/*
+	TESTS
+*/
+
+func TestTieredCache_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+
+	first := newTieredCache(t, path)
+	first.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party", Tracks: 10}, time.Hour)
+	first.Set("spotify:playlist:Stout", playlistValue{Name: "Dark"}, time.Hour)
+	first.Set("spotify:playlist:Lager", playlistValue{Name: "Short"}, 20*time.Millisecond)
+	first.Delete("spotify:playlist:Stout")
+
+	// Close flushes the write-behind queue
+	if err := first.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	second := newTieredCache(t, path)
+	defer second.Close()
+
+	got, found := second.Get("spotify:playlist:IPA")
+	if !found || got.Name != "IPA Party" || got.Tracks != 10 {
+		t.Fatalf("expected IPA to survive the restart, got %+v (found=%v)", got, found)
+	}
+
+	if _, found := second.Get("spotify:playlist:Stout"); found {
+		t.Errorf("expected deleted entry to stay deleted")
+	}
+
+	if _, found := second.Get("spotify:playlist:Lager"); found {
+		t.Errorf("expected expired entry to be skipped")
+	}
+
+	stats := second.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+func TestTieredCache_DeleteWithFullQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+
+	seed := openDiskStore(t, path, 0)
+	for i := range 5000 {
+		_ = seed.Put(fmt.Sprintf("seed:%d", i), []byte(`{"name":"seed"}`), time.Time{})
+	}
+	_ = seed.Close()
+
+	memory, err := cache.NewRistrettoCache[string, playlistValue](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	// Disk writes wait for the index to load, so with a one-slot queue
+	// the write of IPA is still queued when its delete comes in
+	disk, err := cache.OpenDiskStore(path, 0)
+	if err != nil {
+		t.Fatalf("failed to open disk store: %v", err)
+	}
+	first := cache.NewTieredCache(memory, disk, cache.JSONCodec[playlistValue]{}, 1)
+
+	first.Set("spotify:playlist:Stout", playlistValue{Name: "Dark"}, time.Hour)
+	time.Sleep(time.Millisecond)
+	first.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Hour)
+	first.Delete("spotify:playlist:IPA")
+
+	if err := first.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second := newTieredCache(t, path)
+	defer second.Close()
+
+	if _, found := second.Get("spotify:playlist:IPA"); found {
+		t.Errorf("expected deleted entry to stay deleted")
+	}
+}
+
+func TestTieredCache_ConcurrentGetDoesNotResurrect(t *testing.T) {
+	const keys = 50
+
+	tests := []struct {
+		name   string
+		delete func(c *cache.TieredCache[playlistValue], key string)
+	}{
+		{
+			name:   "delete",
+			delete: func(c *cache.TieredCache[playlistValue], key string) { c.Delete(key) },
+		},
+		{
+			name:   "delete by prefix",
+			delete: func(c *cache.TieredCache[playlistValue], key string) { c.DeleteByPrefix(key) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "playlists.db")
+
+			seed := openDiskStore(t, path, 0)
+			for i := range keys {
+				_ = seed.Put(fmt.Sprintf("style:%d", i), []byte(`{"name":"purged"}`), time.Time{})
+			}
+			_ = seed.Close()
+
+			c := newTieredCache(t, path)
+			defer c.Close()
+
+			for i := range keys {
+				key := fmt.Sprintf("style:%d", i)
+
+				// Only on disk, so every Get reads it there and promotes it
+				stop := make(chan struct{})
+				var wg sync.WaitGroup
+				for range 2 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for {
+							select {
+							case <-stop:
+								return
+							default:
+								c.Get(key)
+							}
+						}
+					}()
+				}
+
+				tt.delete(c, key)
+				close(stop)
+				wg.Wait()
+				c.Wait()
+
+				if got, found := c.Get(key); found {
+					t.Fatalf("%s: expected the purged entry to stay deleted, got %+v", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTieredCache_PromotesDiskHits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+
+	first := newTieredCache(t, path)
+	first.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Hour)
+	_ = first.Close()
+
+	second := newTieredCache(t, path)
+	defer second.Close()
+
+	if _, found := second.Get("spotify:playlist:IPA"); !found {
+		t.Fatalf("expected a disk hit")
+	}
+	second.Wait()
+
+	// The promoted entry is in both tiers now
+	if got := second.DeleteByPrefix("spotify:playlist:"); got != 1 {
+		t.Errorf("expected 1 key deleted across tiers, got %d", got)
+	}
+
+	if _, found := second.Get("spotify:playlist:IPA"); found {
+		t.Errorf("expected prefix delete to clear both tiers")
+	}
+}
+
+func TestTieredCache_Entries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.db")
+
+	first := newTieredCache(t, path)
+	first.Set("spotify:playlist:IPA", playlistValue{Name: "IPA Party"}, time.Hour)
+	first.Set("spotify:playlist:Stout", playlistValue{Name: "Dark"}, 0)
+	_ = first.Close()
+
+	second := newTieredCache(t, path)
+	defer second.Close()
+
+	// Stout only on disk, Lager only in memory so far
+	second.Get("spotify:playlist:IPA")
+	second.Set("spotify:playlist:Lager", playlistValue{Name: "Light"}, time.Minute)
+	second.Wait()
+
+	entries := second.Entries("spotify:playlist:", 0)
+
+	want := []string{"spotify:playlist:IPA", "spotify:playlist:Lager", "spotify:playlist:Stout"}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, entries)
+	}
+	for i, key := range want {
+		if entries[i].Key != key {
+			t.Errorf("expected entry %d to be %q, got %q", i, key, entries[i].Key)
+		}
+	}
+
+	if ttl := entries[0].TTL; ttl <= time.Minute || ttl > time.Hour {
+		t.Errorf("expected IPA to keep its remaining TTL, got %s", ttl)
+	}
+	if ttl := entries[2].TTL; ttl != 0 {
+		t.Errorf("expected Stout to have no expiry, got %s", ttl)
+	}
+
+	second.Delete("spotify:playlist:Stout")
+	if entries := second.Entries("spotify:playlist:Stout", 0); len(entries) != 0 {
+		t.Errorf("expected a deleted key not to be listed, got %+v", entries)
+	}
+}
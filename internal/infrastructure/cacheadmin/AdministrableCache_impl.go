@@ -0,0 +1,56 @@
+package cacheadmin
+
+import (
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/infrastructure/cache"
+)
+
+// AdministrableCache exposes any string-keyed cache backend to the cache
+// admin use cases.
+type AdministrableCache[V any] struct {
+	cache cache.Cache[string, V]
+}
+
+// New wraps c for administration.
+func New[V any](c cache.Cache[string, V]) *AdministrableCache[V] {
+	return &AdministrableCache[V]{cache: c}
+}
+
+func (a *AdministrableCache[V]) CacheStats() beer.CacheStats {
+	stats := a.cache.Stats()
+
+	return beer.CacheStats{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		HitRatio:  stats.HitRatio(),
+		Evictions: stats.Evictions,
+		Keys:      stats.Keys,
+		Cost:      stats.Cost,
+		MaxCost:   stats.MaxCost,
+		Errors:    stats.Errors,
+	}
+}
+
+func (a *AdministrableCache[V]) CacheEntries(prefix string, limit int) []beer.CacheEntry {
+	entries := a.cache.Entries(prefix, limit)
+
+	result := make([]beer.CacheEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, beer.CacheEntry{Key: e.Key, TTL: e.TTL})
+	}
+	return result
+}
+
+// PurgeKey checks key is cached before deleting it: entries are sorted,
+// so if it is, it is the first one listed under its own prefix.
+func (a *AdministrableCache[V]) PurgeKey(key string) bool {
+	entries := a.cache.Entries(key, 1)
+	found := len(entries) == 1 && entries[0].Key == key
+
+	a.cache.Delete(key)
+	return found
+}
+
+func (a *AdministrableCache[V]) PurgePrefix(prefix string) int {
+	return a.cache.DeleteByPrefix(prefix)
+}
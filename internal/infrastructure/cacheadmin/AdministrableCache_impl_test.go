@@ -0,0 +1,52 @@
+package cacheadmin_test
+
+import (
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/cache"
+	"karhub-beer-machine/internal/infrastructure/cacheadmin"
+)
+
+/*
+	TESTS
+*/
+
+func TestAdministrableCache(t *testing.T) {
+	memory, err := cache.NewRistrettoCache[string, string](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	memory.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	memory.Set("spotify:playlist:IPA:notfound", "", time.Minute)
+	memory.Set("spotify:playlist:Stout", "Dark", 0)
+	memory.Wait()
+	memory.Get("spotify:playlist:IPA")
+
+	admin := cacheadmin.New[string](memory)
+
+	stats := admin.CacheStats()
+	if stats.Keys != 3 || stats.Hits != 1 || stats.HitRatio != 1 {
+		t.Errorf("expected 3 keys and 1 hit, got %+v", stats)
+	}
+
+	entries := admin.CacheEntries("spotify:playlist:", 0)
+	if len(entries) != 3 || entries[0].Key != "spotify:playlist:IPA" || entries[0].TTL <= 0 {
+		t.Errorf("expected the 3 keys with their TTLs, got %+v", entries)
+	}
+
+	if !admin.PurgeKey("spotify:playlist:IPA") {
+		t.Errorf("expected the cached key to be purged")
+	}
+	if admin.PurgeKey("spotify:playlist:IPA") {
+		t.Errorf("expected a second purge to find nothing")
+	}
+	if _, found := memory.Get("spotify:playlist:IPA:notfound"); !found {
+		t.Errorf("expected a key purge not to remove keys it prefixes")
+	}
+
+	if got := admin.PurgePrefix(""); got != 2 {
+		t.Errorf("expected the 2 remaining keys to be purged, got %d", got)
+	}
+}
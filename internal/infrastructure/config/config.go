@@ -0,0 +1,188 @@
+// Package config loads the API settings from a YAML or JSON file, the
+// environment and command-line flags, in increasing precedence.
+//
+// Every setting has a file key (its `json` tag path, e.g. cache.ttl), an
+// environment variable (its `env` tag) and a flag named after the file
+// key (-cache.ttl). Any variable can also be read from the file named by
+// the same variable suffixed with _FILE, as Docker secrets are mounted.
+// Settings tagged `secret` are redacted when printed, and settings
+// tagged `reload` can be changed without a restart, through a Reloader.
+package config
+
+import (
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+// Config holds every setting of the API.
+type Config struct {
+	HTTP           HTTP           `json:"http"`
+	Log            Log            `json:"log"`
+	Tracing        Tracing        `json:"tracing"`
+	Spotify        Spotify        `json:"spotify"`
+	Music          Music          `json:"music"`
+	Cache          Cache          `json:"cache"`
+	Recommendation Recommendation `json:"recommendation"`
+	Auth           Auth           `json:"auth"`
+	RateLimit      RateLimit      `json:"rate_limit"`
+	Shutdown       Shutdown       `json:"shutdown"`
+}
+
+// HTTP configures the HTTP server.
+type HTTP struct {
+	Port         int           `json:"port" env:"HTTP_PORT" help:"port the API listens on"`
+	ReadTimeout  time.Duration `json:"read_timeout" env:"HTTP_READ_TIMEOUT" help:"time to read a whole request"`
+	WriteTimeout time.Duration `json:"write_timeout" env:"HTTP_WRITE_TIMEOUT" help:"time to write a whole response"`
+}
+
+// Log configures the structured logs.
+type Log struct {
+	Format string `json:"format" env:"LOG_FORMAT" help:"json or text"`
+	Level  string `json:"level" env:"LOG_LEVEL" reload:"true" help:"debug, info, warn or error"`
+}
+
+// Tracing configures the OTLP/HTTP span export. It is disabled when no
+// endpoint is set.
+type Tracing struct {
+	Endpoint       string  `json:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" help:"OTLP/HTTP collector base URL"`
+	TracesEndpoint string  `json:"traces_endpoint" env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT" help:"full traces URL, overrides endpoint"`
+	Headers        Headers `json:"headers" env:"OTEL_EXPORTER_OTLP_HEADERS" secret:"true" help:"key=value,... sent with every export"`
+	ServiceName    string  `json:"service_name" env:"OTEL_SERVICE_NAME" help:"service.name of the spans"`
+	SampleRatio    float64 `json:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG" help:"share of new traces recorded, 0 to 1"`
+}
+
+// Spotify configures the Spotify Web API client. Without credentials a
+// stub is used. The track settings apply to the local catalog too.
+type Spotify struct {
+	ClientID     string `json:"client_id" env:"SPOTIFY_CLIENT_ID" help:"client credentials ID"`
+	ClientSecret string `json:"client_secret" env:"SPOTIFY_CLIENT_SECRET" secret:"true" help:"client credentials secret"`
+	TrackLimit   int    `json:"track_limit" env:"SPOTIFY_TRACK_LIMIT" help:"tracks returned per playlist"`
+	SkipExplicit bool   `json:"skip_explicit" env:"SPOTIFY_SKIP_EXPLICIT" help:"drop tracks flagged as explicit"`
+}
+
+// Music configures the music providers.
+type Music struct {
+	CatalogFile     string            `json:"catalog_file" env:"MUSIC_CATALOG_FILE" help:"JSON catalog enabling the local provider"`
+	DefaultProvider string            `json:"default_provider" env:"MUSIC_DEFAULT_PROVIDER" reload:"true" help:"provider used when none is requested"`
+	TenantProviders map[string]string `json:"tenant_providers" env:"MUSIC_TENANT_PROVIDERS" reload:"true" help:"tenant=provider,... defaults"`
+}
+
+// Cache configures the playlist cache.
+type Cache struct {
+	Backend              string        `json:"backend" env:"CACHE_BACKEND" help:"memory (per replica) or redis (shared)"`
+	TTL                  time.Duration `json:"ttl" env:"CACHE_TTL" reload:"true" help:"time a playlist is fresh"`
+	StaleWhileRevalidate time.Duration `json:"stale_while_revalidate" env:"CACHE_STALE_WHILE_REVALIDATE" reload:"true" help:"age up to which playlists are served, refreshed in the background past the TTL"`
+	StaleIfError         time.Duration `json:"stale_if_error" env:"CACHE_STALE_IF_ERROR" reload:"true" help:"time past that age playlists are kept for Spotify outages"`
+	NegativeTTL          time.Duration `json:"negative_ttl" env:"CACHE_NEGATIVE_TTL" reload:"true" help:"time a style without playlist is remembered"`
+	Counters             int64         `json:"counters" env:"CACHE_COUNTERS" help:"keys tracked for admission by the memory backend"`
+	MaxCost              int64         `json:"max_cost" env:"CACHE_MAX_COST" help:"memory backend size bound"`
+	Disk                 Disk          `json:"disk"`
+	Redis                Redis         `json:"redis"`
+	Warmup               Warmup        `json:"warmup"`
+}
+
+// Disk configures the disk tier of the memory backend.
+type Disk struct {
+	Path     string `json:"path" env:"CACHE_DISK_PATH" help:"file persisting the memory backend"`
+	MaxBytes int64  `json:"max_bytes" env:"CACHE_DISK_MAX_BYTES" help:"disk tier size bound"`
+}
+
+// Redis configures the redis backend.
+type Redis struct {
+	Addr      string `json:"addr" env:"REDIS_ADDR" help:"host:port of Redis"`
+	Password  string `json:"password" env:"REDIS_PASSWORD" secret:"true" help:"Redis AUTH password"`
+	DB        int    `json:"db" env:"REDIS_DB" help:"Redis database"`
+	KeyPrefix string `json:"key_prefix" env:"REDIS_KEY_PREFIX" help:"namespace of every cache key"`
+}
+
+// Warmup configures the startup cache warmup.
+type Warmup struct {
+	Concurrency int           `json:"concurrency" env:"CACHE_WARMUP_CONCURRENCY" help:"parallel playlist fetches (0 disables)"`
+	Timeout     time.Duration `json:"timeout" env:"CACHE_WARMUP_TIMEOUT" help:"time budget of the warmup"`
+}
+
+// Recommendation configures how styles are selected, and the
+// recommendation memo.
+type Recommendation struct {
+	Strategy  string  `json:"strategy" env:"RECOMMENDATION_STRATEGY" reload:"true" help:"closest-average or containing-range"`
+	Precision float64 `json:"precision" env:"RECOMMENDATION_PRECISION" help:"degrees temperatures are rounded to (0 = exact)"`
+	Counters  int64   `json:"counters" env:"RECOMMENDATION_CACHE_COUNTERS" help:"keys tracked for admission"`
+	MaxCost   int64   `json:"max_cost" env:"RECOMMENDATION_CACHE_MAX_COST" help:"memo size bound"`
+}
+
+// Auth configures how callers are authenticated.
+type Auth struct {
+	APIKeys       []APIKey `json:"api_keys" env:"API_KEYS" help:"name:role:sha256,... static API keys"`
+	APIKeysFile   string   `json:"api_keys_file" env:"API_KEYS_FILE" help:"store of the keys issued at runtime"`
+	AdminToken    string   `json:"admin_token" env:"ADMIN_TOKEN" secret:"true" help:"a plain admin API key"`
+	JWTSecret     string   `json:"jwt_secret" env:"JWT_SECRET" secret:"true" help:"HS256 secret accepting JWT bearer tokens"`
+	JWTIssuer     string   `json:"jwt_issuer" env:"JWT_ISSUER" help:"required iss of tokens"`
+	JWTAudience   string   `json:"jwt_audience" env:"JWT_AUDIENCE" help:"required aud of tokens"`
+	AnonymousRole string   `json:"anonymous_role" env:"AUTH_ANONYMOUS_ROLE" help:"role without credentials: none, reader, operator or admin"`
+}
+
+// RateLimit configures the per-client rate limits.
+type RateLimit struct {
+	Limits        map[string]Limit `json:"limits" env:"RATE_LIMITS,allowempty" reload:"true" help:"class=rate:burst,... per client (empty disables)"`
+	DailyQuota    int              `json:"daily_quota" env:"RATE_LIMIT_DAILY_QUOTA" reload:"true" help:"requests per authenticated client and day (0 = unlimited)"`
+	MaxClients    int              `json:"max_clients" env:"RATE_LIMIT_MAX_CLIENTS" reload:"true" help:"clients tracked per rate limit bucket"`
+	ForwardedHops int              `json:"forwarded_hops" env:"RATE_LIMIT_FORWARDED_HOPS" reload:"true" help:"trusted proxies setting X-Forwarded-For"`
+}
+
+// Shutdown configures the graceful shutdown.
+type Shutdown struct {
+	DrainDelay  time.Duration `json:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" help:"time readiness fails before connections are refused"`
+	GracePeriod time.Duration `json:"grace_period" env:"SHUTDOWN_GRACE_PERIOD" help:"time in-flight requests get to finish"`
+}
+
+// Default returns the settings used when nothing overrides them.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Port:         8080,
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		},
+		Log: Log{Format: "json", Level: "info"},
+		Tracing: Tracing{
+			ServiceName: "karhub-beer-machine",
+			SampleRatio: 1,
+		},
+		Spotify: Spotify{TrackLimit: 10},
+		Music:   Music{DefaultProvider: "spotify"},
+		Cache: Cache{
+			Backend:              "memory",
+			TTL:                  10 * time.Minute,
+			StaleWhileRevalidate: time.Hour,
+			StaleIfError:         24 * time.Hour,
+			NegativeTTL:          2 * time.Minute,
+			Counters:             1e5,
+			MaxCost:              1 << 20, // ~1MB
+			Disk:                 Disk{MaxBytes: 64 << 20},
+			Redis:                Redis{Addr: "localhost:6379", KeyPrefix: "karhub:"},
+			Warmup:               Warmup{Concurrency: 4, Timeout: 30 * time.Second},
+		},
+		Recommendation: Recommendation{
+			Strategy:  beer.DefaultSelectionStrategy,
+			Precision: 0.1,
+			Counters:  1e5,
+			MaxCost:   1 << 20,
+		},
+		Auth: Auth{AnonymousRole: "reader"},
+		RateLimit: RateLimit{
+			// Generous enough for people, and keeps a single runaway
+			// client from exhausting the Spotify quota
+			Limits: map[string]Limit{
+				"read":      {Rate: 20, Burst: 40},
+				"recommend": {Rate: 5, Burst: 10},
+				"write":     {Rate: 2, Burst: 5},
+				"admin":     {Rate: 2, Burst: 5},
+				// Per IP address: 10 wrong credentials, then one every 5s
+				"auth_failure": {Rate: 0.2, Burst: 10},
+			},
+			MaxClients: 10000,
+		},
+		Shutdown: Shutdown{GracePeriod: 20 * time.Second},
+	}
+}
@@ -0,0 +1,576 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/reload"
+	"karhub-beer-machine/internal/infrastructure/config"
+)
+
+const adminHash = "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918"
+
+func env(vars map[string]string) config.LookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+/*
+	TESTS
+*/
+
+func TestDefault_IsValid(t *testing.T) {
+	if err := config.Default().Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+http:
+  port: 9000
+  read_timeout: 7s
+cache:
+  ttl: 1m
+  backend: redis
+log:
+  level: debug
+`)
+
+	vars := map[string]string{
+		"HTTP_PORT":     "9001",
+		"CACHE_TTL":     "2m",
+		"LOG_FORMAT":    "", // empty variables are ignored
+		"KARHUB_CONFIG": path,
+	}
+
+	cfg, err := config.Load([]string{"-http.port=9002", "-spotify.skip_explicit"}, env(vars), io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"flag over env and file", cfg.HTTP.Port, 9002},
+		{"env over file", cfg.Cache.TTL, 2 * time.Minute},
+		{"file over default", cfg.HTTP.ReadTimeout, 7 * time.Second},
+		{"file over default", cfg.Cache.Backend, "redis"},
+		{"file over default", cfg.Log.Level, "debug"},
+		{"default", cfg.Log.Format, "json"},
+		{"default", cfg.HTTP.WriteTimeout, 10 * time.Second},
+		{"boolean flag", cfg.Spotify.SkipExplicit, true},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+}
+
+func TestLoad_ConfigFlagOverridesEnv(t *testing.T) {
+	fromEnv := writeFile(t, "env.yaml", "http:\n  port: 9000\n")
+	fromFlag := writeFile(t, "flag.json", `{"http": {"port": 9100}, "rate_limit": {"limits": {"read": "1:2"}}}`)
+
+	cfg, err := config.Load([]string{"-config", fromFlag}, env(map[string]string{"KARHUB_CONFIG": fromEnv}), io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.HTTP.Port != 9100 {
+		t.Errorf("expected the -config file read, got port %d", cfg.HTTP.Port)
+	}
+	want := map[string]config.Limit{"read": {Rate: 1, Burst: 2}}
+	if !reflect.DeepEqual(cfg.RateLimit.Limits, want) {
+		t.Errorf("expected a file mapping to replace the default limits, got %v", cfg.RateLimit.Limits)
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	var out bytes.Buffer
+
+	_, err := config.Load([]string{"-h"}, env(nil), &out)
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("expected flag.ErrHelp, got %v", err)
+	}
+	if !strings.Contains(out.String(), "-cache.ttl") || !strings.Contains(out.String(), "$CACHE_TTL") {
+		t.Fatalf("expected every setting in the usage, got:\n%s", out.String())
+	}
+}
+
+func TestLoadFile_Values(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+auth:
+  api_keys:
+    - ci:admin:`+adminHash+`
+rate_limit:
+  limits:
+    read: "10:20"
+    write: 3
+music:
+  tenant_providers: {}
+tracing:
+  headers:
+    authorization: Bearer abc, def
+`)
+
+	vars := map[string]string{
+		"MUSIC_TENANT_PROVIDERS":     "kiosk-a=local, kiosk-b=spotify",
+		"OTEL_EXPORTER_OTLP_HEADERS": "x-team=beer%2Cmachine",
+	}
+
+	cfg, err := config.LoadFile(path, env(vars))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantKeys := []config.APIKey{{Name: "ci", Role: auth.RoleAdmin, Hash: adminHash}}
+	if !reflect.DeepEqual(cfg.Auth.APIKeys, wantKeys) {
+		t.Errorf("expected %v, got %v", wantKeys, cfg.Auth.APIKeys)
+	}
+
+	wantLimits := map[string]config.Limit{"read": {Rate: 10, Burst: 20}, "write": {Rate: 3, Burst: 3}}
+	if !reflect.DeepEqual(cfg.RateLimit.Limits, wantLimits) {
+		t.Errorf("expected %v, got %v", wantLimits, cfg.RateLimit.Limits)
+	}
+
+	wantTenants := map[string]string{"kiosk-a": "local", "kiosk-b": "spotify"}
+	if !reflect.DeepEqual(cfg.Music.TenantProviders, wantTenants) {
+		t.Errorf("expected %v, got %v", wantTenants, cfg.Music.TenantProviders)
+	}
+
+	// Encoded values are only decoded from the text form
+	wantHeaders := config.Headers{"x-team": "beer,machine"}
+	if !reflect.DeepEqual(cfg.Tracing.Headers, wantHeaders) {
+		t.Errorf("expected %v, got %v", wantHeaders, cfg.Tracing.Headers)
+	}
+}
+
+func TestLoadFile_EmptyRateLimitsDisable(t *testing.T) {
+	cfg, err := config.LoadFile("", env(map[string]string{"RATE_LIMITS": ""}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.RateLimit.Limits) != 0 {
+		t.Fatalf("expected no limits, got %v", cfg.RateLimit.Limits)
+	}
+}
+
+func TestLoadFile_SecretFiles(t *testing.T) {
+	secret := writeFile(t, "spotify_secret", "s3cr3t\n")
+
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "read from file, without the trailing newline",
+			vars: map[string]string{"SPOTIFY_CLIENT_ID": "id", "SPOTIFY_CLIENT_SECRET_FILE": secret},
+			want: "s3cr3t",
+		},
+		{
+			name:    "both set",
+			vars:    map[string]string{"SPOTIFY_CLIENT_ID": "id", "SPOTIFY_CLIENT_SECRET": "x", "SPOTIFY_CLIENT_SECRET_FILE": secret},
+			wantErr: "both SPOTIFY_CLIENT_SECRET and SPOTIFY_CLIENT_SECRET_FILE are set",
+		},
+		{
+			name:    "missing file",
+			vars:    map[string]string{"SPOTIFY_CLIENT_SECRET_FILE": secret + ".missing"},
+			wantErr: "SPOTIFY_CLIENT_SECRET_FILE: open",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadFile("", env(tt.vars))
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Spotify.ClientSecret != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, cfg.Spotify.ClientSecret)
+			}
+		})
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		vars     map[string]string
+		wantErrs []string
+	}{
+		{
+			name:     "unknown keys",
+			file:     "config.yaml",
+			content:  "htp:\n  port: 1\ncache:\n  tll: 1m\n",
+			wantErrs: []string{`unknown key "cache.tll"`, `unknown key "htp"`},
+		},
+		{
+			name:     "wrong types",
+			file:     "config.json",
+			content:  `{"http": {"port": "eighty"}, "cache": {"ttl": 60, "redis": "localhost"}}`,
+			wantErrs: []string{`http.port: invalid integer "eighty"`, `cache.ttl: invalid duration "60"`, "cache.redis: want a mapping"},
+		},
+		{
+			name:     "wrong shapes",
+			file:     "config.yaml",
+			content:  "music:\n  tenant_providers: [a, b]\nlog:\n  level:\n    - debug\n",
+			wantErrs: []string{"music.tenant_providers: want a mapping, got a list", "log.level: want a single value, got a list"},
+		},
+		{
+			name:     "invalid env",
+			vars:     map[string]string{"REDIS_DB": "one", "API_KEYS": "ci:root:abc", "RATE_LIMITS": "read=fast"},
+			wantErrs: []string{`REDIS_DB: invalid integer "one"`, `API_KEYS: invalid API key "ci"`, `RATE_LIMITS: invalid limit "fast"`},
+		},
+		{
+			name: "validation",
+			vars: map[string]string{
+				"HTTP_PORT":                   "70000",
+				"LOG_FORMAT":                  "xml",
+				"CACHE_TTL":                   "-1m",
+				"OTEL_TRACES_SAMPLER_ARG":     "2",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:4318",
+				"JWT_SECRET":                  "short",
+				"AUTH_ANONYMOUS_ROLE":         "root",
+				"RATE_LIMITS":                 "burst=1:1",
+				"SPOTIFY_CLIENT_ID":           "id-without-secret",
+				"RECOMMENDATION_STRATEGY":     "warmest",
+			},
+			wantErrs: []string{
+				"http.port: must be between 1 and 65535, got 70000",
+				`log.format: must be one of [json text], got "xml"`,
+				"cache.ttl: must be positive, got -1m0s",
+				"tracing.sample_ratio: must be between 0 and 1, got 2",
+				`tracing.endpoint: must be an http(s) URL, got "localhost:4318"`,
+				"auth.jwt_secret: must be at least 32 bytes, got 5",
+				`auth.anonymous_role: must be none, reader, operator or admin, got "root"`,
+				`rate_limit.limits: unknown class "burst"`,
+				"spotify: client_id and client_secret must be set together",
+				`recommendation.strategy: must be one of [closest-average containing-range], got "warmest"`,
+			},
+		},
+		{
+			name:     "missing file",
+			file:     "missing.yaml",
+			wantErrs: []string{"reading config file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), tt.file)
+				if tt.content != "" {
+					path = writeFile(t, tt.file, tt.content)
+				}
+			}
+
+			_, err := config.LoadFile(path, env(tt.vars))
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFile_YAMLSyntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "duplicate key", content: "log:\n  level: info\n  level: debug\n", wantErr: `line 3: duplicate key "level"`},
+		{name: "bad indentation", content: "log:\n    level: info\n  format: json\n", wantErr: "line 2: did not find expected key"},
+		{name: "tab indentation", content: "log:\n\tlevel: info\n", wantErr: "line 2: found character that cannot start any token"},
+		{name: "not a mapping", content: "just text\n", wantErr: "line 1: want a mapping at the top level"},
+		{name: "mapping in sequence", content: "auth:\n  api_keys:\n    - name: ci\n", wantErr: "auth.api_keys: item 0: want a single value"},
+		{name: "unterminated quote", content: "log:\n  level: \"debug\n", wantErr: "found unexpected end of stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.LoadFile(writeFile(t, "config.yml", tt.content), env(nil))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadFile_YAML(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+auth:
+  jwt_issuer: O'Brien  # owner's team
+  jwt_audience: "beer # machine"
+  jwt_secret: >-
+    a secret folded
+    over two lines, long enough
+music: {default_provider: local, tenant_providers: {kiosk-a: spotify}}
+`)
+
+	cfg, err := config.LoadFile(path, env(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"apostrophe before a comment", cfg.Auth.JWTIssuer, "O'Brien"},
+		{"hash inside quotes", cfg.Auth.JWTAudience, "beer # machine"},
+		{"folded block scalar", cfg.Auth.JWTSecret, "a secret folded over two lines, long enough"},
+		{"flow mapping", cfg.Music.DefaultProvider, "local"},
+		{"nested flow mapping", cfg.Music.TenantProviders["kiosk-a"], "spotify"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+}
+
+func TestConfig_RedactAndWriteYAML(t *testing.T) {
+	cfg := config.Default()
+	cfg.Spotify.ClientID = "client"
+	cfg.Spotify.ClientSecret = "spotify-secret"
+	cfg.Auth.JWTSecret = "jwt-secret-jwt-secret-jwt-secret"
+	cfg.Auth.APIKeys = []config.APIKey{{Name: "ci", Role: auth.RoleAdmin, Hash: adminHash}}
+	cfg.Cache.Redis.KeyPrefix = `we"ird: #prefix`
+	cfg.Tracing.Headers = config.Headers{"authorization": "Bearer token"}
+
+	redacted := cfg.Redact()
+
+	if cfg.Tracing.Headers["authorization"] != "Bearer token" || cfg.Spotify.ClientSecret != "spotify-secret" {
+		t.Fatalf("expected Redact to leave the original untouched")
+	}
+
+	var out bytes.Buffer
+	if err := redacted.WriteYAML(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	printed := out.String()
+
+	for _, secret := range []string{"spotify-secret", "jwt-secret", "Bearer token"} {
+		if strings.Contains(printed, secret) {
+			t.Errorf("expected %q redacted in:\n%s", secret, printed)
+		}
+	}
+	for _, line := range []string{
+		"  client_secret: \"[REDACTED]\" # SPOTIFY_CLIENT_SECRET\n",
+		"  admin_token: \"\" # ADMIN_TOKEN\n",
+		"  ttl: 10m0s # CACHE_TTL\n",
+		"    read: \"20:40\"\n",
+	} {
+		if !strings.Contains(printed, line) {
+			t.Errorf("expected %q in:\n%s", line, printed)
+		}
+	}
+
+	// The printed file loads back into the same settings
+	var unredacted bytes.Buffer
+	if err := cfg.WriteYAML(&unredacted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := config.LoadFile(writeFile(t, "config.yaml", unredacted.String()), env(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Fatalf("expected the printed config to load back:\n%s", unredacted.String())
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	initial := `
+http:
+  port: 8080
+cache:
+  ttl: 10m
+music:
+  default_provider: spotify
+`
+
+	tests := []struct {
+		name        string
+		content     string
+		vars        map[string]string
+		rejectMusic bool
+		wantErr     bool
+		wantChanges []reload.Change
+		wantTTL     time.Duration
+	}{
+		{
+			name:    "unchanged",
+			content: initial,
+			wantTTL: 10 * time.Minute,
+		},
+		{
+			name: "reloadable and restart-only settings",
+			content: `
+http:
+  port: 9090
+cache:
+  ttl: 5m
+music:
+  default_provider: spotify
+recommendation:
+  strategy: containing-range
+rate_limit:
+  limits:
+    read: "1:2"
+`,
+			wantChanges: []reload.Change{
+				{Key: "http.port", Old: "8080", New: "9090"},
+				{Key: "cache.ttl", Old: "10m0s", New: "5m0s", Applied: true},
+				{Key: "recommendation.strategy", Old: "closest-average", New: "containing-range", Applied: true},
+				{Key: "rate_limit.limits", Old: "admin=2:5,auth_failure=0.2:10,read=20:40,recommend=5:10,write=2:5", New: "read=1:2", Applied: true},
+			},
+			wantTTL: 5 * time.Minute,
+		},
+		{
+			name:        "secrets are redacted",
+			content:     initial,
+			vars:        map[string]string{"REDIS_PASSWORD": "hunter2"},
+			wantChanges: []reload.Change{{Key: "cache.redis.password", Old: "", New: config.Redacted}},
+			wantTTL:     10 * time.Minute,
+		},
+		{
+			name:    "invalid file",
+			content: "cache:\n  ttl: 0s\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			content: "cache:\n  tll: 5m\n",
+			wantErr: true,
+		},
+		{
+			name:        "rejected by a component",
+			content:     "cache:\n  ttl: 5m\nmusic:\n  default_provider: deezer\n",
+			rejectMusic: true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, "config.yaml", initial)
+
+			running, err := config.LoadFile(path, env(nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var applied []config.Config
+			component := config.Component{
+				Name: "music",
+				Check: func(cfg config.Config) error {
+					if tt.rejectMusic && cfg.Music.DefaultProvider != "spotify" {
+						return errors.New("unknown music provider")
+					}
+					return nil
+				},
+				Apply: func(cfg config.Config) {
+					applied = append(applied, cfg)
+				},
+			}
+
+			reloader := config.NewReloader(running, func() (config.Config, error) {
+				return config.LoadFile(path, env(tt.vars))
+			}, component)
+
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to rewrite config: %v", err)
+			}
+
+			changes, err := reloader.Reload(context.Background())
+
+			if tt.wantErr {
+				if !errors.Is(err, reload.ErrInvalidConfig) {
+					t.Fatalf("expected ErrInvalidConfig, got %v", err)
+				}
+				if len(applied) != 0 {
+					t.Fatalf("expected nothing applied, got %d", len(applied))
+				}
+				if !reflect.DeepEqual(reloader.Current(), running) {
+					t.Fatalf("expected the running configuration to stay in place")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Fatalf("expected changes %+v, got %+v", tt.wantChanges, changes)
+			}
+
+			current := reloader.Current()
+			if current.Cache.TTL != tt.wantTTL {
+				t.Fatalf("expected TTL %s, got %s", tt.wantTTL, current.Cache.TTL)
+			}
+			if current.HTTP.Port != 8080 || current.Cache.Redis.Password != "" {
+				t.Fatalf("expected restart-only settings to keep their running values, got %+v", current)
+			}
+
+			wantApplied := 0
+			for _, change := range tt.wantChanges {
+				if change.Applied {
+					wantApplied = 1
+				}
+			}
+			if len(applied) != wantApplied {
+				t.Fatalf("expected %d apply, got %d", wantApplied, len(applied))
+			}
+			if wantApplied == 1 && !reflect.DeepEqual(applied[0], current) {
+				t.Fatalf("expected the current configuration to be applied")
+			}
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileEnv names the variable holding the config file path, which the
+// -config flag overrides.
+const FileEnv = "KARHUB_CONFIG"
+
+// LookupFunc looks up an environment variable, like os.LookupEnv.
+type LookupFunc func(key string) (string, bool)
+
+// Load reads the config file named by -config or $KARHUB_CONFIG, if any,
+// then the environment, then the other flags in args, over the defaults.
+// The result is validated. With -h, it returns flag.ErrHelp after
+// writing the usage to output.
+func Load(args []string, lookup LookupFunc, output io.Writer) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	path, _ := lookup(FileEnv)
+	flags.StringVar(&path, "config", path, "YAML or JSON config file ($"+FileEnv+")")
+
+	// Flags are applied last, so only record them while parsing
+	var set []flagValue
+	for _, f := range fields(&cfg) {
+		usage := f.help
+		if f.env != "" {
+			usage += " ($" + f.env + ")"
+		}
+
+		record := func(value string) error {
+			set = append(set, flagValue{field: f, value: value})
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			flags.BoolFunc(f.path, usage, record)
+		} else {
+			flags.Func(f.path, usage, record)
+		}
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if flags.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if err := load(&cfg, path, lookup); err != nil {
+		return Config{}, err
+	}
+
+	var errs []error
+	for _, f := range set {
+		if err := setText(f.field.value, f.value); err != nil {
+			errs = append(errs, fmt.Errorf("flag -%s: %w", f.field.path, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// LoadFile reads the config file at path, if not empty, then the
+// environment, over the defaults. The result is validated.
+func LoadFile(path string, lookup LookupFunc) (Config, error) {
+	cfg := Default()
+
+	if err := load(&cfg, path, lookup); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+type flagValue struct {
+	field field
+	value string
+}
+
+func load(cfg *Config, path string, lookup LookupFunc) error {
+	if path != "" {
+		if err := applyFile(cfg, path); err != nil {
+			return err
+		}
+	}
+	return applyEnv(cfg, lookup)
+}
+
+// applyFile decodes a .json file as JSON, and any other as YAML.
+func applyFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	var tree map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		tree, err = parseJSON(data)
+	} else {
+		tree, err = parseYAML(data)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := applyTree(reflect.ValueOf(cfg).Elem(), tree, ""); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// parseJSON decodes data into the tree parseYAML returns, with scalars
+// as strings.
+func parseJSON(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var tree map[string]any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return jsonTree(tree).(map[string]any), nil
+}
+
+func jsonTree(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = jsonTree(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = jsonTree(child)
+		}
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// applyTree sets the fields of the struct v from a parsed file. Unknown
+// keys are errors, so typos do not go unnoticed.
+func applyTree(v reflect.Value, tree map[string]any, prefix string) error {
+	var errs []error
+
+	for _, key := range slices.Sorted(maps.Keys(tree)) {
+		node, path := tree[key], prefix+key
+
+		field, ok := fieldByKey(v, key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", path))
+			continue
+		}
+
+		if isSection(field) {
+			children, ok := node.(map[string]any)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: want a mapping", path))
+				continue
+			}
+			if err := applyTree(field, children, path+"."); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if err := setNode(field, node); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyEnv sets the fields from their variables, or from the files named
+// by their _FILE variables. Empty variables are ignored, unless the
+// field is tagged allowempty.
+func applyEnv(cfg *Config, lookup LookupFunc) error {
+	var errs []error
+
+	for _, f := range fields(cfg) {
+		if f.env == "" {
+			continue
+		}
+
+		value, set := lookup(f.env)
+		if file, fileSet := lookup(f.env + "_FILE"); fileSet && file != "" {
+			if set && value != "" {
+				errs = append(errs, fmt.Errorf("both %s and %s_FILE are set", f.env, f.env))
+				continue
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s_FILE: %w", f.env, err))
+				continue
+			}
+			value, set = strings.TrimRight(string(data), "\r\n"), true
+		}
+
+		if !set || (value == "" && !f.allowEmpty) {
+			continue
+		}
+
+		if err := setText(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// field is a setting: a leaf of the Config struct.
+type field struct {
+	path       string
+	env        string
+	allowEmpty bool
+	secret     bool
+	reloadable bool
+	help       string
+	value      reflect.Value
+}
+
+// fields lists the settings of cfg in declaration order.
+func fields(cfg *Config) []field {
+	var all []field
+	collectFields(reflect.ValueOf(cfg).Elem(), "", &all)
+	return all
+}
+
+func collectFields(v reflect.Value, prefix string, all *[]field) {
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		path := prefix + jsonKey(sf)
+
+		if isSection(v.Field(i)) {
+			collectFields(v.Field(i), path+".", all)
+			continue
+		}
+
+		env, options, _ := strings.Cut(sf.Tag.Get("env"), ",")
+		*all = append(*all, field{
+			path:       path,
+			env:        env,
+			allowEmpty: options == "allowempty",
+			secret:     sf.Tag.Get("secret") == "true",
+			reloadable: sf.Tag.Get("reload") == "true",
+			help:       sf.Tag.Get("help"),
+			value:      v.Field(i),
+		})
+	}
+}
+
+func jsonKey(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+func fieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := range v.NumField() {
+		if jsonKey(v.Type().Field(i)) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isSection reports whether v groups settings rather than being one.
+func isSection(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType)
+}
+
+// setNode sets v from a parsed file node: a scalar, a list or a mapping.
+func setNode(v reflect.Value, node any) error {
+	switch n := node.(type) {
+	case string:
+		return setText(v, n)
+
+	case []any:
+		switch v.Kind() {
+		case reflect.Slice:
+		case reflect.Map:
+			return fmt.Errorf("want a mapping, got a list")
+		default:
+			return fmt.Errorf("want a single value, got a list")
+		}
+		items := reflect.MakeSlice(v.Type(), len(n), len(n))
+		for i, item := range n {
+			text, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("item %d: want a single value", i)
+			}
+			if err := setText(items.Index(i), text); err != nil {
+				return err
+			}
+		}
+		setCollection(v, items)
+		return nil
+
+	case map[string]any:
+		switch v.Kind() {
+		case reflect.Map:
+		case reflect.Slice:
+			return fmt.Errorf("want a list, got a mapping")
+		default:
+			return fmt.Errorf("want a single value, got a mapping")
+		}
+		entries := reflect.MakeMapWithSize(v.Type(), len(n))
+		for key, item := range n {
+			text, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s: want a single value", key)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setText(elem, text); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			entries.SetMapIndex(reflect.ValueOf(key), elem)
+		}
+		setCollection(v, entries)
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported value %v", node)
+	}
+}
+
+// setText sets v from its text form. Lists are comma-separated, and
+// mappings are "key=value,..." lists.
+func setText(v reflect.Value, text string) error {
+	text = strings.TrimSpace(text)
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (want e.g. 30s or 10m)", text)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (want true or false)", text)
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", text)
+		}
+		v.SetInt(n)
+
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", text)
+		}
+		v.SetFloat(f)
+
+	case reflect.Slice:
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range splitList(text) {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setText(elem, item); err != nil {
+				return err
+			}
+			items = reflect.Append(items, elem)
+		}
+		setCollection(v, items)
+
+	case reflect.Map:
+		entries := reflect.MakeMap(v.Type())
+		for _, item := range splitList(text) {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("invalid entry %q (want key=value)", item)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setText(elem, value); err != nil {
+				return err
+			}
+			entries.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), elem)
+		}
+		setCollection(v, entries)
+
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+
+	return nil
+}
+
+// setCollection sets a list or mapping, leaving it nil when empty.
+func setCollection(v, collection reflect.Value) {
+	if collection.Len() == 0 {
+		v.SetZero()
+		return
+	}
+	v.Set(collection)
+}
+
+// splitList splits "a, b,,c" into its non-empty items.
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
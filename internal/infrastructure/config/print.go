@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redacted is what secrets are replaced with when printed.
+const Redacted = "[REDACTED]"
+
+// Redact returns a copy of c with every secret that is set replaced.
+func (c Config) Redact() Config {
+	// Copy the maps, so redacting does not touch c's
+	c.Tracing.Headers = maps.Clone(c.Tracing.Headers)
+
+	for _, f := range fields(&c) {
+		if !f.secret {
+			continue
+		}
+
+		switch f.value.Kind() {
+		case reflect.String:
+			if f.value.String() != "" {
+				f.value.SetString(Redacted)
+			}
+		case reflect.Map:
+			for _, key := range f.value.MapKeys() {
+				f.value.SetMapIndex(key, reflect.ValueOf(Redacted).Convert(f.value.Type().Elem()))
+			}
+		}
+	}
+
+	return c
+}
+
+// WriteYAML writes c as a YAML config file, each setting commented with
+// its environment variable. Redact it first unless secrets may be shown.
+func (c Config) WriteYAML(w io.Writer) error {
+	var b strings.Builder
+	writeSection(&b, reflect.ValueOf(c), 0)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSection(b *strings.Builder, v reflect.Value, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		value := v.Field(i)
+		key := jsonKey(sf)
+
+		if isSection(value) {
+			fmt.Fprintf(b, "%s%s:\n", indent, key)
+			writeSection(b, value, depth+1)
+			continue
+		}
+
+		comment := ""
+		if env, _, _ := strings.Cut(sf.Tag.Get("env"), ","); env != "" {
+			comment = " # " + env
+		}
+
+		switch {
+		case value.Kind() == reflect.Map && value.Len() > 0:
+			fmt.Fprintf(b, "%s%s:%s\n", indent, key, comment)
+			keys := value.MapKeys()
+			slices.SortFunc(keys, func(a, b reflect.Value) int {
+				return strings.Compare(a.String(), b.String())
+			})
+			for _, k := range keys {
+				fmt.Fprintf(b, "%s  %s: %s\n", indent, quote(k.String()), formatScalar(value.MapIndex(k)))
+			}
+		case value.Kind() == reflect.Map:
+			fmt.Fprintf(b, "%s%s: {}%s\n", indent, key, comment)
+		case value.Kind() == reflect.Slice && value.Len() > 0:
+			fmt.Fprintf(b, "%s%s:%s\n", indent, key, comment)
+			for j := range value.Len() {
+				fmt.Fprintf(b, "%s  - %s\n", indent, formatScalar(value.Index(j)))
+			}
+		case value.Kind() == reflect.Slice:
+			fmt.Fprintf(b, "%s%s: []%s\n", indent, key, comment)
+		default:
+			fmt.Fprintf(b, "%s%s: %s%s\n", indent, key, formatScalar(value), comment)
+		}
+	}
+}
+
+// formatScalar formats a single setting as it would be written in a
+// config file.
+func formatScalar(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return quote(err.Error())
+		}
+		return quote(string(text))
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.String:
+		return quote(v.String())
+	case v.Kind() == reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// quote double-quotes s unless it reads back as the same plain scalar.
+func quote(s string) string {
+	plain := s != "" &&
+		s == strings.TrimSpace(s) &&
+		!strings.ContainsAny(s, ":#,[]{}\"'&*!|>%@`") &&
+		!strings.HasPrefix(s, "-") &&
+		s != "~" && s != "null"
+	if plain {
+		return s
+	}
+	return strconv.Quote(s)
+}
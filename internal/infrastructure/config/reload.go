@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"karhub-beer-machine/internal/application/reload"
+)
+
+// Component is a part of the service built from reloadable settings.
+type Component struct {
+	// Name identifies the component in errors.
+	Name string
+
+	// Check rejects a configuration the component cannot apply, e.g. a
+	// default provider that is not registered. It is optional.
+	Check func(cfg Config) error
+
+	// Apply swaps in the settings of cfg, atomically for the requests it
+	// serves. It only runs once every component accepted cfg, so it
+	// cannot fail.
+	Apply func(cfg Config)
+}
+
+// Reloader loads the configuration again on demand and applies its
+// reloadable settings to the registered components. The other settings
+// keep their running values until the next restart.
+type Reloader struct {
+	load       func() (Config, error)
+	components []Component
+
+	mu      sync.Mutex
+	current Config
+}
+
+// NewReloader creates a Reloader for the running configuration current,
+// reloaded with load (e.g. Load with the command-line arguments).
+func NewReloader(current Config, load func() (Config, error), components ...Component) *Reloader {
+	return &Reloader{
+		load:       load,
+		components: components,
+		current:    current,
+	}
+}
+
+// Current returns the running configuration.
+func (r *Reloader) Current() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+// Reload loads and validates the configuration, then applies it to every
+// component, or to none when one rejects it. It returns every setting
+// that differs from the running one; those that cannot be reloaded are
+// reported as not applied, and again on the next reloads until a
+// restart.
+func (r *Reloader) Reload(_ context.Context) ([]reload.Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loaded, err := r.load()
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n%w", reload.ErrInvalidConfig, err)
+	}
+
+	next := r.current
+	changes, reloaded := diff(&next, &loaded)
+	if !reloaded {
+		return changes, nil
+	}
+
+	// The settings are valid together, but maybe not with the running
+	// values of those that need a restart
+	errs := []error{next.Validate()}
+	for _, c := range r.components {
+		if c.Check == nil {
+			continue
+		}
+		if err := c.Check(next); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("%w:\n%w", reload.ErrInvalidConfig, err)
+	}
+
+	for _, c := range r.components {
+		c.Apply(next)
+	}
+	r.current = next
+
+	return changes, nil
+}
+
+// diff lists the settings of loaded that differ from running, and copies
+// the reloadable ones over running. It reports whether it copied any.
+func diff(running, loaded *Config) ([]reload.Change, bool) {
+	var (
+		changes  []reload.Change
+		reloaded bool
+	)
+
+	next := fields(loaded)
+	for i, f := range fields(running) {
+		if reflect.DeepEqual(f.value.Interface(), next[i].value.Interface()) {
+			continue
+		}
+
+		changes = append(changes, reload.Change{
+			Key:     f.path,
+			Old:     auditText(f, f.value),
+			New:     auditText(f, next[i].value),
+			Applied: f.reloadable,
+		})
+
+		if f.reloadable {
+			f.value.Set(next[i].value)
+			reloaded = true
+		}
+	}
+
+	return changes, reloaded
+}
+
+// auditText formats the value of f as an environment variable would set
+// it, redacting secrets.
+func auditText(f field, v reflect.Value) string {
+	if f.secret && !v.IsZero() {
+		return Redacted
+	}
+	return text(v)
+}
+
+func text(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range v.Len() {
+			items[i] = text(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	case v.Kind() == reflect.Map:
+		entries := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			entries = append(entries, key.String()+"="+text(v.MapIndex(key)))
+		}
+		slices.Sort(entries)
+		return strings.Join(entries, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/infrastructure/jwt"
+)
+
+// RateLimitClasses are the route classes a limit can be set for.
+var RateLimitClasses = []string{"read", "recommend", "write", "admin", "auth_failure"}
+
+// Validate reports every invalid setting at once, each prefixed with
+// its file key.
+func (c Config) Validate() error {
+	v := &validator{}
+
+	v.check(c.HTTP.Port >= 1 && c.HTTP.Port <= 65535, "http.port",
+		"must be between 1 and 65535, got %d", c.HTTP.Port)
+	positive(v, "http.read_timeout", c.HTTP.ReadTimeout)
+	positive(v, "http.write_timeout", c.HTTP.WriteTimeout)
+
+	v.oneOf("log.format", c.Log.Format, "json", "text")
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level",
+		"must be debug, info, warn or error, got %q", c.Log.Level)
+
+	v.url("tracing.endpoint", c.Tracing.Endpoint)
+	v.url("tracing.traces_endpoint", c.Tracing.TracesEndpoint)
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio",
+		"must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	v.check(c.Tracing.ServiceName != "", "tracing.service_name", "must not be empty")
+
+	v.check((c.Spotify.ClientID == "") == (c.Spotify.ClientSecret == ""), "spotify",
+		"client_id and client_secret must be set together")
+	positive(v, "spotify.track_limit", c.Spotify.TrackLimit)
+
+	v.check(c.Music.DefaultProvider != "", "music.default_provider", "must not be empty")
+	for tenant, provider := range c.Music.TenantProviders {
+		v.check(provider != "", "music.tenant_providers", "tenant %q has no provider", tenant)
+	}
+
+	v.oneOf("cache.backend", c.Cache.Backend, "memory", "redis")
+	positive(v, "cache.ttl", c.Cache.TTL)
+	v.check(c.Cache.StaleWhileRevalidate >= c.Cache.TTL, "cache.stale_while_revalidate",
+		"must be at least the TTL (%s), got %s", c.Cache.TTL, c.Cache.StaleWhileRevalidate)
+	nonNegative(v, "cache.stale_if_error", c.Cache.StaleIfError)
+	nonNegative(v, "cache.negative_ttl", c.Cache.NegativeTTL)
+	positive(v, "cache.counters", c.Cache.Counters)
+	positive(v, "cache.max_cost", c.Cache.MaxCost)
+	positive(v, "cache.disk.max_bytes", c.Cache.Disk.MaxBytes)
+	v.check(c.Cache.Redis.Addr != "", "cache.redis.addr", "must not be empty")
+	nonNegative(v, "cache.redis.db", c.Cache.Redis.DB)
+	nonNegative(v, "cache.warmup.concurrency", c.Cache.Warmup.Concurrency)
+	positive(v, "cache.warmup.timeout", c.Cache.Warmup.Timeout)
+
+	v.oneOf("recommendation.strategy", c.Recommendation.Strategy, beer.SelectionStrategies()...)
+	v.check(c.Recommendation.Precision >= 0, "recommendation.precision",
+		"must not be negative, got %g", c.Recommendation.Precision)
+	positive(v, "recommendation.counters", c.Recommendation.Counters)
+	positive(v, "recommendation.max_cost", c.Recommendation.MaxCost)
+
+	if c.Auth.JWTSecret != "" {
+		v.check(len(c.Auth.JWTSecret) >= jwt.MinSecretLength, "auth.jwt_secret",
+			"must be at least %d bytes, got %d", jwt.MinSecretLength, len(c.Auth.JWTSecret))
+	}
+	if c.Auth.AnonymousRole != "none" {
+		_, err := auth.ParseRole(c.Auth.AnonymousRole)
+		v.check(err == nil, "auth.anonymous_role",
+			"must be none, reader, operator or admin, got %q", c.Auth.AnonymousRole)
+	}
+
+	for class := range c.RateLimit.Limits {
+		v.check(slices.Contains(RateLimitClasses, class), "rate_limit.limits",
+			"unknown class %q (want read, recommend, write, admin or auth_failure)", class)
+	}
+	nonNegative(v, "rate_limit.daily_quota", c.RateLimit.DailyQuota)
+	positive(v, "rate_limit.max_clients", c.RateLimit.MaxClients)
+	nonNegative(v, "rate_limit.forwarded_hops", c.RateLimit.ForwardedHops)
+
+	nonNegative(v, "shutdown.drain_delay", c.Shutdown.DrainDelay)
+	positive(v, "shutdown.grace_period", c.Shutdown.GracePeriod)
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, key, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+}
+
+func positive[T int | int64 | time.Duration](v *validator, key string, n T) {
+	v.check(n > 0, key, "must be positive, got %v", n)
+}
+
+func nonNegative[T int | int64 | time.Duration](v *validator, key string, n T) {
+	v.check(n >= 0, key, "must not be negative, got %v", n)
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), key, "must be one of %v, got %q", allowed, value)
+}
+
+func (v *validator) url(key, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", key,
+		"must be an http(s) URL, got %q", value)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"karhub-beer-machine/internal/application/auth"
+)
+
+// Limit is a token bucket: Rate requests per second, up to Burst at
+// once. A Rate of 0 leaves the class unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// UnmarshalText parses "rate:burst", or "rate" for a burst of one
+// second's worth of requests.
+func (l *Limit) UnmarshalText(data []byte) error {
+	text := string(data)
+	rateText, burstText, hasBurst := strings.Cut(text, ":")
+
+	rate, err := strconv.ParseFloat(rateText, 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return fmt.Errorf("invalid limit %q (want rate:burst)", text)
+	}
+
+	burst := int(math.Ceil(rate))
+	if hasBurst {
+		burst, err = strconv.Atoi(burstText)
+		if err != nil || burst < 1 {
+			return fmt.Errorf("invalid limit %q (want a burst of at least 1)", text)
+		}
+	}
+
+	*l = Limit{Rate: rate, Burst: burst}
+	return nil
+}
+
+// MarshalText formats the limit as "rate:burst".
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatFloat(l.Rate, 'g', -1, 64) + ":" + strconv.Itoa(l.Burst)), nil
+}
+
+// APIKey is a static API key, known by the SHA-256 of its value only.
+type APIKey struct {
+	Name string
+	Role auth.Role
+	Hash string
+}
+
+// UnmarshalText parses "name:role:sha256", where the hash is the
+// hex-encoded SHA-256 of the key (e.g. from sha256sum).
+func (k *APIKey) UnmarshalText(data []byte) error {
+	text := string(data)
+	parts := strings.Split(text, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return fmt.Errorf("invalid API key %q (want name:role:sha256)", text)
+	}
+
+	role, err := auth.ParseRole(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid API key %q: %w", parts[0], err)
+	}
+
+	hash := strings.ToLower(parts[2])
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("invalid API key %q: want a hex-encoded SHA-256", parts[0])
+	}
+
+	*k = APIKey{Name: parts[0], Role: role, Hash: hash}
+	return nil
+}
+
+// MarshalText formats the key as "name:role:sha256".
+func (k APIKey) MarshalText() ([]byte, error) {
+	return []byte(k.Name + ":" + string(k.Role) + ":" + k.Hash), nil
+}
+
+// Headers are HTTP headers. In their text form, "key=value,...", values
+// are URL-encoded so they can hold commas.
+type Headers map[string]string
+
+// UnmarshalText parses "key=value,...".
+func (h *Headers) UnmarshalText(data []byte) error {
+	text := string(data)
+	headers := make(Headers)
+
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(key) == "" || err != nil {
+			return fmt.Errorf("invalid header %q (want key=value)", entry)
+		}
+
+		headers[strings.TrimSpace(key)] = decoded
+	}
+
+	*h = headers
+	return nil
+}
+
+// MarshalText formats the headers as "key=value,...", sorted by key.
+func (h Headers) MarshalText() ([]byte, error) {
+	entries := make([]string, 0, len(h))
+	for _, key := range slices.Sorted(maps.Keys(h)) {
+		entries = append(entries, key+"="+url.PathEscape(h[key]))
+	}
+	return []byte(strings.Join(entries, ",")), nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML decodes data into the tree parseJSON returns: mappings
+// become map[string]any, sequences []any and scalars strings as written,
+// so the config fields give them their types. Null and empty documents
+// mean empty. Duplicate keys are errors, as the later one would silently
+// win.
+func parseYAML(data []byte) (map[string]any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 {
+		return map[string]any{}, nil
+	}
+
+	tree, err := yamlTree(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch root := tree.(type) {
+	case map[string]any:
+		return root, nil
+	case string:
+		if root == "" {
+			return map[string]any{}, nil
+		}
+	}
+	return nil, fmt.Errorf("line %d: want a mapping at the top level", doc.Content[0].Line)
+}
+
+func yamlTree(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		tree := make(map[string]any, len(node.Content)/2)
+
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: keys must be scalars", key.Line)
+			}
+			if _, found := tree[key.Value]; found {
+				return nil, fmt.Errorf("line %d: duplicate key %q", key.Line, key.Value)
+			}
+
+			child, err := yamlTree(value)
+			if err != nil {
+				return nil, err
+			}
+			tree[key.Value] = child
+		}
+		return tree, nil
+
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(node.Content))
+
+		for _, item := range node.Content {
+			child, err := yamlTree(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, child)
+		}
+		return items, nil
+
+	case yaml.AliasNode:
+		return yamlTree(node.Alias)
+
+	default:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	}
+}
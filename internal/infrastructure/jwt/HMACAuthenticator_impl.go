@@ -0,0 +1,224 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+)
+
+// MinSecretLength is the shortest HMAC secret accepted: 256 bits, the
+// size of the SHA-256 output (RFC 7518, section 3.2).
+const MinSecretLength = 32
+
+// DefaultLeeway tolerates clock skew between the issuer and the API.
+const DefaultLeeway = 30 * time.Second
+
+// header is the only JOSE header this package signs or accepts.
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims are the JWT claims this API reads.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Role      string   `json:"role"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+// Audience is the "aud" claim, a single string or an array of strings.
+type Audience []string
+
+// MarshalJSON writes a single audience as a plain string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts both forms.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// HMACAuthenticator signs and verifies HS256 JSON Web Tokens.
+type HMACAuthenticator struct {
+	secret   []byte
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// Option configures an HMACAuthenticator.
+type Option func(*HMACAuthenticator)
+
+// WithIssuer requires tokens to carry iss, and sets it on signed tokens.
+func WithIssuer(issuer string) Option {
+	return func(a *HMACAuthenticator) {
+		a.issuer = issuer
+	}
+}
+
+// WithAudience requires tokens to list aud, and sets it on signed tokens.
+func WithAudience(audience string) Option {
+	return func(a *HMACAuthenticator) {
+		a.audience = audience
+	}
+}
+
+// WithLeeway sets the clock skew tolerated on exp and nbf.
+func WithLeeway(leeway time.Duration) Option {
+	return func(a *HMACAuthenticator) {
+		a.leeway = leeway
+	}
+}
+
+// WithClock replaces time.Now, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(a *HMACAuthenticator) {
+		a.now = now
+	}
+}
+
+// NewHMACAuthenticator creates a new HMACAuthenticator.
+func NewHMACAuthenticator(secret []byte, opts ...Option) (*HMACAuthenticator, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("%w: %d bytes, want at least %d", ErrWeakSecret, len(secret), MinSecretLength)
+	}
+
+	a := &HMACAuthenticator{
+		secret: bytes.Clone(secret),
+		leeway: DefaultLeeway,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a, nil
+}
+
+// Sign issues a token for subject with role, valid for ttl.
+func (a *HMACAuthenticator) Sign(subject string, role auth.Role, ttl time.Duration) (string, error) {
+	now := a.now()
+
+	claims := Claims{
+		Subject:   subject,
+		Role:      string(role),
+		Issuer:    a.issuer,
+		ExpiresAt: now.Add(ttl).Unix(),
+		IssuedAt:  now.Unix(),
+	}
+	if a.audience != "" {
+		claims.Audience = Audience{a.audience}
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(a.sign(signingInput)), nil
+}
+
+// Authenticate implements auth.Authenticator. Credentials that are not
+// shaped like a JWT (three dot-separated segments) are unsupported.
+func (a *HMACAuthenticator) Authenticate(_ context.Context, credential string) (auth.Principal, error) {
+	parts := strings.Split(credential, ".")
+	if len(parts) != 3 {
+		return auth.Principal{}, auth.ErrUnsupportedCredential
+	}
+
+	claims, err := a.verify(parts)
+	if err != nil {
+		return auth.Principal{}, fmt.Errorf("%w: %v", auth.ErrInvalidCredential, err)
+	}
+
+	return auth.Principal{Subject: claims.Subject, Role: auth.Role(claims.Role)}, nil
+}
+
+// verify checks the signature first, then the claims.
+func (a *HMACAuthenticator) verify(parts []string) (Claims, error) {
+	var claims Claims
+
+	// Only HS256 is accepted: no "none", no algorithm confusion
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return claims, errors.New("malformed header")
+	}
+	var jose struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(rawHeader, &jose); err != nil || jose.Alg != "HS256" {
+		return claims, errors.New("unsupported algorithm")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, a.sign(parts[0]+"."+parts[1])) {
+		return claims, errors.New("bad signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, errors.New("malformed claims")
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, errors.New("malformed claims")
+	}
+
+	now := a.now()
+
+	switch {
+	case claims.ExpiresAt == 0:
+		return claims, errors.New("missing exp")
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(a.leeway)):
+		return claims, errors.New("token expired")
+	case claims.NotBefore != 0 && now.Add(a.leeway).Before(time.Unix(claims.NotBefore, 0)):
+		return claims, errors.New("token not valid yet")
+	case claims.Subject == "":
+		return claims, errors.New("missing sub")
+	case a.issuer != "" && claims.Issuer != a.issuer:
+		return claims, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	case a.audience != "" && !claims.Audience.contains(a.audience):
+		return claims, fmt.Errorf("unexpected audience %v", claims.Audience)
+	}
+
+	if _, err := auth.ParseRole(claims.Role); err != nil {
+		return claims, err
+	}
+
+	return claims, nil
+}
+
+func (a *HMACAuthenticator) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func (a Audience) contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/jwt"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func newAuthenticator(t *testing.T, now time.Time, opts ...jwt.Option) *jwt.HMACAuthenticator {
+	t.Helper()
+
+	opts = append(opts, jwt.WithClock(func() time.Time { return now }))
+
+	a, err := jwt.NewHMACAuthenticator(secret, opts...)
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	return a
+}
+
+// forge signs arbitrary header and claims JSON with secret, to build
+// tokens Sign never would.
+func forge(headerJSON, claimsJSON string) string {
+	input := base64.RawURLEncoding.EncodeToString([]byte(headerJSON)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claimsJSON))
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+/*
+	TESTS
+*/
+
+func TestHMACAuthenticator_RoundTrip(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := newAuthenticator(t, now, jwt.WithIssuer("karhub"), jwt.WithAudience("beer-api"))
+
+	token, err := a.Sign("ci", auth.RoleOperator, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, err := a.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if principal.Subject != "ci" || principal.Role != auth.RoleOperator {
+		t.Errorf("expected ci as operator, got %+v", principal)
+	}
+}
+
+func TestHMACAuthenticator_Rejects(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := newAuthenticator(t, now, jwt.WithIssuer("karhub"))
+
+	valid, _ := a.Sign("ci", auth.RoleAdmin, time.Hour)
+	parts := strings.Split(valid, ".")
+
+	other, err := jwt.NewHMACAuthenticator([]byte("another-secret-another-secret-32"), jwt.WithIssuer("karhub"))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	foreign, _ := other.Sign("ci", auth.RoleAdmin, time.Hour)
+
+	expired, _ := newAuthenticator(t, now.Add(-2*time.Hour), jwt.WithIssuer("karhub")).Sign("ci", auth.RoleAdmin, time.Hour)
+	withinLeeway, _ := newAuthenticator(t, now.Add(-time.Hour-10*time.Second), jwt.WithIssuer("karhub")).Sign("ci", auth.RoleAdmin, time.Hour)
+	wrongIssuer, _ := newAuthenticator(t, now, jwt.WithIssuer("someone-else")).Sign("ci", auth.RoleAdmin, time.Hour)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", token: valid},
+		{name: "within clock leeway", token: withinLeeway},
+		{name: "not a jwt", token: "kbm_plain-api-key", wantErr: auth.ErrUnsupportedCredential},
+		{name: "signed with another secret", token: foreign, wantErr: auth.ErrInvalidCredential},
+		{name: "tampered claims", token: parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ci","role":"admin","exp":9999999999}`)) + "." + parts[2], wantErr: auth.ErrInvalidCredential},
+		{name: "expired", token: expired, wantErr: auth.ErrInvalidCredential},
+		{name: "wrong issuer", token: wrongIssuer, wantErr: auth.ErrInvalidCredential},
+		{name: "alg none", token: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".", wantErr: auth.ErrInvalidCredential},
+		{name: "alg HS512", token: forge(`{"alg":"HS512"}`, `{"sub":"ci","role":"admin","iss":"karhub","exp":9999999999}`), wantErr: auth.ErrInvalidCredential},
+		{name: "unknown role", token: forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"root","iss":"karhub","exp":9999999999}`), wantErr: auth.ErrInvalidCredential},
+		{name: "missing exp", token: forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"admin","iss":"karhub"}`), wantErr: auth.ErrInvalidCredential},
+		{name: "not valid yet", token: forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"admin","iss":"karhub","exp":9999999999,"nbf":1700003600}`), wantErr: auth.ErrInvalidCredential},
+		{name: "audience array", token: forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"reader","iss":"karhub","aud":["x","y"],"exp":9999999999}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.Authenticate(context.Background(), tt.token)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHMACAuthenticator_Audience(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a := newAuthenticator(t, now, jwt.WithAudience("beer-api"))
+
+	token := forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"reader","aud":["dashboard","beer-api"],"exp":9999999999}`)
+	if _, err := a.Authenticate(context.Background(), token); err != nil {
+		t.Errorf("expected a listed audience to be accepted, got %v", err)
+	}
+
+	token = forge(`{"alg":"HS256"}`, `{"sub":"ci","role":"reader","aud":"dashboard","exp":9999999999}`)
+	if _, err := a.Authenticate(context.Background(), token); !errors.Is(err, auth.ErrInvalidCredential) {
+		t.Errorf("expected another audience to be rejected, got %v", err)
+	}
+}
+
+func TestNewHMACAuthenticator_WeakSecret(t *testing.T) {
+	if _, err := jwt.NewHMACAuthenticator([]byte("short")); !errors.Is(err, jwt.ErrWeakSecret) {
+		t.Fatalf("expected ErrWeakSecret, got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+package jwt
+
+import "errors"
+
+// ErrWeakSecret is returned when the HMAC secret is shorter than
+// MinSecretLength bytes.
+var ErrWeakSecret = errors.New("jwt secret too short")
@@ -0,0 +1,181 @@
+package localmusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+// ProviderName identifies the local catalog as a music provider.
+const ProviderName = "local"
+
+// CatalogProvider implements beer.MusicProvider from a local catalog file,
+// for deployments without access to a streaming service.
+type CatalogProvider struct {
+	name         string
+	playlists    []catalogPlaylist
+	trackLimit   int
+	skipExplicit bool
+}
+
+// CatalogOption customizes a CatalogProvider.
+type CatalogOption func(*CatalogProvider)
+
+// WithTrackLimit caps the tracks returned per playlist, as the Spotify
+// client does; 0, the default, returns them all.
+func WithTrackLimit(limit int) CatalogOption {
+	return func(p *CatalogProvider) {
+		p.trackLimit = limit
+	}
+}
+
+// WithoutExplicitTracks drops tracks flagged as explicit. They do not
+// count towards the track limit.
+func WithoutExplicitTracks() CatalogOption {
+	return func(p *CatalogProvider) {
+		p.skipExplicit = true
+	}
+}
+
+// catalogFile is the on-disk JSON format of a music catalog.
+type catalogFile struct {
+	// Provider optionally overrides the provider name reported
+	// in responses (defaults to "local").
+	Provider  string            `json:"provider"`
+	Playlists []catalogPlaylist `json:"playlists"`
+}
+
+type catalogPlaylist struct {
+	// Styles lists the beer style names the playlist is meant for.
+	Styles      []string       `json:"styles"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Owner       string         `json:"owner"`
+	Link        string         `json:"link"`
+	ImageURL    string         `json:"imageUrl"`
+	Tracks      []catalogTrack `json:"tracks"`
+}
+
+type catalogTrack struct {
+	Name          string   `json:"name"`
+	Artists       []string `json:"artists"`
+	Album         string   `json:"album"`
+	AlbumImageURL string   `json:"albumImageUrl"`
+	DurationMs    int64    `json:"durationMs"`
+	Explicit      bool     `json:"explicit"`
+	PreviewURL    string   `json:"previewUrl"`
+	Link          string   `json:"link"`
+}
+
+// NewCatalogProvider loads a catalog provider from a JSON file.
+func NewCatalogProvider(path string, opts ...CatalogOption) (*CatalogProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read music catalog: %w", err)
+	}
+
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse music catalog %s: %w", path, err)
+	}
+
+	name := file.Provider
+	if name == "" {
+		name = ProviderName
+	}
+
+	p := &CatalogProvider{
+		name:      name,
+		playlists: file.Playlists,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+// Name returns the provider name reported in playlists.
+func (p *CatalogProvider) Name() string {
+	return p.name
+}
+
+// FindPlaylistByStyle returns the first playlist listed for the style.
+// Playlists whose name contains the style are used as a fallback,
+// mirroring a streaming service search.
+func (p *CatalogProvider) FindPlaylistByStyle(
+	_ context.Context,
+	styleName string,
+) (beer.Playlist, error) {
+	for _, pl := range p.playlists {
+		for _, style := range pl.Styles {
+			if strings.EqualFold(style, styleName) {
+				return p.toPlaylist(pl), nil
+			}
+		}
+	}
+
+	needle := strings.ToLower(styleName)
+	for _, pl := range p.playlists {
+		if strings.Contains(strings.ToLower(pl.Name), needle) {
+			return p.toPlaylist(pl), nil
+		}
+	}
+
+	return beer.Playlist{}, ErrPlaylistNotFound
+}
+
+func (p *CatalogProvider) toPlaylist(pl catalogPlaylist) beer.Playlist {
+	playlist := beer.Playlist{
+		Name:        pl.Name,
+		Description: pl.Description,
+		Owner:       pl.Owner,
+		Link:        pl.Link,
+		Images:      toImages(pl.ImageURL),
+		Provider:    p.name,
+		TotalTracks: len(pl.Tracks),
+		Tracks:      make([]beer.Track, 0, len(pl.Tracks)),
+	}
+
+	for _, t := range pl.Tracks {
+		if p.skipExplicit && t.Explicit {
+			continue
+		}
+
+		if p.trackLimit > 0 && len(playlist.Tracks) == p.trackLimit {
+			break
+		}
+
+		track := beer.Track{
+			Name:        t.Name,
+			Artists:     append([]string{}, t.Artists...),
+			Album:       t.Album,
+			AlbumImages: toImages(t.AlbumImageURL),
+			Duration:    time.Duration(t.DurationMs) * time.Millisecond,
+			Explicit:    t.Explicit,
+			PreviewURL:  t.PreviewURL,
+			Link:        t.Link,
+		}
+
+		if len(t.Artists) > 0 {
+			track.Artist = t.Artists[0]
+		}
+
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	return playlist
+}
+
+func toImages(url string) []beer.Image {
+	if url == "" {
+		return []beer.Image{}
+	}
+	return []beer.Image{{URL: url}}
+}
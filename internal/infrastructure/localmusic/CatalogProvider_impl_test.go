@@ -0,0 +1,244 @@
+package localmusic_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/infrastructure/localmusic"
+)
+
+const catalogJSON = `{
+  "playlists": [
+    {
+      "styles": ["IPA"],
+      "name": "Hop Heads",
+      "owner": "Karhub",
+      "imageUrl": "https://img.test/hop.jpg",
+      "tracks": [
+        {"name": "Citra Skies", "artists": ["The Hop Garden", "Mosaic"], "durationMs": 215000}
+      ]
+    },
+    {
+      "name": "Dunkel Nights",
+      "tracks": []
+    }
+  ]
+}`
+
+func writeCatalog(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	return path
+}
+
+func TestNewCatalogProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		missing  bool
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "valid catalog",
+			content:  catalogJSON,
+			wantName: localmusic.ProviderName,
+		},
+		{
+			name:     "custom provider name",
+			content:  `{"provider": "jukebox", "playlists": []}`,
+			wantName: "jukebox",
+		},
+		{
+			name:    "invalid json",
+			content: `{`,
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			missing: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "missing.json")
+			if !tt.missing {
+				path = writeCatalog(t, tt.content)
+			}
+
+			provider, err := localmusic.NewCatalogProvider(path)
+
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantErr && provider.Name() != tt.wantName {
+				t.Errorf("expected name %s, got %s", tt.wantName, provider.Name())
+			}
+		})
+	}
+}
+
+func TestCatalogProvider_FindPlaylistByStyle(t *testing.T) {
+	provider, err := localmusic.NewCatalogProvider(writeCatalog(t, catalogJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		style        string
+		wantPlaylist string
+		wantErr      bool
+	}{
+		{
+			name:         "style listed in catalog",
+			style:        "ipa",
+			wantPlaylist: "Hop Heads",
+		},
+		{
+			name:         "style matched by playlist name",
+			style:        "Dunkel",
+			wantPlaylist: "Dunkel Nights",
+		},
+		{
+			name:    "style not in catalog",
+			style:   "Weissbier",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := provider.FindPlaylistByStyle(context.Background(), tt.style)
+
+			if tt.wantErr {
+				if !errors.Is(err, localmusic.ErrPlaylistNotFound) {
+					t.Fatalf("expected ErrPlaylistNotFound, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if playlist.Name != tt.wantPlaylist {
+				t.Errorf("expected playlist %s, got %s", tt.wantPlaylist, playlist.Name)
+			}
+
+			if playlist.Provider != localmusic.ProviderName {
+				t.Errorf("expected provider %s, got %s", localmusic.ProviderName, playlist.Provider)
+			}
+		})
+	}
+}
+
+func TestCatalogProvider_MapsTracks(t *testing.T) {
+	provider, _ := localmusic.NewCatalogProvider(writeCatalog(t, catalogJSON))
+
+	playlist, err := provider.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(playlist.Tracks) != 1 {
+		t.Fatalf("expected 1 track, got %d", len(playlist.Tracks))
+	}
+
+	track := playlist.Tracks[0]
+
+	if track.Artist != "The Hop Garden" || len(track.Artists) != 2 {
+		t.Errorf("unexpected artists: %s / %v", track.Artist, track.Artists)
+	}
+
+	if track.Duration != 215*time.Second {
+		t.Errorf("expected duration 3m35s, got %v", track.Duration)
+	}
+
+	if len(playlist.Images) != 1 || playlist.Images[0].URL != "https://img.test/hop.jpg" {
+		t.Errorf("unexpected images: %+v", playlist.Images)
+	}
+}
+
+func TestCatalogProvider_TrackSettings(t *testing.T) {
+	path := writeCatalog(t, `{
+  "playlists": [
+    {
+      "styles": ["IPA"],
+      "name": "Hop Heads",
+      "tracks": [
+        {"name": "One", "explicit": true},
+        {"name": "Two"},
+        {"name": "Three", "explicit": true},
+        {"name": "Four"},
+        {"name": "Five"}
+      ]
+    }
+  ]
+}`)
+
+	tests := []struct {
+		name       string
+		opts       []localmusic.CatalogOption
+		wantTracks []string
+	}{
+		{
+			name:       "every track by default",
+			wantTracks: []string{"One", "Two", "Three", "Four", "Five"},
+		},
+		{
+			name:       "track limit",
+			opts:       []localmusic.CatalogOption{localmusic.WithTrackLimit(2)},
+			wantTracks: []string{"One", "Two"},
+		},
+		{
+			name:       "without explicit tracks",
+			opts:       []localmusic.CatalogOption{localmusic.WithoutExplicitTracks()},
+			wantTracks: []string{"Two", "Four", "Five"},
+		},
+		{
+			name:       "explicit tracks do not count towards the limit",
+			opts:       []localmusic.CatalogOption{localmusic.WithTrackLimit(2), localmusic.WithoutExplicitTracks()},
+			wantTracks: []string{"Two", "Four"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := localmusic.NewCatalogProvider(path, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			playlist, err := provider.FindPlaylistByStyle(context.Background(), "IPA")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, track := range playlist.Tracks {
+				got = append(got, track.Name)
+			}
+
+			if !slices.Equal(got, tt.wantTracks) {
+				t.Errorf("expected tracks %v, got %v", tt.wantTracks, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package localmusic
+
+import (
+	"fmt"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+// ErrPlaylistNotFound is returned when the catalog has no playlist for a beer style.
+// It wraps beer.ErrMusicNotFound.
+var ErrPlaylistNotFound = fmt.Errorf("local catalog: %w", beer.ErrMusicNotFound)
@@ -0,0 +1,366 @@
+package otlp
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+// Default batching settings.
+const (
+	DefaultQueueSize     = 2048
+	DefaultBatchSize     = 512
+	DefaultBatchInterval = 5 * time.Second
+)
+
+// Tracer implements tracing.Tracer. Ended spans are queued and exported
+// in batches by a background goroutine; when the queue is full, spans
+// are dropped rather than slowing requests down.
+type Tracer struct {
+	exporter      Exporter
+	threshold     uint64
+	alwaysSample  bool
+	queueSize     int
+	batchSize     int
+	batchInterval time.Duration
+
+	queue   chan SpanData
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+
+	stopOnce sync.Once
+	closed   atomic.Bool
+	dropped  atomic.Uint64
+}
+
+// TracerOption customizes a Tracer.
+type TracerOption func(*Tracer)
+
+// WithSampleRatio records the given share of new traces, between 0 and
+// 1 (the default). Spans continuing a trace follow the sampling decision
+// of their parent instead.
+func WithSampleRatio(ratio float64) TracerOption {
+	return func(t *Tracer) {
+		switch {
+		case ratio >= 1:
+			t.alwaysSample = true
+		case ratio <= 0:
+			t.alwaysSample = false
+			t.threshold = 0
+		default:
+			t.alwaysSample = false
+			t.threshold = uint64(ratio * math.MaxUint64)
+		}
+	}
+}
+
+// WithBatching sets how many ended spans may wait for export, how many
+// are sent per export, and how long a partial batch may wait.
+func WithBatching(queueSize, batchSize int, interval time.Duration) TracerOption {
+	return func(t *Tracer) {
+		if queueSize > 0 {
+			t.queueSize = queueSize
+		}
+		if batchSize > 0 {
+			t.batchSize = batchSize
+		}
+		if interval > 0 {
+			t.batchInterval = interval
+		}
+	}
+}
+
+// NewTracer creates a Tracer exporting to exporter, and starts its
+// export goroutine; call Shutdown to stop it.
+func NewTracer(exporter Exporter, opts ...TracerOption) *Tracer {
+	t := &Tracer{
+		exporter:      exporter,
+		alwaysSample:  true,
+		queueSize:     DefaultQueueSize,
+		batchSize:     DefaultBatchSize,
+		batchInterval: DefaultBatchInterval,
+		flushes:       make(chan chan struct{}),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.queue = make(chan SpanData, t.queueSize)
+	go t.run()
+
+	return t
+}
+
+// Start implements tracing.Tracer.
+func (t *Tracer) Start(ctx context.Context, name string, kind tracing.SpanKind, attrs ...tracing.Attribute) tracing.Span {
+	parent := tracing.SpanContextFromContext(ctx)
+
+	sc := tracing.SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = t.sample(sc.TraceID)
+	}
+
+	s := &span{
+		tracer:    t,
+		recording: sc.Sampled,
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			SpanContext:  sc,
+			ParentSpanID: parent.SpanID,
+			Start:        time.Now(),
+		},
+	}
+	if s.recording {
+		s.data.Attributes = slices.Clone(attrs)
+	}
+
+	return s
+}
+
+// Dropped returns how many ended spans were dropped on a full queue.
+func (t *Tracer) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// ForceFlush exports every span ended so far, and waits for the export
+// to finish or ctx to be done.
+func (t *Tracer) ForceFlush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	select {
+	case t.flushes <- flushed:
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports the queued spans and stops the export goroutine.
+// Spans ending afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.stopOnce.Do(func() {
+		t.closed.Store(true)
+		close(t.stop)
+	})
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sample decides whether a new trace is recorded. Deciding on the
+// random trace ID keeps the decision consistent with other services
+// sampling at the same ratio.
+func (t *Tracer) sample(id tracing.TraceID) bool {
+	if t.alwaysSample {
+		return true
+	}
+	return binary.BigEndian.Uint64(id[8:]) < t.threshold
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	if t.closed.Load() {
+		t.dropped.Add(1)
+		return
+	}
+
+	select {
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// run batches queued spans until Shutdown.
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultExportTimeout)
+		defer cancel()
+
+		if err := t.exporter.Export(ctx, batch); err != nil {
+			slog.Default().Warn("exporting spans failed", "spans", len(batch), "error", err)
+		}
+		batch = make([]SpanData, 0, t.batchSize)
+	}
+
+	// drain moves every queued span into batches
+	drain := func() {
+		for {
+			select {
+			case data := <-t.queue:
+				batch = append(batch, data)
+				if len(batch) == t.batchSize {
+					export()
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) == t.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case flushed := <-t.flushes:
+			drain()
+			export()
+			close(flushed)
+		case <-t.stop:
+			drain()
+			export()
+			return
+		}
+	}
+}
+
+/*
+	---------- Spans ----------
+*/
+
+type span struct {
+	tracer *Tracer
+
+	// recording is false for spans of unsampled traces, which only
+	// carry their span context
+	recording bool
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *span) SpanContext() tracing.SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *span) SetName(name string) {
+	if !s.recording {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Name = name
+	}
+}
+
+func (s *span) SetAttributes(attrs ...tracing.Attribute) {
+	if !s.recording {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Attributes = append(s.data.Attributes, attrs...)
+	}
+}
+
+func (s *span) RecordError(err error) {
+	if !s.recording || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.data.Err = err.Error()
+	s.data.Events = append(s.data.Events, Event{
+		Name: "exception",
+		Time: time.Now(),
+		Attributes: []tracing.Attribute{
+			tracing.String("exception.message", err.Error()),
+		},
+	})
+}
+
+func (s *span) End() {
+	if !s.recording {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.enqueue(data)
+}
+
+/*
+	---------- IDs ----------
+*/
+
+func newTraceID() tracing.TraceID {
+	var id tracing.TraceID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func newSpanID() tracing.SpanID {
+	var id tracing.SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
@@ -0,0 +1,236 @@
+package otlp_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+	"karhub-beer-machine/internal/infrastructure/otlp"
+)
+
+func newTracer(t *testing.T, opts ...otlp.TracerOption) (*otlp.Tracer, *otlp.InMemoryExporter) {
+	t.Helper()
+
+	exporter := otlp.NewInMemoryExporter()
+	tracer := otlp.NewTracer(exporter, opts...)
+	t.Cleanup(func() { _ = tracer.Shutdown(context.Background()) })
+
+	return tracer, exporter
+}
+
+func flush(t *testing.T, tracer *otlp.Tracer) {
+	t.Helper()
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+}
+
+/*
+	TESTS
+*/
+
+func TestTracer_ParentChild(t *testing.T) {
+	tracer, exporter := newTracer(t)
+
+	root := tracer.Start(context.Background(), "root", tracing.SpanKindServer, tracing.String("a", "b"))
+	ctx := tracing.ContextWithSpan(context.Background(), root)
+
+	child := tracer.Start(ctx, "child", tracing.SpanKindInternal)
+	child.SetAttributes(tracing.Int("n", 1))
+	child.RecordError(errors.New("boom"))
+	child.End()
+
+	root.SetName("renamed")
+	root.End()
+	root.End()
+
+	flush(t, tracer)
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	gotChild, gotRoot := spans[0], spans[1]
+
+	if gotRoot.Name != "renamed" || gotRoot.Kind != tracing.SpanKindServer || gotRoot.ParentSpanID.IsValid() {
+		t.Errorf("unexpected root span %+v", gotRoot)
+	}
+	if v, _ := gotRoot.Attribute("a"); v != "b" {
+		t.Errorf("expected attribute a=b, got %v", v)
+	}
+	if !gotRoot.SpanContext.Sampled || gotRoot.End.Before(gotRoot.Start) {
+		t.Errorf("unexpected root span %+v", gotRoot)
+	}
+
+	if gotChild.SpanContext.TraceID != gotRoot.SpanContext.TraceID {
+		t.Errorf("expected child in the root trace")
+	}
+	if gotChild.ParentSpanID != gotRoot.SpanContext.SpanID {
+		t.Errorf("expected child of the root span")
+	}
+	if !gotChild.Failed() || gotChild.Err != "boom" || len(gotChild.Events) != 1 {
+		t.Errorf("expected a recorded error, got %+v", gotChild)
+	}
+	if v, _ := gotChild.Attribute("n"); v != 1 {
+		t.Errorf("expected attribute n=1, got %v", v)
+	}
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	tests := []struct {
+		name       string
+		ratio      float64
+		parent     string
+		wantExport bool
+	}{
+		{
+			name:       "new trace, ratio 1",
+			ratio:      1,
+			wantExport: true,
+		},
+		{
+			name:  "new trace, ratio 0",
+			ratio: 0,
+		},
+		{
+			name:       "sampled parent wins over ratio 0",
+			ratio:      0,
+			parent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantExport: true,
+		},
+		{
+			name:   "unsampled parent wins over ratio 1",
+			ratio:  1,
+			parent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, exporter := newTracer(t, otlp.WithSampleRatio(tt.ratio))
+
+			ctx := context.Background()
+			if sc, ok := tracing.ParseTraceParent(tt.parent); ok {
+				ctx = tracing.ContextWithRemoteSpanContext(ctx, sc)
+			}
+
+			span := tracer.Start(ctx, "op", tracing.SpanKindServer)
+			span.End()
+			flush(t, tracer)
+
+			if got := len(exporter.Spans()) == 1; got != tt.wantExport {
+				t.Fatalf("expected exported %v, got %v", tt.wantExport, got)
+			}
+
+			// Unsampled spans still carry a span context to propagate
+			sc := span.SpanContext()
+			if !sc.IsValid() || sc.Sampled != tt.wantExport {
+				t.Errorf("unexpected span context %+v", sc)
+			}
+		})
+	}
+}
+
+func TestTracer_SampleRatio(t *testing.T) {
+	tracer, _ := newTracer(t, otlp.WithSampleRatio(0.25))
+
+	sampled := 0
+	for range 10000 {
+		if tracer.Start(context.Background(), "op", tracing.SpanKindInternal).SpanContext().Sampled {
+			sampled++
+		}
+	}
+
+	if sampled < 2000 || sampled > 3000 {
+		t.Fatalf("expected about 2500 sampled traces, got %d", sampled)
+	}
+}
+
+func TestTracer_Batching(t *testing.T) {
+	exporter := &batchRecorder{}
+	tracer := otlp.NewTracer(exporter, otlp.WithBatching(100, 4, time.Hour))
+
+	for range 10 {
+		tracer.Start(context.Background(), "op", tracing.SpanKindInternal).End()
+	}
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down: %v", err)
+	}
+
+	if got := exporter.sizes(); len(got) != 3 || got[0] != 4 || got[1] != 4 || got[2] != 2 {
+		t.Fatalf("expected batches of 4, 4 and 2, got %v", got)
+	}
+
+	// Spans ending after shutdown are dropped
+	tracer.Start(context.Background(), "late", tracing.SpanKindInternal).End()
+	if tracer.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped span, got %d", tracer.Dropped())
+	}
+}
+
+func TestTracer_Concurrent(t *testing.T) {
+	tracer, exporter := newTracer(t)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				span := tracer.Start(context.Background(), "op", tracing.SpanKindInternal)
+				span.SetAttributes(tracing.Bool("ok", true))
+				span.End()
+			}
+		}()
+	}
+	wg.Wait()
+
+	flush(t, tracer)
+
+	if got := len(exporter.Spans()) + int(tracer.Dropped()); got != 800 {
+		t.Fatalf("expected 800 spans exported or dropped, got %d", got)
+	}
+}
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (r *batchRecorder) Export(_ context.Context, spans []otlp.SpanData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, len(spans))
+	return nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.batches
+}
+
+/*
+	BENCHMARKS
+*/
+
+func BenchmarkTracer_StartEnd(b *testing.B) {
+	tracer := otlp.NewTracer(otlp.NewInMemoryExporter(), otlp.WithBatching(1<<16, 0, 0))
+	defer tracer.Shutdown(context.Background())
+
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		span := tracer.Start(ctx, "op", tracing.SpanKindInternal, tracing.String("k", "v"))
+		span.End()
+	}
+}
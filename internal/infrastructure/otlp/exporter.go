@@ -0,0 +1,53 @@
+// Package otlp records spans for the tracing port and exports them in
+// batches, over OTLP/HTTP with JSON encoding or to memory for tests,
+// with the standard library only.
+package otlp
+
+import (
+	"context"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+// SpanData is an ended span, as handed to an Exporter.
+type SpanData struct {
+	Name         string
+	Kind         tracing.SpanKind
+	SpanContext  tracing.SpanContext
+	ParentSpanID tracing.SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   []tracing.Attribute
+	Events       []Event
+
+	// Err is the message of the error recorded last, if any.
+	Err string
+}
+
+// Failed reports whether an error was recorded on the span.
+func (s SpanData) Failed() bool {
+	return s.Err != ""
+}
+
+// Attribute returns the value of the attribute named key.
+func (s SpanData) Attribute(key string) (any, bool) {
+	for i := len(s.Attributes) - 1; i >= 0; i-- {
+		if s.Attributes[i].Key == key {
+			return s.Attributes[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Event is something that happened at a point in time during a span.
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []tracing.Attribute
+}
+
+// Exporter sends batches of ended spans to a backend.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
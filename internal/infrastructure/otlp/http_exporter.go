@@ -0,0 +1,275 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+// DefaultExportTimeout bounds a single export request.
+const DefaultExportTimeout = 10 * time.Second
+
+// scopeName identifies the instrumentation in exported data.
+const scopeName = "karhub-beer-machine/internal/application/tracing"
+
+// HTTPExporter posts spans to an OTLP/HTTP collector with JSON encoding,
+// e.g. http://localhost:4318/v1/traces.
+type HTTPExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+}
+
+// HTTPExporterOption customizes an HTTPExporter.
+type HTTPExporterOption func(*HTTPExporter)
+
+// WithHeaders adds headers to every export request, e.g. an API key
+// required by the collector.
+func WithHeaders(headers map[string]string) HTTPExporterOption {
+	return func(e *HTTPExporter) {
+		for k, v := range headers {
+			e.headers[k] = v
+		}
+	}
+}
+
+// WithServiceName sets the service.name resource attribute.
+func WithServiceName(name string) HTTPExporterOption {
+	return func(e *HTTPExporter) {
+		e.serviceName = name
+	}
+}
+
+// WithExportTimeout bounds each export request (DefaultExportTimeout
+// otherwise).
+func WithExportTimeout(timeout time.Duration) HTTPExporterOption {
+	return func(e *HTTPExporter) {
+		e.client.Timeout = timeout
+	}
+}
+
+// NewHTTPExporter creates an exporter posting to endpoint, the full URL
+// of the traces resource.
+func NewHTTPExporter(endpoint string, opts ...HTTPExporterOption) *HTTPExporter {
+	e := &HTTPExporter{
+		endpoint:    endpoint,
+		headers:     make(map[string]string),
+		serviceName: "unknown_service",
+		client:      &http.Client{Timeout: DefaultExportTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Export implements Exporter.
+func (e *HTTPExporter) Export(ctx context.Context, spans []SpanData) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("otlp: collector answered %s", resp.Status)
+	}
+	return nil
+}
+
+/*
+	---------- OTLP/JSON ----------
+*/
+
+// The types below mirror the JSON mapping of the OTLP trace protobuf
+// messages: IDs are hex, 64-bit integers are strings.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanJSON struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []eventJSON `json:"events,omitempty"`
+	Status            status      `json:"status"`
+}
+
+type eventJSON struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+// Status codes of OTLP spans.
+const (
+	statusUnset = 0
+	statusError = 2
+)
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *HTTPExporter) request(spans []SpanData) exportRequest {
+	out := make([]spanJSON, len(spans))
+	for i, s := range spans {
+		out[i] = toSpanJSON(s)
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: toKeyValues([]tracing.Attribute{tracing.String("service.name", e.serviceName)}),
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+func toSpanJSON(s SpanData) spanJSON {
+	span := spanJSON{
+		TraceID:           s.SpanContext.TraceID.String(),
+		SpanID:            s.SpanContext.SpanID.String(),
+		Name:              s.Name,
+		Kind:              otlpKind(s.Kind),
+		StartTimeUnixNano: unixNano(s.Start),
+		EndTimeUnixNano:   unixNano(s.End),
+		Attributes:        toKeyValues(s.Attributes),
+		Status:            status{Code: statusUnset},
+	}
+
+	if s.ParentSpanID.IsValid() {
+		span.ParentSpanID = s.ParentSpanID.String()
+	}
+	if s.Failed() {
+		span.Status = status{Code: statusError, Message: s.Err}
+	}
+
+	for _, ev := range s.Events {
+		span.Events = append(span.Events, eventJSON{
+			TimeUnixNano: unixNano(ev.Time),
+			Name:         ev.Name,
+			Attributes:   toKeyValues(ev.Attributes),
+		})
+	}
+
+	return span
+}
+
+// otlpKind maps a span kind to its OTLP value (0 is unspecified).
+func otlpKind(kind tracing.SpanKind) int {
+	switch kind {
+	case tracing.SpanKindServer:
+		return 2
+	case tracing.SpanKindClient:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func toKeyValues(attrs []tracing.Attribute) []keyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	kvs := make([]keyValue, len(attrs))
+	for i, a := range attrs {
+		kvs[i] = keyValue{Key: a.Key, Value: toAnyValue(a.Value)}
+	}
+	return kvs
+}
+
+func toAnyValue(v any) anyValue {
+	switch v := v.(type) {
+	case string:
+		return anyValue{StringValue: &v}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return anyValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return anyValue{IntValue: &s}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		return anyValue{StringValue: &s}
+	}
+}
@@ -0,0 +1,178 @@
+package otlp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+	"karhub-beer-machine/internal/infrastructure/otlp"
+)
+
+/*
+	TESTS
+*/
+
+func TestHTTPExporter_Export(t *testing.T) {
+	var (
+		gotBody    []byte
+		gotHeaders http.Header
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer collector.Close()
+
+	exporter := otlp.NewHTTPExporter(collector.URL+"/v1/traces",
+		otlp.WithServiceName("beer"),
+		otlp.WithHeaders(map[string]string{"Api-Key": "secret"}),
+	)
+
+	sc, _ := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	start := time.Unix(1700000000, 5)
+
+	err := exporter.Export(context.Background(), []otlp.SpanData{{
+		Name:         "GET /beer-styles",
+		Kind:         tracing.SpanKindServer,
+		SpanContext:  sc,
+		ParentSpanID: tracing.SpanID{1},
+		Start:        start,
+		End:          start.Add(time.Millisecond),
+		Attributes: []tracing.Attribute{
+			tracing.String("http.route", "/beer-styles"),
+			tracing.Int("http.response.status_code", 500),
+			tracing.Float64("beer.temperature", -2.5),
+			tracing.Bool("playlist.found", false),
+		},
+		Events: []otlp.Event{{
+			Name:       "exception",
+			Time:       start,
+			Attributes: []tracing.Attribute{tracing.String("exception.message", "boom")},
+		}},
+		Err: "boom",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ct := gotHeaders.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON, got %q", ct)
+	}
+	if key := gotHeaders.Get("Api-Key"); key != "secret" {
+		t.Errorf("expected the configured header, got %q", key)
+	}
+
+	want := `{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"beer"}}]},` +
+		`"scopeSpans":[{"scope":{"name":"karhub-beer-machine/internal/application/tracing"},"spans":[{` +
+		`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","parentSpanId":"0100000000000000",` +
+		`"name":"GET /beer-styles","kind":2,"startTimeUnixNano":"1700000000000000005","endTimeUnixNano":"1700000000001000005",` +
+		`"attributes":[{"key":"http.route","value":{"stringValue":"/beer-styles"}},` +
+		`{"key":"http.response.status_code","value":{"intValue":"500"}},` +
+		`{"key":"beer.temperature","value":{"doubleValue":-2.5}},` +
+		`{"key":"playlist.found","value":{"boolValue":false}}],` +
+		`"events":[{"timeUnixNano":"1700000000000000005","name":"exception","attributes":[{"key":"exception.message","value":{"stringValue":"boom"}}]}],` +
+		`"status":{"code":2,"message":"boom"}}]}]}]}`
+
+	if string(gotBody) != want {
+		t.Fatalf("unexpected body:\n%s\nwant:\n%s", gotBody, want)
+	}
+
+	if !json.Valid(gotBody) {
+		t.Fatalf("expected valid JSON")
+	}
+}
+
+func TestHTTPExporter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		timeout time.Duration
+	}{
+		{
+			name: "collector rejects the batch",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+		},
+		{
+			name: "collector too slow",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				// The server notices the client going away once the
+				// body is read
+				_, _ = io.ReadAll(r.Body)
+				<-r.Context().Done()
+			},
+			timeout: 20 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := httptest.NewServer(tt.handler)
+			defer collector.Close()
+
+			var opts []otlp.HTTPExporterOption
+			if tt.timeout > 0 {
+				opts = append(opts, otlp.WithExportTimeout(tt.timeout))
+			}
+
+			exporter := otlp.NewHTTPExporter(collector.URL, opts...)
+			err := exporter.Export(context.Background(), []otlp.SpanData{{Name: "op"}})
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestHTTPExporter_WithTracer(t *testing.T) {
+	received := make(chan []byte, 1)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer collector.Close()
+
+	tracer := otlp.NewTracer(otlp.NewHTTPExporter(collector.URL))
+	tracer.Start(context.Background(), "op", tracing.SpanKindInternal).End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down: %v", err)
+	}
+
+	select {
+	case body := <-received:
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						Name string `json:"name"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if name := req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name; name != "op" {
+			t.Fatalf("expected span op, got %q", name)
+		}
+	default:
+		t.Fatalf("expected the span to be exported on shutdown")
+	}
+
+	if err := tracer.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("unexpected error after shutdown: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+)
+
+// APIKeyRepositoryImpl is an implementation of auth.APIKeyRepository
+// persisted to a JSON file.
+//
+// Keys are served from memory; every write rewrites the whole file
+// atomically (temporary file, fsync, rename) before it is applied, so the
+// file never holds a partial write and memory never gets ahead of it.
+// Keys are few and rarely change, and last-used times are only written
+// once per auth.LastUsedResolution, so rewriting is cheap enough.
+type APIKeyRepositoryImpl struct {
+	mu   sync.Mutex
+	path string
+	keys *memory.APIKeyRepositoryImpl
+}
+
+// apiKeyRecord is the file format of an API key.
+type apiKeyRecord struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Owner      string    `json:"owner,omitempty"`
+	Role       string    `json:"role"`
+	Hash       string    `json:"sha256"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt,omitzero"`
+	LastUsedAt time.Time `json:"lastUsedAt,omitzero"`
+	RevokedAt  time.Time `json:"revokedAt,omitzero"`
+}
+
+// NewAPIKeyRepository opens the key file at path, creating it on the
+// first write if it does not exist.
+func NewAPIKeyRepository(path string) (*APIKeyRepositoryImpl, error) {
+	r := &APIKeyRepositoryImpl{
+		path: path,
+		keys: memory.NewAPIKeyRepository(),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []apiKeyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid api key file %s: %w", path, err)
+	}
+
+	for _, record := range records {
+		_ = r.keys.Save(fromRecord(record))
+	}
+
+	return r, nil
+}
+
+// Save creates or replaces an API key by ID.
+func (r *APIKeyRepositoryImpl) Save(key auth.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.write(key); err != nil {
+		return err
+	}
+	return r.keys.Save(key)
+}
+
+// FindByID retrieves a key by its identifier.
+func (r *APIKeyRepositoryImpl) FindByID(id string) (auth.APIKey, error) {
+	return r.keys.FindByID(id)
+}
+
+// FindByHash retrieves the key whose secret hashes to hash.
+func (r *APIKeyRepositoryImpl) FindByHash(hash string) (auth.APIKey, error) {
+	return r.keys.FindByHash(hash)
+}
+
+// FindAll retrieves every key, oldest first.
+func (r *APIKeyRepositoryImpl) FindAll() ([]auth.APIKey, error) {
+	return r.keys.FindAll()
+}
+
+// MarkUsed records that a key was used at the given time.
+func (r *APIKeyRepositoryImpl) MarkUsed(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, err := r.keys.FindByID(id)
+	if err != nil {
+		return err
+	}
+	key.LastUsedAt = at
+
+	if err := r.write(key); err != nil {
+		return err
+	}
+	return r.keys.Save(key)
+}
+
+// write persists the current keys with changed added or replaced.
+func (r *APIKeyRepositoryImpl) write(changed auth.APIKey) error {
+	keys, _ := r.keys.FindAll()
+
+	records := make([]apiKeyRecord, 0, len(keys)+1)
+	replaced := false
+	for _, key := range keys {
+		if key.ID == changed.ID {
+			key, replaced = changed, true
+		}
+		records = append(records, toRecord(key))
+	}
+	if !replaced {
+		records = append(records, toRecord(changed))
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// Hashes are not secrets, but nobody else needs to read them
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.path)
+}
+
+func toRecord(key auth.APIKey) apiKeyRecord {
+	return apiKeyRecord{
+		ID:         key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		Role:       string(key.Role),
+		Hash:       key.Hash,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func fromRecord(record apiKeyRecord) auth.APIKey {
+	return auth.APIKey{
+		ID:         record.ID,
+		Name:       record.Name,
+		Owner:      record.Owner,
+		Role:       auth.Role(record.Role),
+		Hash:       record.Hash,
+		CreatedAt:  record.CreatedAt,
+		ExpiresAt:  record.ExpiresAt,
+		LastUsedAt: record.LastUsedAt,
+		RevokedAt:  record.RevokedAt,
+	}
+}
@@ -0,0 +1,128 @@
+package jsonfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/persistence/jsonfile"
+)
+
+func openRepository(t *testing.T, path string) *jsonfile.APIKeyRepositoryImpl {
+	t.Helper()
+
+	repo, err := jsonfile.NewAPIKeyRepository(path)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	return repo
+}
+
+/*
+	TESTS
+*/
+
+func TestAPIKeyRepository_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+
+	created, err := auth.NewCreateAPIKeyUseCase(openRepository(t, path)).Execute(auth.CreateAPIKeyInput{
+		Name: "ci", Owner: "platform", Role: auth.RoleAdmin, TTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := openRepository(t, path)
+	usedAt := time.Now().UTC().Truncate(time.Second)
+	if err := first.MarkUsed(created.Key.ID, usedAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened := openRepository(t, path)
+
+	got, err := reopened.FindByHash(auth.HashAPIKey(created.Secret))
+	if err != nil {
+		t.Fatalf("expected the key to survive a reopen, got %v", err)
+	}
+
+	if got.Name != "ci" || got.Owner != "platform" || got.Role != auth.RoleAdmin {
+		t.Errorf("expected the key metadata to be kept, got %+v", got)
+	}
+	if !got.CreatedAt.Equal(created.Key.CreatedAt) || !got.ExpiresAt.Equal(created.Key.ExpiresAt) {
+		t.Errorf("expected timestamps to be kept, got %+v", got)
+	}
+	if !got.LastUsedAt.Equal(usedAt) {
+		t.Errorf("expected last use %v, got %v", usedAt, got.LastUsedAt)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if strings.Contains(string(data), created.Secret) {
+		t.Errorf("expected the secret not to be written")
+	}
+
+	info, _ := os.Stat(path)
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected mode 0600, got %o", perm)
+	}
+}
+
+func TestAPIKeyRepository_RotationReplacesHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	repo := openRepository(t, path)
+
+	created, _ := auth.NewCreateAPIKeyUseCase(repo).Execute(auth.CreateAPIKeyInput{Name: "ci", Role: auth.RoleAdmin})
+	rotated, _ := auth.NewRotateAPIKeyUseCase(repo).Execute(created.Key.ID)
+
+	reopened := openRepository(t, path)
+
+	if _, err := reopened.FindByHash(auth.HashAPIKey(created.Secret)); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Errorf("expected the old secret to be gone, got %v", err)
+	}
+	if _, err := reopened.FindByHash(auth.HashAPIKey(rotated.Secret)); err != nil {
+		t.Errorf("expected the new secret to be found, got %v", err)
+	}
+
+	if keys, _ := reopened.FindAll(); len(keys) != 1 {
+		t.Errorf("expected a single key, got %+v", keys)
+	}
+}
+
+func TestAPIKeyRepository_FailedWriteChangesNothing(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	repo := openRepository(t, filepath.Join(dir, "api-keys.json"))
+
+	// The temporary file cannot be created anymore
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("failed to remove dir: %v", err)
+	}
+
+	if err := repo.Save(auth.APIKey{ID: "1", Name: "ci", Role: auth.RoleAdmin, Hash: "h"}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if _, err := repo.FindByID("1"); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Errorf("expected a failed write not to be applied, got %v", err)
+	}
+}
+
+func TestNewAPIKeyRepository_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := jsonfile.NewAPIKeyRepository(path); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
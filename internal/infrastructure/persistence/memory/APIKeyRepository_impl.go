@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+)
+
+// APIKeyRepositoryImpl is an in-memory implementation of
+// auth.APIKeyRepository.
+//
+// Key   -> string (APIKey.ID)
+// Value -> auth.APIKey
+//
+// A second map indexes the IDs by hash.
+type APIKeyRepositoryImpl struct {
+	mu     sync.RWMutex
+	keys   map[string]auth.APIKey
+	byHash map[string]string
+}
+
+// NewAPIKeyRepository creates a new in-memory APIKeyRepository.
+func NewAPIKeyRepository() *APIKeyRepositoryImpl {
+	return &APIKeyRepositoryImpl{
+		keys:   make(map[string]auth.APIKey),
+		byHash: make(map[string]string),
+	}
+}
+
+// Save creates or replaces an API key by ID. Replacing a key drops its
+// previous hash.
+func (r *APIKeyRepositoryImpl) Save(key auth.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, found := r.keys[key.ID]; found {
+		delete(r.byHash, previous.Hash)
+	}
+
+	r.keys[key.ID] = key
+	r.byHash[key.Hash] = key.ID
+	return nil
+}
+
+// FindByID retrieves a key by its identifier.
+func (r *APIKeyRepositoryImpl) FindByID(id string) (auth.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, found := r.keys[id]
+	if !found {
+		return auth.APIKey{}, auth.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+// FindByHash retrieves the key whose secret hashes to hash.
+func (r *APIKeyRepositoryImpl) FindByHash(hash string) (auth.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, found := r.byHash[hash]
+	if !found {
+		return auth.APIKey{}, auth.ErrAPIKeyNotFound
+	}
+
+	return r.keys[id], nil
+}
+
+// FindAll retrieves every key, oldest first.
+func (r *APIKeyRepositoryImpl) FindAll() ([]auth.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]auth.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// MarkUsed records that a key was used at the given time.
+func (r *APIKeyRepositoryImpl) MarkUsed(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, found := r.keys[id]
+	if !found {
+		return auth.ErrAPIKeyNotFound
+	}
+
+	key.LastUsedAt = at
+	r.keys[id] = key
+	return nil
+}
+
+// sortAPIKeys orders keys by creation time, then ID.
+func sortAPIKeys(keys []auth.APIKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+}
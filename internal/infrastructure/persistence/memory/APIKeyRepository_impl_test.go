@@ -0,0 +1,69 @@
+package memory_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+)
+
+func TestAPIKeyRepository_SaveAndFind(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+
+	key := auth.APIKey{ID: "ci", Name: "ci", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("s3cret")}
+	if err := repo.Save(key); err != nil {
+		t.Fatalf("unexpected error on save: %v", err)
+	}
+
+	got, err := repo.FindByHash(auth.HashAPIKey("s3cret"))
+	if err != nil {
+		t.Fatalf("unexpected error on find: %v", err)
+	}
+	if got != key {
+		t.Errorf("expected %+v, got %+v", key, got)
+	}
+
+	if _, err := repo.FindByHash(auth.HashAPIKey("other")); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestAPIKeyRepository_ReplaceDropsOldHash(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+
+	_ = repo.Save(auth.APIKey{ID: "ci", Name: "ci", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("old")})
+	_ = repo.Save(auth.APIKey{ID: "ci", Name: "ci", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("new")})
+
+	if _, err := repo.FindByHash(auth.HashAPIKey("old")); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Errorf("expected the old secret to stop working, got %v", err)
+	}
+
+	if _, err := repo.FindByHash(auth.HashAPIKey("new")); err != nil {
+		t.Errorf("expected the new secret to work, got %v", err)
+	}
+}
+
+func TestAPIKeyRepository_FindAllAndMarkUsed(t *testing.T) {
+	repo := memory.NewAPIKeyRepository()
+
+	now := time.Now()
+	_ = repo.Save(auth.APIKey{ID: "b", Name: "second", Role: auth.RoleReader, Hash: "h2", CreatedAt: now})
+	_ = repo.Save(auth.APIKey{ID: "a", Name: "first", Role: auth.RoleReader, Hash: "h1", CreatedAt: now.Add(-time.Hour)})
+
+	if err := repo.MarkUsed("b", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.MarkUsed("missing", now); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	keys, _ := repo.FindAll()
+	if len(keys) != 2 || keys[0].ID != "a" || keys[1].ID != "b" {
+		t.Fatalf("expected keys oldest first, got %+v", keys)
+	}
+	if !keys[1].LastUsedAt.Equal(now) {
+		t.Errorf("expected last use to be recorded, got %v", keys[1].LastUsedAt)
+	}
+}
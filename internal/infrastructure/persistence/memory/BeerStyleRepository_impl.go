@@ -2,41 +2,28 @@ package memory
 
 import (
 	"sync"
-
-	"github.com/dgraph-io/ristretto/v2"
+	"sync/atomic"
 
 	domain "karhub-beer-machine/internal/domain/beer"
 )
 
 // BeerStyleRepositoryImpl is an in-memory implementation of BeerStyleRepository
-// backed by Ristretto v2 (generic).
+// backed by copy-on-write catalog snapshots.
 //
-// Key   -> string (BeerStyle.ID)
-// Value -> domain.BeerStyle
+// The catalog is an immutable domain.Catalog published through an atomic
+// pointer: readers (FindAll, FindByID, Snapshot) load it without locking,
+// and writers build the next version and swap it in. Writers serialize on
+// a mutex so no write is lost; they never block readers.
 type BeerStyleRepositoryImpl struct {
-	cache *ristretto.Cache[string, domain.BeerStyle]
-
-	// keys is required because Ristretto does NOT support iteration.
-	// We keep track of inserted keys to support FindAll().
-	mu   sync.RWMutex
-	keys map[string]struct{}
+	mu      sync.Mutex
+	catalog atomic.Pointer[domain.Catalog]
 }
 
-// NewBeerStyleRepository creates a new in-memory BeerStyleRepository using Ristretto v2.
+// NewBeerStyleRepository creates a new, empty in-memory BeerStyleRepository.
 func NewBeerStyleRepository() (*BeerStyleRepositoryImpl, error) {
-	cache, err := ristretto.NewCache(&ristretto.Config[string, domain.BeerStyle]{
-		NumCounters: 1e5,
-		MaxCost:     1 << 20, // ~1MB
-		BufferItems: 64,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return &BeerStyleRepositoryImpl{
-		cache: cache,
-		keys:  make(map[string]struct{}),
-	}, nil
+	r := &BeerStyleRepositoryImpl{}
+	r.catalog.Store(domain.NewCatalog(0, nil))
+	return r, nil
 }
 
 // Create stores a new beer style.
@@ -44,10 +31,7 @@ func (r *BeerStyleRepositoryImpl) Create(style domain.BeerStyle) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.cache.Set(style.ID, style, 1)
-	r.cache.Wait()
-
-	r.keys[style.ID] = struct{}{}
+	r.catalog.Store(r.catalog.Load().With(style))
 	return nil
 }
 
@@ -56,12 +40,12 @@ func (r *BeerStyleRepositoryImpl) Update(style domain.BeerStyle) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, found := r.keys[style.ID]; !found {
+	current := r.catalog.Load()
+	if _, found := current.FindByID(style.ID); !found {
 		return domain.ErrBeerStyleNotFound
 	}
 
-	r.cache.Set(style.ID, style, 1)
-	r.cache.Wait()
+	r.catalog.Store(current.With(style))
 	return nil
 }
 
@@ -70,18 +54,18 @@ func (r *BeerStyleRepositoryImpl) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, found := r.keys[id]; !found {
+	current := r.catalog.Load()
+	if _, found := current.FindByID(id); !found {
 		return domain.ErrBeerStyleNotFound
 	}
 
-	r.cache.Del(id)
-	delete(r.keys, id)
+	r.catalog.Store(current.Without(id))
 	return nil
 }
 
 // FindByID retrieves a beer style by ID.
 func (r *BeerStyleRepositoryImpl) FindByID(id string) (domain.BeerStyle, error) {
-	style, found := r.cache.Get(id)
+	style, found := r.catalog.Load().FindByID(id)
 	if !found {
 		return domain.BeerStyle{}, domain.ErrBeerStyleNotFound
 	}
@@ -89,18 +73,17 @@ func (r *BeerStyleRepositoryImpl) FindByID(id string) (domain.BeerStyle, error)
 	return style, nil
 }
 
-// FindAll retrieves all beer styles stored in memory.
+// FindAll retrieves a copy of all beer styles, in insertion order.
 func (r *BeerStyleRepositoryImpl) FindAll() ([]domain.BeerStyle, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	styles := make([]domain.BeerStyle, 0, len(r.keys))
+	return r.catalog.Load().Styles(), nil
+}
 
-	for id := range r.keys {
-		if style, found := r.cache.Get(id); found {
-			styles = append(styles, style)
-		}
-	}
+// Snapshot returns the current immutable catalog.
+func (r *BeerStyleRepositoryImpl) Snapshot() *domain.Catalog {
+	return r.catalog.Load()
+}
 
-	return styles, nil
+// CatalogVersion returns a number that changes on every write.
+func (r *BeerStyleRepositoryImpl) CatalogVersion() uint64 {
+	return r.catalog.Load().Version()
 }
@@ -1,6 +1,10 @@
 package memory_test
 
 import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	domain "karhub-beer-machine/internal/domain/beer"
@@ -125,3 +129,214 @@ func TestBeerStyleRepository_DeleteNotFound(t *testing.T) {
 		t.Errorf("expected ErrBeerStyleNotFound, got %v", err)
 	}
 }
+
+func TestBeerStyleRepository_CatalogVersion(t *testing.T) {
+	repo, _ := memory.NewBeerStyleRepository()
+
+	style := domain.BeerStyle{ID: "1", Name: "IPA", MinTemp: -7, MaxTemp: 10}
+
+	steps := []struct {
+		name        string
+		write       func() error
+		wantChanged bool
+	}{
+		{"create", func() error { return repo.Create(style) }, true},
+		{"update", func() error { return repo.Update(style) }, true},
+		{"failed update", func() error { return repo.Update(domain.BeerStyle{ID: "missing"}) }, false},
+		{"delete", func() error { return repo.Delete("1") }, true},
+		{"failed delete", func() error { return repo.Delete("1") }, false},
+	}
+
+	for _, step := range steps {
+		before := repo.CatalogVersion()
+		_ = step.write()
+
+		if changed := repo.CatalogVersion() != before; changed != step.wantChanged {
+			t.Errorf("%s: expected version changed=%v, got %v", step.name, step.wantChanged, changed)
+		}
+	}
+}
+
+func TestBeerStyleRepository_ConcurrentReadsAndWrites(t *testing.T) {
+	repo, _ := memory.NewBeerStyleRepository()
+
+	// Every write keeps MaxTemp - MinTemp == 4, so a reader seeing anything
+	// else saw a torn write
+	for i := range 20 {
+		_ = repo.Create(benchmarkStyle(i, 0))
+	}
+
+	var (
+		wg      sync.WaitGroup
+		done    atomic.Bool
+		created atomic.Int64
+		deleted atomic.Int64
+	)
+
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range 200 {
+				_ = repo.Update(benchmarkStyle((w*200+i)%20, float64(i%7)))
+
+				// Each writer owns its own extra IDs
+				extra := benchmarkStyle(1000+w*1000+i, 0)
+				_ = repo.Create(extra)
+				created.Add(1)
+
+				if i%2 == 0 {
+					if err := repo.Delete(extra.ID); err == nil {
+						deleted.Add(1)
+					}
+				}
+			}
+		}()
+	}
+
+	readerErrs := make(chan string, 8)
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var lastVersion uint64
+
+			for !done.Load() {
+				snapshot := repo.Snapshot()
+
+				if snapshot.Version() < lastVersion {
+					readerErrs <- fmt.Sprintf("version went back from %d to %d", lastVersion, snapshot.Version())
+					return
+				}
+				lastVersion = snapshot.Version()
+
+				styles := snapshot.Styles()
+				if len(styles) != snapshot.Len() || len(styles) < 20 {
+					readerErrs <- fmt.Sprintf("inconsistent snapshot: %d styles, Len %d", len(styles), snapshot.Len())
+					return
+				}
+
+				for _, style := range styles {
+					if style.MaxTemp-style.MinTemp != 4 {
+						readerErrs <- fmt.Sprintf("torn style %+v", style)
+						return
+					}
+				}
+
+				all, _ := repo.FindAll()
+				if _, err := domain.SelectBestStyle(all, 0); err != nil {
+					readerErrs <- err.Error()
+					return
+				}
+			}
+		}()
+	}
+
+	// Stop the readers once the writers are done
+	go func() {
+		for created.Load() < 4*200 {
+			runtime.Gosched()
+		}
+		done.Store(true)
+	}()
+
+	wg.Wait()
+	close(readerErrs)
+
+	for err := range readerErrs {
+		t.Error(err)
+	}
+
+	all, _ := repo.FindAll()
+	if want := 20 + int(created.Load()-deleted.Load()); len(all) != want {
+		t.Errorf("expected %d styles, got %d", want, len(all))
+	}
+
+	if want := uint64(20 + 4*200*2 + deleted.Load()); repo.CatalogVersion() != want {
+		t.Errorf("expected version %d (one per write), got %d", want, repo.CatalogVersion())
+	}
+}
+
+/*
+	BENCHMARKS
+*/
+
+// BenchmarkBeerStyleRepository_MixedLoad runs parallel recommendations
+// with one write every writeEvery operations. Recommendations read either
+// a copy (FindAll + SelectBestStyle) or the snapshot itself.
+func BenchmarkBeerStyleRepository_MixedLoad(b *testing.B) {
+	for _, size := range []int{10, 100} {
+		for _, writeEvery := range []int{0, 100, 10} {
+			for _, read := range []string{"findall", "snapshot"} {
+				benchmarkMixedLoad(b, size, writeEvery, read)
+			}
+		}
+	}
+}
+
+func benchmarkMixedLoad(b *testing.B, size, writeEvery int, read string) {
+	name := fmt.Sprintf("styles=%d/writes=%s/read=%s", size, writeRatio(writeEvery), read)
+
+	b.Run(name, func(b *testing.B) {
+		repo, err := memory.NewBeerStyleRepository()
+		if err != nil {
+			b.Fatalf("failed to create repository: %v", err)
+		}
+
+		for i := range size {
+			_ = repo.Create(benchmarkStyle(i, 0))
+		}
+
+		var ops atomic.Uint64
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				n := ops.Add(1)
+				temp := float64(n%25) - 10
+
+				if writeEvery > 0 && n%uint64(writeEvery) == 0 {
+					_ = repo.Update(benchmarkStyle(int(n)%size, float64(n%7)))
+					continue
+				}
+
+				if read == "snapshot" {
+					if _, err := repo.Snapshot().SelectBestStyle(temp); err != nil {
+						b.Fatal(err)
+					}
+					continue
+				}
+
+				styles, err := repo.FindAll()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := domain.SelectBestStyle(styles, temp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
+func benchmarkStyle(i int, shift float64) domain.BeerStyle {
+	avg := -10 + float64(i%25) + shift
+	return domain.BeerStyle{
+		ID:      fmt.Sprint(i),
+		Name:    fmt.Sprintf("Style %04d", i),
+		MinTemp: avg - 2,
+		MaxTemp: avg + 2,
+	}
+}
+
+func writeRatio(writeEvery int) string {
+	if writeEvery == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", 100/writeEvery)
+}
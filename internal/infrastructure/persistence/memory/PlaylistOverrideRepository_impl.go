@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"sync"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+// PlaylistOverrideRepositoryImpl is an in-memory implementation of
+// beer.PlaylistOverrideRepository.
+//
+// Key   -> string (BeerStyle.ID)
+// Value -> beer.PlaylistOverride
+type PlaylistOverrideRepositoryImpl struct {
+	mu        sync.RWMutex
+	overrides map[string]beer.PlaylistOverride
+}
+
+// NewPlaylistOverrideRepository creates a new in-memory PlaylistOverrideRepository.
+func NewPlaylistOverrideRepository() *PlaylistOverrideRepositoryImpl {
+	return &PlaylistOverrideRepositoryImpl{
+		overrides: make(map[string]beer.PlaylistOverride),
+	}
+}
+
+// Save creates or replaces the override of a style.
+func (r *PlaylistOverrideRepositoryImpl) Save(override beer.PlaylistOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.overrides[override.StyleID] = cloneOverride(override)
+	return nil
+}
+
+// Delete removes the override of a style.
+func (r *PlaylistOverrideRepositoryImpl) Delete(styleID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.overrides[styleID]; !found {
+		return beer.ErrPlaylistOverrideNotFound
+	}
+
+	delete(r.overrides, styleID)
+	return nil
+}
+
+// FindByStyleID retrieves the override of a style.
+func (r *PlaylistOverrideRepositoryImpl) FindByStyleID(styleID string) (beer.PlaylistOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	override, found := r.overrides[styleID]
+	if !found {
+		return beer.PlaylistOverride{}, beer.ErrPlaylistOverrideNotFound
+	}
+
+	return cloneOverride(override), nil
+}
+
+// cloneOverride copies the track slice so callers cannot mutate stored state.
+func cloneOverride(o beer.PlaylistOverride) beer.PlaylistOverride {
+	o.Playlist.Tracks = append([]beer.Track(nil), o.Playlist.Tracks...)
+	o.Playlist.Images = append([]beer.Image(nil), o.Playlist.Images...)
+	return o
+}
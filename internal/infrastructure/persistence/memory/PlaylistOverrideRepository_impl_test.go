@@ -0,0 +1,67 @@
+package memory_test
+
+import (
+	"testing"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+)
+
+func TestPlaylistOverrideRepository_SaveAndFind(t *testing.T) {
+	repo := memory.NewPlaylistOverrideRepository()
+
+	override := beer.PlaylistOverride{
+		StyleID: "1",
+		Playlist: beer.Playlist{
+			Name:   "Staff Picks",
+			Tracks: []beer.Track{{Name: "Dark Roast"}},
+		},
+	}
+
+	if err := repo.Save(override); err != nil {
+		t.Fatalf("unexpected error on save: %v", err)
+	}
+
+	// Mutating the caller's copy must not affect the stored override
+	override.Playlist.Tracks[0].Name = "Changed"
+
+	got, err := repo.FindByStyleID("1")
+	if err != nil {
+		t.Fatalf("unexpected error on find: %v", err)
+	}
+
+	if got.Playlist.Tracks[0].Name != "Dark Roast" {
+		t.Errorf("expected stored track Dark Roast, got %s", got.Playlist.Tracks[0].Name)
+	}
+}
+
+func TestPlaylistOverrideRepository_Replace(t *testing.T) {
+	repo := memory.NewPlaylistOverrideRepository()
+
+	_ = repo.Save(beer.PlaylistOverride{StyleID: "1", Playlist: beer.Playlist{Name: "Old"}})
+	_ = repo.Save(beer.PlaylistOverride{StyleID: "1", ExternalURI: "spotify:playlist:abc"})
+
+	got, _ := repo.FindByStyleID("1")
+
+	if got.ExternalURI != "spotify:playlist:abc" || got.Playlist.Name != "" {
+		t.Errorf("expected override to be replaced, got %+v", got)
+	}
+}
+
+func TestPlaylistOverrideRepository_Delete(t *testing.T) {
+	repo := memory.NewPlaylistOverrideRepository()
+
+	_ = repo.Save(beer.PlaylistOverride{StyleID: "1", Playlist: beer.Playlist{Name: "Staff Picks"}})
+
+	if err := repo.Delete("1"); err != nil {
+		t.Fatalf("unexpected error on delete: %v", err)
+	}
+
+	if _, err := repo.FindByStyleID("1"); err != beer.ErrPlaylistOverrideNotFound {
+		t.Errorf("expected ErrPlaylistOverrideNotFound, got %v", err)
+	}
+
+	if err := repo.Delete("1"); err != beer.ErrPlaylistOverrideNotFound {
+		t.Errorf("expected ErrPlaylistOverrideNotFound, got %v", err)
+	}
+}
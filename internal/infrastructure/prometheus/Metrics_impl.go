@@ -0,0 +1,186 @@
+package prometheus
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+)
+
+// namespace prefixes every metric of the service.
+const namespace = "karhub_"
+
+// Metrics are the metrics of the beer machine. They implement
+// metrics.Recorder for use cases and middleware.RequestObserver for the
+// HTTP server, and watch the state owned by other components.
+type Metrics struct {
+	registry *Registry
+
+	httpRequests *CounterVec
+	httpDuration *HistogramVec
+
+	useCases        *HistogramVec
+	recommendations *CounterVec
+
+	spotifyRequests *CounterVec
+	spotifyDuration *HistogramVec
+}
+
+// NewMetrics registers the metrics of the service in registry.
+func NewMetrics(registry *Registry) *Metrics {
+	return &Metrics{
+		registry: registry,
+
+		httpRequests: registry.NewCounterVec(
+			namespace+"http_requests_total",
+			"HTTP requests served, by route pattern and status.",
+			"method", "route", "status",
+		),
+		httpDuration: registry.NewHistogramVec(
+			namespace+"http_request_duration_seconds",
+			"Latency of HTTP requests, by route pattern and status.",
+			DefBuckets,
+			"method", "route", "status",
+		),
+
+		useCases: registry.NewHistogramVec(
+			namespace+"use_case_duration_seconds",
+			"Duration of use case executions.",
+			DefBuckets,
+			"use_case",
+		),
+		recommendations: registry.NewCounterVec(
+			namespace+"recommendations_total",
+			"Recommendations served, by beer style.",
+			"style",
+		),
+
+		spotifyRequests: registry.NewCounterVec(
+			namespace+"spotify_requests_total",
+			"Spotify Web API calls, by outcome.",
+			"outcome",
+		),
+		spotifyDuration: registry.NewHistogramVec(
+			namespace+"spotify_request_duration_seconds",
+			"Latency of Spotify Web API calls, pacing included, by outcome.",
+			DefBuckets,
+			"outcome",
+		),
+	}
+}
+
+// ObserveRequest implements middleware.RequestObserver.
+func (m *Metrics) ObserveRequest(method, route string, status int, latency time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.With(method, route, code).Inc()
+	m.httpDuration.With(method, route, code).Observe(latency.Seconds())
+}
+
+// ObserveUseCase implements metrics.Recorder.
+func (m *Metrics) ObserveUseCase(useCase string, duration time.Duration) {
+	m.useCases.With(useCase).Observe(duration.Seconds())
+}
+
+// CountRecommendation implements metrics.Recorder.
+func (m *Metrics) CountRecommendation(style string) {
+	m.recommendations.With(style).Inc()
+}
+
+// ObserveSpotifyCall records the outcome and latency of a Spotify call
+// (see spotify.WithCallObserver).
+func (m *Metrics) ObserveSpotifyCall(outcome string, latency time.Duration) {
+	m.spotifyRequests.With(outcome).Inc()
+	m.spotifyDuration.With(outcome).Observe(latency.Seconds())
+}
+
+// WatchCaches exports the counters and hit ratio of caches, by name.
+// Each cache is asked for its stats once per scrape, and every family is
+// emitted from that snapshot.
+func (m *Metrics) WatchCaches(caches map[string]beer.AdministrableCache) {
+	names := slices.Sorted(maps.Keys(caches))
+	stats := make([]beer.CacheStats, len(names))
+
+	m.registry.OnScrape(func() {
+		for i, name := range names {
+			stats[i] = caches[name].CacheStats()
+		}
+	})
+
+	each := func(emit func(value float64, labelValues ...string), value func(beer.CacheStats) float64) {
+		for i, name := range names {
+			emit(value(stats[i]), name)
+		}
+	}
+
+	m.registry.NewCounterFunc(
+		namespace+"cache_hits_total",
+		"Cache lookups that found an entry.",
+		[]string{"cache"},
+		func(emit func(float64, ...string)) {
+			each(emit, func(s beer.CacheStats) float64 { return float64(s.Hits) })
+		},
+	)
+	m.registry.NewCounterFunc(
+		namespace+"cache_misses_total",
+		"Cache lookups that found no entry.",
+		[]string{"cache"},
+		func(emit func(float64, ...string)) {
+			each(emit, func(s beer.CacheStats) float64 { return float64(s.Misses) })
+		},
+	)
+	m.registry.NewGaugeFunc(
+		namespace+"cache_hit_ratio",
+		"Hits over lookups since the cache was created.",
+		[]string{"cache"},
+		func(emit func(float64, ...string)) {
+			each(emit, func(s beer.CacheStats) float64 { return s.HitRatio })
+		},
+	)
+	m.registry.NewGaugeFunc(
+		namespace+"cache_keys",
+		"Entries currently cached.",
+		[]string{"cache"},
+		func(emit func(float64, ...string)) {
+			each(emit, func(s beer.CacheStats) float64 { return float64(s.Keys) })
+		},
+	)
+}
+
+// WatchCatalog exports the number of beer styles in the catalog.
+func (m *Metrics) WatchCatalog(repository domain.BeerStyleRepository) {
+	m.registry.NewGaugeFunc(
+		namespace+"catalog_styles",
+		"Beer styles in the catalog.",
+		nil,
+		func(emit func(float64, ...string)) {
+			if snapshotter, ok := repository.(domain.CatalogSnapshotter); ok {
+				emit(float64(snapshotter.Snapshot().Len()))
+				return
+			}
+
+			if styles, err := repository.FindAll(); err == nil {
+				emit(float64(len(styles)))
+			}
+		},
+	)
+}
+
+// WatchSpotifyBackoff exports whether Spotify calls are held back: after
+// a 429, every call waits out Retry-After, like an open circuit breaker.
+func (m *Metrics) WatchSpotifyBackoff(scheduler interface{ BlockedUntil() time.Time }) {
+	m.registry.NewGaugeFunc(
+		namespace+"spotify_breaker_open",
+		"1 while Spotify calls wait out a Retry-After backoff, 0 otherwise.",
+		nil,
+		func(emit func(float64, ...string)) {
+			if scheduler.BlockedUntil().IsZero() {
+				emit(0)
+				return
+			}
+			emit(1)
+		},
+	)
+}
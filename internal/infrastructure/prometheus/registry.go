@@ -0,0 +1,400 @@
+// Package prometheus exports metrics in the Prometheus text exposition
+// format (version 0.0.4) with the standard library only.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are latency buckets in seconds, from 5ms to 10s.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector writes one metric family.
+type collector interface {
+	name() string
+	collect(w *bufio.Writer)
+}
+
+// Registry holds metric families and serves them to Prometheus. It is
+// safe for concurrent use.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	hooks      []func()
+
+	// scraping serializes scrapes, so hooks and the families reading
+	// what they sampled see a single scrape.
+	scraping sync.Mutex
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// register adds c, and panics on a duplicate name: families are
+// registered at startup, where a clash is a programming error.
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic("prometheus: duplicate metric " + c.name())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// OnScrape registers fn to run at the start of every scrape, before any
+// family is collected: a snapshot taken there can feed several families.
+func (r *Registry) OnScrape(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, fn)
+}
+
+// ServeHTTP writes every family, sorted by name.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	hooks := slices.Clone(r.hooks)
+	r.mu.Unlock()
+
+	r.scraping.Lock()
+	defer r.scraping.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	slices.SortFunc(collectors, func(a, b collector) int {
+		return strings.Compare(a.name(), b.name())
+	})
+
+	w.Header().Set("Content-Type", ContentType)
+
+	buf := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.collect(buf)
+	}
+	_ = buf.Flush()
+}
+
+/*
+	---------- Families ----------
+*/
+
+// family is the metadata shared by every kind of metric.
+type family struct {
+	fullName   string
+	help       string
+	kind       string
+	labelNames []string
+}
+
+func (f *family) name() string {
+	return f.fullName
+}
+
+func (f *family) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.fullName, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.fullName, f.kind)
+}
+
+// writeSample writes one sample line; extra is an already formatted
+// label pair (e.g. the le of a bucket).
+func (f *family) writeSample(w *bufio.Writer, suffix string, labelValues []string, extra string, value float64) {
+	w.WriteString(f.fullName)
+	w.WriteString(suffix)
+
+	if len(labelValues) > 0 || extra != "" {
+		w.WriteByte('{')
+		for i, v := range labelValues {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", f.labelNames[i], escapeLabel(v))
+		}
+		if extra != "" {
+			if len(labelValues) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extra)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// vec maps label values to the series of a family.
+type vec[S any] struct {
+	family
+	mu     sync.RWMutex
+	series map[string]*labeled[S]
+	create func() *S
+}
+
+type labeled[S any] struct {
+	values []string
+	series *S
+}
+
+func newVec[S any](name, help, kind string, labelNames []string, create func() *S) vec[S] {
+	return vec[S]{
+		family: family{fullName: name, help: help, kind: kind, labelNames: labelNames},
+		series: make(map[string]*labeled[S]),
+		create: create,
+	}
+}
+
+// with returns the series of labelValues, created on first use.
+func (v *vec[S]) with(labelValues []string) *S {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("prometheus: %s wants %d label values, got %d",
+			v.fullName, len(v.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	v.mu.RLock()
+	l, found := v.series[key]
+	v.mu.RUnlock()
+	if found {
+		return l.series
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if l, found := v.series[key]; found {
+		return l.series
+	}
+
+	l = &labeled[S]{values: slices.Clone(labelValues), series: v.create()}
+	v.series[key] = l
+	return l.series
+}
+
+// sorted returns the series ordered by label values, for stable output.
+func (v *vec[S]) sorted() []*labeled[S] {
+	v.mu.RLock()
+	all := make([]*labeled[S], 0, len(v.series))
+	for _, l := range v.series {
+		all = append(all, l)
+	}
+	v.mu.RUnlock()
+
+	slices.SortFunc(all, func(a, b *labeled[S]) int {
+		return slices.Compare(a.values, b.values)
+	})
+	return all
+}
+
+/*
+	---------- Counters ----------
+*/
+
+// Counter is a value that only goes up.
+type Counter struct {
+	bits atomic.Uint64
+}
+
+// Inc adds 1.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds delta, which must not be negative.
+func (c *Counter) Add(delta float64) {
+	addFloat(&c.bits, delta)
+}
+
+// Value returns the current count.
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(c.bits.Load())
+}
+
+// CounterVec is a family of counters partitioned by labels.
+type CounterVec struct {
+	vec[Counter]
+}
+
+// NewCounterVec registers a counter family.
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{newVec(name, help, "counter", labelNames, func() *Counter { return &Counter{} })}
+	r.register(c)
+	return c
+}
+
+// With returns the counter of labelValues, in label name order.
+func (c *CounterVec) With(labelValues ...string) *Counter {
+	return c.with(labelValues)
+}
+
+func (c *CounterVec) collect(w *bufio.Writer) {
+	c.writeHeader(w)
+	for _, l := range c.sorted() {
+		c.writeSample(w, "", l.values, "", l.series.Value())
+	}
+}
+
+/*
+	---------- Histograms ----------
+*/
+
+// Histogram counts observations in buckets.
+type Histogram struct {
+	upperBounds []float64
+
+	// counts are per bucket, not cumulative; the last one is +Inf
+	counts  []atomic.Uint64
+	sumBits atomic.Uint64
+	count   atomic.Uint64
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(value float64) {
+	i, _ := slices.BinarySearch(h.upperBounds, value)
+	h.counts[i].Add(1)
+	addFloat(&h.sumBits, value)
+	h.count.Add(1)
+}
+
+// HistogramVec is a family of histograms partitioned by labels.
+type HistogramVec struct {
+	vec[Histogram]
+	buckets []float64
+}
+
+// NewHistogramVec registers a histogram family with the given bucket
+// upper bounds, in increasing order.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	h := &HistogramVec{buckets: buckets}
+	h.vec = newVec(name, help, "histogram", labelNames, func() *Histogram {
+		return &Histogram{
+			upperBounds: buckets,
+			counts:      make([]atomic.Uint64, len(buckets)+1),
+		}
+	})
+	r.register(h)
+	return h
+}
+
+// With returns the histogram of labelValues, in label name order.
+func (h *HistogramVec) With(labelValues ...string) *Histogram {
+	return h.with(labelValues)
+}
+
+func (h *HistogramVec) collect(w *bufio.Writer) {
+	h.writeHeader(w)
+
+	for _, l := range h.sorted() {
+		// Read the count first: observations landing meanwhile can only
+		// make buckets larger, never the +Inf bucket smaller than it
+		count := l.series.count.Load()
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += l.series.counts[i].Load()
+			h.writeSample(w, "_bucket", l.values, `le="`+formatFloat(bound)+`"`, float64(cumulative))
+		}
+		cumulative += l.series.counts[len(h.buckets)].Load()
+
+		h.writeSample(w, "_bucket", l.values, `le="+Inf"`, float64(max(cumulative, count)))
+		h.writeSample(w, "_sum", l.values, "", math.Float64frombits(l.series.sumBits.Load()))
+		h.writeSample(w, "_count", l.values, "", float64(max(cumulative, count)))
+	}
+}
+
+/*
+	---------- Functions ----------
+*/
+
+// funcFamily samples values from a function at every scrape, for state
+// owned elsewhere (cache counters, catalog size, ...).
+type funcFamily struct {
+	family
+	fn func(emit func(value float64, labelValues ...string))
+}
+
+// NewGaugeFunc registers a gauge family whose samples fn emits at every
+// scrape, one call of emit per series.
+func (r *Registry) NewGaugeFunc(name, help string, labelNames []string, fn func(emit func(value float64, labelValues ...string))) {
+	r.register(&funcFamily{
+		family: family{fullName: name, help: help, kind: "gauge", labelNames: labelNames},
+		fn:     fn,
+	})
+}
+
+// NewCounterFunc is NewGaugeFunc for values that only go up.
+func (r *Registry) NewCounterFunc(name, help string, labelNames []string, fn func(emit func(value float64, labelValues ...string))) {
+	r.register(&funcFamily{
+		family: family{fullName: name, help: help, kind: "counter", labelNames: labelNames},
+		fn:     fn,
+	})
+}
+
+func (f *funcFamily) collect(w *bufio.Writer) {
+	f.writeHeader(w)
+	f.fn(func(value float64, labelValues ...string) {
+		f.writeSample(w, "", labelValues, "", value)
+	})
+}
+
+/*
+	---------- Helpers ----------
+*/
+
+// addFloat atomically adds delta to the float64 stored in bits.
+func addFloat(bits *atomic.Uint64, delta float64) {
+	for {
+		old := bits.Load()
+		if bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,225 @@
+package prometheus_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	"karhub-beer-machine/internal/infrastructure/prometheus"
+)
+
+type fakeCache struct {
+	beer.AdministrableCache
+	stats beer.CacheStats
+}
+
+func (c fakeCache) CacheStats() beer.CacheStats {
+	return c.stats
+}
+
+// countingCache counts how often its stats are taken.
+type countingCache struct {
+	beer.AdministrableCache
+	calls *int
+}
+
+func (c countingCache) CacheStats() beer.CacheStats {
+	*c.calls++
+	return beer.CacheStats{Hits: uint64(*c.calls)}
+}
+
+func scrape(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != prometheus.ContentType {
+		t.Fatalf("expected content type %q, got %q", prometheus.ContentType, ct)
+	}
+	return rec.Body.String()
+}
+
+/*
+	TESTS
+*/
+
+func TestRegistry_Exposition(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	requests := registry.NewCounterVec("requests_total", "Requests\nserved.", "route", "status")
+	requests.With("/b", "200").Add(2)
+	requests.With("/a", "500").Inc()
+	requests.With(`say "hi"\`, "200").Inc()
+
+	latency := registry.NewHistogramVec("latency_seconds", "Latency.", []float64{0.5, 0.1}, "route")
+	for _, v := range []float64{0.05, 0.1, 0.3, 2} {
+		latency.With("/a").Observe(v)
+	}
+
+	registry.NewGaugeFunc("styles", "Styles.", nil, func(emit func(float64, ...string)) {
+		emit(3)
+	})
+
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 2
+latency_seconds_bucket{route="/a",le="0.5"} 3
+latency_seconds_bucket{route="/a",le="+Inf"} 4
+latency_seconds_sum{route="/a"} 2.45
+latency_seconds_count{route="/a"} 4
+# HELP requests_total Requests\nserved.
+# TYPE requests_total counter
+requests_total{route="/a",status="500"} 1
+requests_total{route="/b",status="200"} 2
+requests_total{route="say \"hi\"\\",status="200"} 1
+# HELP styles Styles.
+# TYPE styles gauge
+styles 3
+`
+
+	if got := scrape(t, registry); got != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *prometheus.Registry)
+	}{
+		{
+			name: "duplicate name",
+			fn: func(r *prometheus.Registry) {
+				r.NewCounterVec("dup", "")
+				r.NewGaugeFunc("dup", "", nil, func(func(float64, ...string)) {})
+			},
+		},
+		{
+			name: "wrong number of label values",
+			fn: func(r *prometheus.Registry) {
+				r.NewCounterVec("c", "", "a", "b").With("only one")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic")
+				}
+			}()
+			tt.fn(prometheus.NewRegistry())
+		})
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := registry.NewCounterVec("c_total", "", "k")
+	histogram := registry.NewHistogramVec("h", "", prometheus.DefBuckets, "k")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				counter.With("x").Inc()
+				histogram.With("x").Observe(float64(i) / 1000)
+				if i%100 == 0 {
+					scrape(t, registry)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	out := scrape(t, registry)
+	for _, line := range []string{`c_total{k="x"} 8000`, `h_count{k="x"} 8000`, `h_bucket{k="x",le="+Inf"} 8000`} {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("expected %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := prometheus.NewMetrics(registry)
+
+	repo, err := memory.NewBeerStyleRepository()
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	_ = repo.Create(domain.BeerStyle{ID: "new", Name: "Gose", MinTemp: 2, MaxTemp: 6})
+	styles, _ := repo.FindAll()
+
+	metrics.WatchCatalog(repo)
+	metrics.WatchCaches(map[string]beer.AdministrableCache{
+		"playlists": fakeCache{stats: beer.CacheStats{Hits: 3, Misses: 1, HitRatio: 0.75, Keys: 2}},
+	})
+	metrics.WatchSpotifyBackoff(blockedUntil(time.Now().Add(time.Minute)))
+
+	metrics.ObserveRequest(http.MethodPost, "/beer-styles/best", http.StatusOK, 30*time.Millisecond)
+	metrics.ObserveUseCase("find_best_beer_style", 20*time.Millisecond)
+	metrics.CountRecommendation("IPA")
+	metrics.ObserveSpotifyCall("ok", 200*time.Millisecond)
+
+	out := scrape(t, registry)
+
+	for _, line := range []string{
+		`karhub_http_requests_total{method="POST",route="/beer-styles/best",status="200"} 1`,
+		`karhub_http_request_duration_seconds_bucket{method="POST",route="/beer-styles/best",status="200",le="0.05"} 1`,
+		`karhub_use_case_duration_seconds_count{use_case="find_best_beer_style"} 1`,
+		`karhub_recommendations_total{style="IPA"} 1`,
+		`karhub_spotify_requests_total{outcome="ok"} 1`,
+		`karhub_spotify_request_duration_seconds_bucket{outcome="ok",le="0.1"} 0`,
+		`karhub_spotify_breaker_open 1`,
+		`karhub_cache_hits_total{cache="playlists"} 3`,
+		`karhub_cache_misses_total{cache="playlists"} 1`,
+		`karhub_cache_hit_ratio{cache="playlists"} 0.75`,
+		`karhub_catalog_styles ` + strconv.Itoa(len(styles)),
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("expected %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestMetrics_CacheStatsOncePerScrape(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := prometheus.NewMetrics(registry)
+
+	var playlists, recommendations int
+	metrics.WatchCaches(map[string]beer.AdministrableCache{
+		"playlists":       countingCache{calls: &playlists},
+		"recommendations": countingCache{calls: &recommendations},
+	})
+
+	for i := 1; i <= 2; i++ {
+		out := scrape(t, registry)
+
+		if playlists != i || recommendations != i {
+			t.Fatalf("scrape %d: expected %d stats calls per cache, got %d and %d", i, i, playlists, recommendations)
+		}
+
+		line := `karhub_cache_hits_total{cache="playlists"} ` + strconv.Itoa(i)
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("expected %q in:\n%s", line, out)
+		}
+	}
+}
+
+type blockedUntil time.Time
+
+func (b blockedUntil) BlockedUntil() time.Time {
+	return time.Time(b)
+}
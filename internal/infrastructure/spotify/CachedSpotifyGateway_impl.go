@@ -2,31 +2,184 @@ package spotify
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/tracing"
 	"karhub-beer-machine/internal/infrastructure/cache"
 )
 
-// CachedGateway decorates a SpotifyGateway with cache.
+// Default background refresh settings.
+const (
+	DefaultRefreshWorkers = 4
+	DefaultRefreshQueue   = 64
+
+	// fetchTimeout bounds a single upstream fetch, which is shared by
+	// every caller waiting for the same key.
+	fetchTimeout = 10 * time.Second
+)
+
+// CachedGateway decorates a MusicProvider with cache.
+//
+// Entries younger than the soft TTL are served as fresh. Between the soft
+// and hard TTLs they are served immediately as stale while a bounded pool
+// of workers refreshes them in the background. Past the hard TTL the
+// provider is called synchronously, and the old entry is only served
+// (for up to maxStale more) when that call fails.
+//
+// Not-found answers are cached separately for a shorter negative TTL,
+// and are never masked by stale entries.
+//
+// Concurrent fetches of the same key are coalesced into a single
+// upstream call whose result is shared by every waiting caller.
+//
+// As a CatalogObserver it drops the entries of deleted or renamed styles
+// and pre-warms new names through the same worker pool.
+//
+// The TTLs can be changed while it serves, with SetTTLs.
 type CachedGateway struct {
-	gateway beer.SpotifyGateway
-	cache   *cache.RistrettoCache[string, beer.Playlist]
-	ttl     time.Duration
+	gateway beer.MusicProvider
+	cache   cache.Cache[string, beer.Playlist]
+	flight  *cache.Group[beer.Playlist]
+	ttls    atomic.Pointer[cacheTTLs]
+
+	refreshes chan refreshJob
+	wg        sync.WaitGroup
+
+	mu         sync.Mutex
+	refreshing map[string]struct{}
+	closed     bool
+}
+
+// refreshJob reloads one cache key in the background.
+type refreshJob struct {
+	ctx   context.Context
+	key   string
+	fetch func(ctx context.Context) (beer.Playlist, error)
+}
+
+// cacheTTLs are the TTLs of a CachedGateway, swapped as a whole.
+type cacheTTLs struct {
+	softTTL     time.Duration
+	hardTTL     time.Duration
+	maxStale    time.Duration
+	negativeTTL time.Duration
+}
+
+// cacheConfig holds the optional CachedGateway settings.
+type cacheConfig struct {
+	hardTTL     time.Duration
+	maxStale    time.Duration
+	negativeTTL time.Duration
+	workers     int
+	queueSize   int
+}
+
+// newCacheConfig applies opts over the defaults for the soft TTL ttl.
+func newCacheConfig(ttl time.Duration, opts []CacheOption) cacheConfig {
+	cfg := cacheConfig{
+		hardTTL:   ttl,
+		workers:   DefaultRefreshWorkers,
+		queueSize: DefaultRefreshQueue,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.hardTTL < ttl {
+		cfg.hardTTL = ttl
+	}
+
+	return cfg
+}
+
+func (cfg cacheConfig) ttls(ttl time.Duration) *cacheTTLs {
+	return &cacheTTLs{
+		softTTL:     ttl,
+		hardTTL:     cfg.hardTTL,
+		maxStale:    cfg.maxStale,
+		negativeTTL: cfg.negativeTTL,
+	}
+}
+
+// CacheOption customizes a CachedGateway.
+type CacheOption func(*cacheConfig)
+
+// WithStaleWhileRevalidate keeps serving entries up to hardTTL old,
+// refreshing them in the background once they pass the soft TTL.
+func WithStaleWhileRevalidate(hardTTL time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.hardTTL = hardTTL
+	}
+}
+
+// WithStaleIfError keeps entries for maxStale past the hard TTL, to be
+// served only when the provider fails.
+func WithStaleIfError(maxStale time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.maxStale = maxStale
+	}
+}
+
+// WithNegativeTTL caches not-found answers for ttl, so styles without
+// a playlist do not call the provider on every request.
+func WithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithRefreshWorkers bounds the background refresh and prefetch pool.
+// Jobs that do not fit in the queue are dropped; the next request
+// retries them.
+func WithRefreshWorkers(workers, queueSize int) CacheOption {
+	return func(c *cacheConfig) {
+		c.workers = workers
+		c.queueSize = queueSize
+	}
 }
 
 // NewCachedSpotifyGateway creates a cached Spotify gateway.
+// ttl is the soft TTL; without options it is also the hard TTL.
 func NewCachedSpotifyGateway(
-	gateway beer.SpotifyGateway,
-	cache *cache.RistrettoCache[string, beer.Playlist],
+	gateway beer.MusicProvider,
+	playlistCache cache.Cache[string, beer.Playlist],
 	ttl time.Duration,
+	opts ...CacheOption,
 ) *CachedGateway {
-	return &CachedGateway{
-		gateway: gateway,
-		cache:   cache,
-		ttl:     ttl,
+	cfg := newCacheConfig(ttl, opts)
+
+	c := &CachedGateway{
+		gateway:    gateway,
+		cache:      playlistCache,
+		flight:     cache.NewGroup[beer.Playlist](fetchTimeout),
+		refreshing: make(map[string]struct{}),
 	}
+	c.ttls.Store(cfg.ttls(ttl))
+
+	if cfg.workers > 0 {
+		c.refreshes = make(chan refreshJob, cfg.queueSize)
+		for range cfg.workers {
+			c.wg.Add(1)
+			go c.refreshWorker()
+		}
+	}
+
+	return c
+}
+
+// SetTTLs replaces the TTLs, as NewCachedSpotifyGateway sets them; the
+// worker options are ignored. Lookups use the new TTLs at once, but
+// entries already cached are only kept for as long as they were stored
+// for.
+func (c *CachedGateway) SetTTLs(ttl time.Duration, opts ...CacheOption) {
+	c.ttls.Store(newCacheConfig(ttl, opts).ttls(ttl))
 }
 
 func (c *CachedGateway) FindPlaylistByStyle(
@@ -34,17 +187,270 @@ func (c *CachedGateway) FindPlaylistByStyle(
 	styleName string,
 ) (beer.Playlist, error) {
 
-	key := fmt.Sprintf("spotify:playlist:%s", styleName)
+	return c.get(ctx, playlistKey(styleName), c.styleFetcher(styleName))
+}
+
+// Warm loads the playlist for styleName into the cache, unless a fresh
+// entry (or not-found answer) is already there.
+func (c *CachedGateway) Warm(ctx context.Context, styleName string) error {
+	key := playlistKey(styleName)
 
-	if playlist, found := c.cache.Get(key); found {
-		return playlist, nil
+	if cached, found := c.cache.Get(key); found && time.Since(cached.FetchedAt) < c.ttls.Load().softTTL {
+		return nil
+	}
+
+	if _, notFound := c.cache.Get(notFoundKey(key)); notFound {
+		return ErrPlaylistNotFound
+	}
+
+	_, err := c.load(ctx, key, c.styleFetcher(styleName))
+	return err
+}
+
+// Invalidate drops the cached playlist (or not-found answer) for styleName.
+func (c *CachedGateway) Invalidate(styleName string) {
+	key := playlistKey(styleName)
+
+	c.cache.Delete(key)
+	c.cache.Delete(notFoundKey(key))
+}
+
+// OnCatalogChange keeps the cache in step with the beer style catalog.
+func (c *CachedGateway) OnCatalogChange(ctx context.Context, change beer.CatalogChange) {
+	switch change.Kind {
+	case beer.CatalogStyleDeleted:
+		if change.PreviousName != "" {
+			c.Invalidate(change.PreviousName)
+		}
+	case beer.CatalogStyleCreated:
+		c.prefetch(ctx, change.Name)
+	case beer.CatalogStyleUpdated:
+		if change.Renamed() {
+			c.Invalidate(change.PreviousName)
+			c.prefetch(ctx, change.Name)
+		}
+	}
+}
+
+// prefetch warms styleName in the background.
+func (c *CachedGateway) prefetch(ctx context.Context, styleName string) {
+	if styleName == "" {
+		return
+	}
+
+	c.scheduleRefresh(ctx, playlistKey(styleName), c.styleFetcher(styleName))
+}
+
+func (c *CachedGateway) styleFetcher(
+	styleName string,
+) func(ctx context.Context) (beer.Playlist, error) {
+	return func(ctx context.Context) (beer.Playlist, error) {
+		return c.gateway.FindPlaylistByStyle(ctx, styleName)
+	}
+}
+
+func playlistKey(styleName string) string {
+	return fmt.Sprintf("spotify:playlist:%s", styleName)
+}
+
+// notFoundKey is where the negative entry for key is stored.
+func notFoundKey(key string) string {
+	return key + ":notfound"
+}
+
+// FindPlaylistByURI resolves a pinned playlist through the cache, when the
+// decorated gateway supports URI lookups.
+func (c *CachedGateway) FindPlaylistByURI(
+	ctx context.Context,
+	uri string,
+) (beer.Playlist, error) {
+	resolver, ok := c.gateway.(beer.PlaylistURIResolver)
+	if !ok {
+		return beer.Playlist{}, ErrPlaylistNotFound
+	}
+
+	key := fmt.Sprintf("spotify:playlist-uri:%s", uri)
+
+	return c.get(ctx, key, func(ctx context.Context) (beer.Playlist, error) {
+		return resolver.FindPlaylistByURI(ctx, uri)
+	})
+}
+
+// CheckHealth checks the decorated gateway, when it can be checked. Stale
+// entries keep being served while it fails.
+func (c *CachedGateway) CheckHealth(ctx context.Context) error {
+	if checker, ok := c.gateway.(interface{ CheckHealth(context.Context) error }); ok {
+		return checker.CheckHealth(ctx)
 	}
+	return nil
+}
+
+// Close stops the background refresh workers and waits for them to finish.
+func (c *CachedGateway) Close() {
+	c.mu.Lock()
+	if c.closed || c.refreshes == nil {
+		c.closed = true
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	close(c.refreshes)
+	c.mu.Unlock()
+
+	c.wg.Wait()
+}
+
+// get serves key from the cache according to its age, calling fetch
+// when the entry is missing or past the hard TTL, within a span recording
+// how fresh the answer was.
+func (c *CachedGateway) get(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) (beer.Playlist, error),
+) (beer.Playlist, error) {
+	ctx, span := tracing.Start(ctx, "CachedGateway.get", tracing.String("cache.key", key))
+	defer span.End()
 
-	playlist, err := c.gateway.FindPlaylistByStyle(ctx, styleName)
+	playlist, err := c.lookup(ctx, key, fetch)
 	if err != nil {
+		if !errors.Is(err, beer.ErrMusicNotFound) {
+			span.RecordError(err)
+		}
 		return beer.Playlist{}, err
 	}
 
-	c.cache.SetWithTTL(key, playlist, c.ttl)
+	span.SetAttributes(tracing.String("cache.freshness", string(playlist.Freshness)))
 	return playlist, nil
 }
+
+func (c *CachedGateway) lookup(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) (beer.Playlist, error),
+) (beer.Playlist, error) {
+	ttls := c.ttls.Load()
+	cached, found := c.cache.Get(key)
+	age := time.Since(cached.FetchedAt)
+
+	if found && age < ttls.softTTL {
+		cached.Freshness = beer.FreshnessFresh
+		return cached, nil
+	}
+
+	if found && age < ttls.hardTTL {
+		c.scheduleRefresh(ctx, key, fetch)
+		cached.Freshness = beer.FreshnessStale
+		return cached, nil
+	}
+
+	if _, notFound := c.cache.Get(notFoundKey(key)); notFound {
+		return beer.Playlist{}, ErrPlaylistNotFound
+	}
+
+	playlist, err := c.load(ctx, key, fetch)
+	if err != nil {
+		// Not found is an answer, not an outage: never mask it
+		if found && !errors.Is(err, beer.ErrMusicNotFound) {
+			logging.FromContext(ctx).Warn("serving stale playlist after provider error",
+				"key", key, "age", age, "error", err)
+			cached.Freshness = beer.FreshnessStaleIfError
+			return cached, nil
+		}
+		return beer.Playlist{}, err
+	}
+
+	return playlist, nil
+}
+
+// load calls the provider and stores the result, or the not-found
+// answer. Concurrent loads of the same key share a single provider call.
+func (c *CachedGateway) load(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) (beer.Playlist, error),
+) (beer.Playlist, error) {
+	playlist, err, _ := c.flight.Do(ctx, key, func(ctx context.Context) (beer.Playlist, error) {
+		playlist, err := fetch(ctx)
+		ttls := c.ttls.Load()
+		if errors.Is(err, beer.ErrMusicNotFound) {
+			c.cache.Delete(key)
+			if ttls.negativeTTL > 0 {
+				c.cache.Set(notFoundKey(key), beer.Playlist{}, ttls.negativeTTL)
+				c.flush()
+			}
+			return beer.Playlist{}, err
+		}
+		if err != nil {
+			return beer.Playlist{}, err
+		}
+
+		playlist.FetchedAt = time.Now()
+		playlist.Freshness = beer.FreshnessFresh
+
+		// Make the entry visible before releasing the waiting callers,
+		// so callers arriving right after do not start another fetch
+		c.cache.Set(key, playlist, ttls.hardTTL+ttls.maxStale)
+		c.cache.Delete(notFoundKey(key))
+		c.flush()
+		return playlist, nil
+	})
+
+	return playlist, err
+}
+
+// flush makes buffered writes visible, for backends that buffer them.
+func (c *CachedGateway) flush() {
+	if buffered, ok := c.cache.(interface{ Wait() }); ok {
+		buffered.Wait()
+	}
+}
+
+// scheduleRefresh queues a background load of key, unless one is
+// already pending or the queue is full.
+func (c *CachedGateway) scheduleRefresh(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) (beer.Playlist, error),
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refreshes == nil || c.closed {
+		return
+	}
+
+	if _, pending := c.refreshing[key]; pending {
+		return
+	}
+
+	job := refreshJob{
+		// Keep request values (provider selection, style) but not its
+		// cancellation: the refresh outlives the request.
+		ctx:   context.WithoutCancel(ctx),
+		key:   key,
+		fetch: fetch,
+	}
+
+	select {
+	case c.refreshes <- job:
+		c.refreshing[key] = struct{}{}
+	default:
+		// Queue full: the next request will try again
+	}
+}
+
+func (c *CachedGateway) refreshWorker() {
+	defer c.wg.Done()
+
+	for job := range c.refreshes {
+		_, err := c.load(job.ctx, job.key, job.fetch)
+		if err != nil && !errors.Is(err, beer.ErrMusicNotFound) {
+			logging.FromContext(job.ctx).Warn("background playlist refresh failed",
+				"key", job.key, "error", err)
+		}
+
+		c.mu.Lock()
+		delete(c.refreshing, job.key)
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,642 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/infrastructure/cache"
+	"karhub-beer-machine/internal/infrastructure/cache/redistest"
+	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
+)
+
+/*
+	Provider mock
+*/
+
+type countingProvider struct {
+	mu      sync.Mutex
+	calls   int
+	version int
+	err     error
+	called  chan struct{}
+}
+
+func newCountingProvider() *countingProvider {
+	return &countingProvider{called: make(chan struct{}, 16)}
+}
+
+func (p *countingProvider) FindPlaylistByStyle(
+	_ context.Context,
+	styleName string,
+) (beer.Playlist, error) {
+	p.mu.Lock()
+	p.calls++
+	version, err := p.version, p.err
+	p.mu.Unlock()
+
+	p.called <- struct{}{}
+
+	if err != nil {
+		return beer.Playlist{}, err
+	}
+
+	return beer.Playlist{
+		Name:        styleName,
+		TotalTracks: version,
+	}, nil
+}
+
+func (p *countingProvider) set(version int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.version = version
+	p.err = err
+}
+
+func (p *countingProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+func newPlaylistCache(t *testing.T) *cache.RistrettoCache[string, beer.Playlist] {
+	t.Helper()
+
+	c, err := cache.NewRistrettoCache[string, beer.Playlist](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	return c
+}
+
+/*
+	TESTS
+*/
+
+const (
+	softTTL  = 50 * time.Millisecond
+	hardTTL  = 250 * time.Millisecond
+	maxStale = time.Second
+)
+
+func TestCachedGateway_FreshHit(t *testing.T) {
+	provider := newCountingProvider()
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(provider, playlistCache, time.Minute)
+	defer gateway.Close()
+
+	for i := 0; i < 3; i++ {
+		playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		playlistCache.Wait()
+
+		if playlist.Freshness != beer.FreshnessFresh {
+			t.Errorf("expected fresh playlist, got %s", playlist.Freshness)
+		}
+
+		if playlist.FetchedAt.IsZero() {
+			t.Errorf("expected FetchedAt to be set")
+		}
+	}
+
+	if got := provider.callCount(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCachedGateway_StaleWhileRevalidate(t *testing.T) {
+	provider := newCountingProvider()
+	provider.set(1, nil)
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(
+		provider,
+		playlistCache,
+		softTTL,
+		spotifyinfra.WithStaleWhileRevalidate(hardTTL),
+	)
+	defer gateway.Close()
+
+	if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	playlistCache.Wait()
+	<-provider.called
+
+	provider.set(2, nil)
+	time.Sleep(softTTL + 10*time.Millisecond)
+
+	// Past the soft TTL: old data is served immediately...
+	start := time.Now()
+	playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if playlist.Freshness != beer.FreshnessStale || playlist.TotalTracks != 1 {
+		t.Fatalf("expected stale version 1, got %s version %d", playlist.Freshness, playlist.TotalTracks)
+	}
+
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("expected stale playlist to be served immediately, took %v", elapsed)
+	}
+
+	// ...while a background worker refreshes it
+	select {
+	case <-provider.called:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a background refresh")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		playlistCache.Wait()
+		playlist, _ = gateway.FindPlaylistByStyle(context.Background(), "IPA")
+		if playlist.Freshness == beer.FreshnessFresh && playlist.TotalTracks == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected refreshed version 2, got %s version %d", playlist.Freshness, playlist.TotalTracks)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCachedGateway_PastHardTTL(t *testing.T) {
+	upstreamErr := errors.New("spotify down")
+
+	tests := []struct {
+		name          string
+		warm          bool
+		upstreamErr   error
+		wantErr       bool
+		wantFreshness beer.Freshness
+		wantVersion   int
+	}{
+		{
+			name:          "upstream recovers with new data",
+			warm:          true,
+			wantFreshness: beer.FreshnessFresh,
+			wantVersion:   2,
+		},
+		{
+			name:          "upstream fails serves stale data",
+			warm:          true,
+			upstreamErr:   upstreamErr,
+			wantFreshness: beer.FreshnessStaleIfError,
+			wantVersion:   1,
+		},
+		{
+			name:        "upstream fails without cached data",
+			warm:        false,
+			upstreamErr: upstreamErr,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newCountingProvider()
+			provider.set(1, nil)
+			playlistCache := newPlaylistCache(t)
+
+			gateway := spotifyinfra.NewCachedSpotifyGateway(
+				provider,
+				playlistCache,
+				softTTL,
+				spotifyinfra.WithStaleWhileRevalidate(softTTL),
+				spotifyinfra.WithStaleIfError(maxStale),
+			)
+			defer gateway.Close()
+
+			if tt.warm {
+				if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				playlistCache.Wait()
+				time.Sleep(softTTL + 10*time.Millisecond)
+			}
+
+			provider.set(2, tt.upstreamErr)
+
+			playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+
+			if tt.wantErr {
+				if !errors.Is(err, tt.upstreamErr) {
+					t.Fatalf("expected %v, got %v", tt.upstreamErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if playlist.Freshness != tt.wantFreshness {
+				t.Errorf("expected freshness %s, got %s", tt.wantFreshness, playlist.Freshness)
+			}
+
+			if playlist.TotalTracks != tt.wantVersion {
+				t.Errorf("expected version %d, got %d", tt.wantVersion, playlist.TotalTracks)
+			}
+		})
+	}
+}
+
+/*
+	Blocking provider mock
+*/
+
+type blockingProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+	ctxErr  atomic.Value
+}
+
+func (p *blockingProvider) FindPlaylistByStyle(
+	ctx context.Context,
+	styleName string,
+) (beer.Playlist, error) {
+	p.calls.Add(1)
+	<-p.release
+
+	if err := ctx.Err(); err != nil {
+		p.ctxErr.Store(err)
+		return beer.Playlist{}, err
+	}
+
+	return beer.Playlist{Name: styleName}, nil
+}
+
+func TestCachedGateway_ConcurrentMissesShareOneFetch(t *testing.T) {
+	const callers = 50
+
+	provider := &blockingProvider{release: make(chan struct{})}
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(provider, playlistCache, time.Minute)
+	defer gateway.Close()
+
+	// One caller gives up while the fetch is in flight
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := gateway.FindPlaylistByStyle(cancelledCtx, "IPA")
+		cancelledErr <- err
+	}()
+
+	for provider.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+			if err == nil && playlist.Name != "IPA" {
+				err = errors.New("unexpected playlist " + playlist.Name)
+			}
+			errs <- err
+		}()
+	}
+
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled for the cancelled caller, got %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if got := provider.calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+
+	if err := provider.ctxErr.Load(); err != nil {
+		t.Errorf("shared fetch was cancelled: %v", err)
+	}
+
+	// The shared result is cached for later callers too
+	if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := provider.calls.Load(); got != 1 {
+		t.Errorf("expected cached playlist, got %d upstream calls", got)
+	}
+}
+
+func TestCachedGateway_OnCatalogChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		change       beer.CatalogChange
+		wantPrefetch string
+		wantDropped  string
+		wantKept     string
+	}{
+		{
+			name:         "create prefetches the new style",
+			change:       beer.CatalogChange{Kind: beer.CatalogStyleCreated, StyleID: "9", Name: "Stout"},
+			wantPrefetch: "Stout",
+			wantKept:     "IPA",
+		},
+		{
+			name: "rename drops the old name and prefetches the new one",
+			change: beer.CatalogChange{
+				Kind: beer.CatalogStyleUpdated, StyleID: "1", Name: "Imperial IPA", PreviousName: "IPA",
+			},
+			wantPrefetch: "Imperial IPA",
+			wantDropped:  "IPA",
+		},
+		{
+			name: "update without rename keeps the entry",
+			change: beer.CatalogChange{
+				Kind: beer.CatalogStyleUpdated, StyleID: "1", Name: "IPA", PreviousName: "IPA",
+			},
+			wantKept: "IPA",
+		},
+		{
+			name:        "delete drops the entry",
+			change:      beer.CatalogChange{Kind: beer.CatalogStyleDeleted, StyleID: "1", PreviousName: "IPA"},
+			wantDropped: "IPA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newCountingProvider()
+			playlistCache := newPlaylistCache(t)
+
+			gateway := spotifyinfra.NewCachedSpotifyGateway(provider, playlistCache, time.Minute)
+			defer gateway.Close()
+
+			if err := gateway.Warm(context.Background(), "IPA"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			<-provider.called
+
+			gateway.OnCatalogChange(context.Background(), tt.change)
+
+			if tt.wantPrefetch != "" {
+				select {
+				case <-provider.called:
+				case <-time.After(time.Second):
+					t.Fatalf("expected %q to be prefetched", tt.wantPrefetch)
+				}
+
+				// Wait for the prefetched entry to be stored
+				deadline := time.Now().Add(time.Second)
+				for {
+					playlistCache.Wait()
+					if _, found := playlistCache.Get("spotify:playlist:" + tt.wantPrefetch); found {
+						break
+					}
+					if time.Now().After(deadline) {
+						t.Fatalf("expected %q to be cached", tt.wantPrefetch)
+					}
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			playlistCache.Wait()
+			calls := provider.callCount()
+
+			if tt.wantPrefetch != "" {
+				if _, err := gateway.FindPlaylistByStyle(context.Background(), tt.wantPrefetch); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := provider.callCount(); got != calls {
+					t.Errorf("expected prefetched %q to be served from cache", tt.wantPrefetch)
+				}
+			}
+
+			if tt.wantKept != "" {
+				if _, found := playlistCache.Get("spotify:playlist:" + tt.wantKept); !found {
+					t.Errorf("expected %q to stay cached", tt.wantKept)
+				}
+			}
+
+			if tt.wantDropped != "" {
+				if _, found := playlistCache.Get("spotify:playlist:" + tt.wantDropped); found {
+					t.Errorf("expected %q to be invalidated", tt.wantDropped)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedGateway_WarmSkipsFreshEntries(t *testing.T) {
+	provider := newCountingProvider()
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(provider, playlistCache, time.Minute)
+	defer gateway.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := gateway.Warm(context.Background(), "IPA"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := provider.callCount(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCachedGateway_NegativeCaching(t *testing.T) {
+	const negativeTTL = 50 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		warm      bool
+		wait      time.Duration
+		wantCalls int
+	}{
+		{
+			name:      "not found is cached",
+			wantCalls: 1,
+		},
+		{
+			name:      "not found expires after the negative TTL",
+			wait:      negativeTTL + 20*time.Millisecond,
+			wantCalls: 2,
+		},
+		{
+			name:      "not found replaces a stale entry",
+			warm:      true,
+			wait:      softTTL + 10*time.Millisecond,
+			wantCalls: 2, // the warm-up call plus a single not-found call
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newCountingProvider()
+			playlistCache := newPlaylistCache(t)
+
+			gateway := spotifyinfra.NewCachedSpotifyGateway(
+				provider,
+				playlistCache,
+				softTTL,
+				spotifyinfra.WithStaleIfError(maxStale),
+				spotifyinfra.WithNegativeTTL(negativeTTL),
+			)
+			defer gateway.Close()
+
+			if tt.warm {
+				if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				playlistCache.Wait()
+				time.Sleep(tt.wait)
+			}
+
+			provider.set(0, spotifyinfra.ErrPlaylistNotFound)
+
+			if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); !errors.Is(err, beer.ErrMusicNotFound) {
+				t.Fatalf("expected ErrMusicNotFound, got %v", err)
+			}
+
+			if !tt.warm {
+				time.Sleep(tt.wait)
+			}
+
+			_, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+			if !errors.Is(err, beer.ErrMusicNotFound) {
+				t.Fatalf("expected ErrMusicNotFound, got %v", err)
+			}
+
+			if got := provider.callCount(); got != tt.wantCalls {
+				t.Errorf("expected %d upstream calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestCachedGateway_NegativeEntryClearedByInvalidate(t *testing.T) {
+	provider := newCountingProvider()
+	provider.set(0, spotifyinfra.ErrPlaylistNotFound)
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(
+		provider,
+		playlistCache,
+		time.Minute,
+		spotifyinfra.WithNegativeTTL(time.Minute),
+	)
+	defer gateway.Close()
+
+	if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	gateway.Invalidate("IPA")
+	provider.set(1, nil)
+
+	playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if playlist.TotalTracks != 1 {
+		t.Errorf("expected playlist version 1, got %d", playlist.TotalTracks)
+	}
+}
+
+func TestCachedGateway_SharedRedisBackend(t *testing.T) {
+	server := redistest.NewServer()
+	defer server.Close()
+
+	client := cache.NewRedisClient(server.Addr())
+	defer client.Close()
+
+	provider := newCountingProvider()
+	provider.set(7, nil)
+
+	// Two API replicas sharing one Redis
+	replicaA := spotifyinfra.NewCachedSpotifyGateway(
+		provider,
+		cache.NewRedisCache[string](client, spotifyinfra.PlaylistCodec{}, "karhub:"),
+		time.Minute,
+	)
+	defer replicaA.Close()
+
+	replicaB := spotifyinfra.NewCachedSpotifyGateway(
+		provider,
+		cache.NewRedisCache[string](client, spotifyinfra.PlaylistCodec{}, "karhub:"),
+		time.Minute,
+	)
+	defer replicaB.Close()
+
+	if _, err := replicaA.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	playlist, err := replicaB.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if playlist.TotalTracks != 7 || playlist.Freshness != beer.FreshnessFresh {
+		t.Errorf("expected fresh version 7 from the shared cache, got %s version %d", playlist.Freshness, playlist.TotalTracks)
+	}
+
+	if got := provider.callCount(); got != 1 {
+		t.Errorf("expected 1 upstream call across replicas, got %d", got)
+	}
+}
+
+func TestCachedGateway_SetTTLs(t *testing.T) {
+	provider := newCountingProvider()
+	provider.set(1, nil)
+	playlistCache := newPlaylistCache(t)
+
+	gateway := spotifyinfra.NewCachedSpotifyGateway(provider, playlistCache, time.Minute)
+	defer gateway.Close()
+
+	if _, err := gateway.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	playlistCache.Wait()
+	<-provider.called
+
+	// Cached for a minute, but the new soft TTL applies at once
+	gateway.SetTTLs(softTTL, spotifyinfra.WithStaleWhileRevalidate(time.Minute))
+	time.Sleep(softTTL + 10*time.Millisecond)
+
+	playlist, err := gateway.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if playlist.Freshness != beer.FreshnessStale {
+		t.Fatalf("expected stale playlist under the new TTL, got %s", playlist.Freshness)
+	}
+
+	select {
+	case <-provider.called:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a background refresh")
+	}
+}
@@ -0,0 +1,44 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"karhub-beer-machine/internal/application/beer"
+)
+
+// playlistCodecVersion is bumped whenever beer.Playlist changes in a way
+// older entries cannot be decoded into; those entries then read as misses.
+const playlistCodecVersion = 1
+
+// PlaylistCodec serializes cached playlists for byte-oriented cache
+// backends (e.g. Redis) as versioned JSON.
+type PlaylistCodec struct{}
+
+type playlistEnvelope struct {
+	Version  int           `json:"v"`
+	Playlist beer.Playlist `json:"playlist"`
+}
+
+func (PlaylistCodec) Marshal(playlist beer.Playlist) ([]byte, error) {
+	return json.Marshal(playlistEnvelope{
+		Version:  playlistCodecVersion,
+		Playlist: playlist,
+	})
+}
+
+func (PlaylistCodec) Unmarshal(data []byte) (beer.Playlist, error) {
+	var envelope playlistEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return beer.Playlist{}, err
+	}
+
+	if envelope.Version != playlistCodecVersion {
+		return beer.Playlist{}, fmt.Errorf(
+			"unsupported cached playlist version %d",
+			envelope.Version,
+		)
+	}
+
+	return envelope.Playlist, nil
+}
@@ -0,0 +1,85 @@
+package spotify_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
+)
+
+func TestPlaylistCodec(t *testing.T) {
+	playlist := beer.Playlist{
+		Name:        "IPA Party",
+		Description: "Hoppy tunes",
+		Owner:       "KarHub",
+		Link:        "https://open.spotify.com/playlist/ipa1",
+		Images:      []beer.Image{{URL: "https://img/ipa.jpg", Width: 640, Height: 640}},
+		Provider:    spotifyinfra.ProviderName,
+		Freshness:   beer.FreshnessFresh,
+		FetchedAt:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		TotalTracks: 2,
+		Tracks: []beer.Track{
+			{
+				Name:     "Hop Song",
+				Artist:   "The Brewers",
+				Artists:  []string{"The Brewers", "Malt"},
+				Album:    "Bitter",
+				Duration: 3 * time.Minute,
+				Explicit: true,
+				Link:     "https://open.spotify.com/track/t1",
+			},
+		},
+	}
+
+	codec := spotifyinfra.PlaylistCodec{}
+
+	tests := []struct {
+		name    string
+		data    func() []byte
+		wantErr bool
+	}{
+		{
+			name: "round trip",
+			data: func() []byte {
+				data, err := codec.Marshal(playlist)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return data
+			},
+		},
+		{
+			name:    "unknown version",
+			data:    func() []byte { return []byte(`{"v":99,"playlist":{"Name":"IPA Party"}}`) },
+			wantErr: true,
+		},
+		{
+			name:    "corrupt data",
+			data:    func() []byte { return []byte(`{"v":1,"playl`) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codec.Unmarshal(tt.data())
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, playlist) {
+				t.Errorf("expected %+v, got %+v", playlist, got)
+			}
+		})
+	}
+}
@@ -3,35 +3,145 @@ package spotify
 import (
 	"context"
 	"errors"
-	"os"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 
 	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/tracing"
 )
 
-// Client implements beer.SpotifyGateway using Spotify Web API.
+// ProviderName identifies Spotify as a music provider.
+const ProviderName = "spotify"
+
+// DefaultTrackLimit is the number of tracks returned per playlist
+// when no limit is configured.
+const DefaultTrackLimit = 10
+
+// maxPageSize is the largest page Spotify serves for playlist items.
+const maxPageSize = 50
+
+// Client implements beer.MusicProvider using Spotify Web API.
 type Client struct {
-	client *spotify.Client
+	client       *spotify.Client
+	tokens       oauth2.TokenSource
+	scheduler    *Scheduler
+	trackLimit   int
+	skipExplicit bool
+}
+
+// clientConfig holds the settings used to build a Client.
+type clientConfig struct {
+	clientID     string
+	clientSecret string
+	baseURL      string
+	tokenURL     string
+	scheduler    *Scheduler
+	observer     CallObserver
+	trackLimit   int
+	skipExplicit bool
+}
+
+// ClientOption customizes how NewSpotifyClient builds the client.
+type ClientOption func(*clientConfig)
+
+// WithCredentials sets the client credentials. Without them,
+// NewSpotifyClient fails.
+func WithCredentials(clientID, clientSecret string) ClientOption {
+	return func(c *clientConfig) {
+		c.clientID = clientID
+		c.clientSecret = clientSecret
+	}
+}
+
+// WithBaseURL overrides the Spotify Web API base URL.
+// The URL must end with a slash (e.g. "https://api.spotify.com/v1/").
+func WithBaseURL(url string) ClientOption {
+	return func(c *clientConfig) {
+		c.baseURL = url
+	}
+}
+
+// WithTokenURL overrides the Spotify Accounts token endpoint.
+func WithTokenURL(url string) ClientOption {
+	return func(c *clientConfig) {
+		c.tokenURL = url
+	}
+}
+
+// WithScheduler sets the Scheduler that paces every Web API call.
+// Sharing one Scheduler between clients makes them share the rate
+// limit and the Retry-After backoff.
+func WithScheduler(scheduler *Scheduler) ClientOption {
+	return func(c *clientConfig) {
+		c.scheduler = scheduler
+	}
+}
+
+// WithCallObserver reports the outcome and latency of every Web API
+// call to observer.
+func WithCallObserver(observer CallObserver) ClientOption {
+	return func(c *clientConfig) {
+		c.observer = observer
+	}
+}
+
+// WithTrackLimit sets how many tracks are returned per playlist.
+// Tracks are fetched page by page until the limit is reached.
+func WithTrackLimit(limit int) ClientOption {
+	return func(c *clientConfig) {
+		c.trackLimit = limit
+	}
+}
+
+// WithoutExplicitTracks filters out tracks flagged as explicit.
+// Filtered tracks do not count towards the track limit.
+func WithoutExplicitTracks() ClientOption {
+	return func(c *clientConfig) {
+		c.skipExplicit = true
+	}
 }
 
 // NewSpotifyClient creates a Spotify client using Client Credentials flow,
 // following the official example from the spotify/v2 repository.
-func NewSpotifyClient(ctx context.Context) (*Client, error) {
-	clientID := os.Getenv("SPOTIFY_CLIENT_ID")
-	clientSecret := os.Getenv("SPOTIFY_CLIENT_SECRET")
+func NewSpotifyClient(ctx context.Context, opts ...ClientOption) (*Client, error) {
+	cfg := clientConfig{
+		tokenURL:   spotifyauth.TokenURL,
+		trackLimit: DefaultTrackLimit,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.scheduler == nil {
+		cfg.scheduler = NewScheduler(
+			DefaultRequestsPerSecond,
+			DefaultBurst,
+			DefaultMaxQueueWait,
+		)
+	}
+
+	if cfg.trackLimit <= 0 {
+		cfg.trackLimit = DefaultTrackLimit
+	}
 
-	if clientID == "" || clientSecret == "" {
+	if cfg.clientID == "" || cfg.clientSecret == "" {
 		return nil, errors.New("spotify credentials not set")
 	}
 
 	// OAuth2 client credentials configuration
 	config := &clientcredentials.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		TokenURL:     spotifyauth.TokenURL,
+		ClientID:     cfg.clientID,
+		ClientSecret: cfg.clientSecret,
+		TokenURL:     cfg.tokenURL,
 	}
 
 	// Retrieve token
@@ -40,16 +150,42 @@ func NewSpotifyClient(ctx context.Context) (*Client, error) {
 		return nil, err
 	}
 
-	// Create HTTP client authorized with the token
-	httpClient := spotifyauth.New().Client(ctx, token)
+	// Create HTTP client authorized with the token, renewing it
+	// against the same token endpoint once it expires. Web API calls
+	// are paced by the scheduler, which also owns 429 handling, so
+	// the SDK's blocking retry stays disabled.
+	var transport http.RoundTripper = &rateLimitedTransport{
+		base:      &tracingTransport{base: http.DefaultTransport},
+		scheduler: cfg.scheduler,
+	}
+	if cfg.observer != nil {
+		transport = &observedTransport{base: transport, observer: cfg.observer}
+	}
+
+	tokens := oauth2.ReuseTokenSource(token, config.TokenSource(ctx))
+
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: tokens,
+			Base:   transport,
+		},
+	}
+
+	var spotifyOpts []spotify.ClientOption
+	if cfg.baseURL != "" {
+		spotifyOpts = append(spotifyOpts, spotify.WithBaseURL(cfg.baseURL))
+	}
 
 	// Create Spotify client
-	client := spotify.New(
-		httpClient,
-		spotify.WithRetry(true),
-	)
+	client := spotify.New(httpClient, spotifyOpts...)
 
-	return &Client{client: client}, nil
+	return &Client{
+		client:       client,
+		tokens:       tokens,
+		scheduler:    cfg.scheduler,
+		trackLimit:   cfg.trackLimit,
+		skipExplicit: cfg.skipExplicit,
+	}, nil
 }
 
 // FindPlaylistByStyle searches for a public playlist containing the beer style name.
@@ -57,7 +193,19 @@ func (c *Client) FindPlaylistByStyle(
 	ctx context.Context,
 	styleName string,
 ) (beer.Playlist, error) {
+	ctx, span := tracing.Start(ctx, "SpotifyClient.FindPlaylistByStyle",
+		tracing.String("beer.style", styleName))
+	defer span.End()
+
+	playlist, err := c.findPlaylistByStyle(ctx, styleName)
+	recordLookup(span, playlist, err)
+	return playlist, err
+}
 
+func (c *Client) findPlaylistByStyle(
+	ctx context.Context,
+	styleName string,
+) (beer.Playlist, error) {
 	results, err := c.client.Search(
 		ctx,
 		styleName,
@@ -65,7 +213,7 @@ func (c *Client) FindPlaylistByStyle(
 		spotify.Limit(1),
 	)
 	if err != nil {
-		return beer.Playlist{}, err
+		return beer.Playlist{}, mapError(err)
 	}
 
 	if results.Playlists == nil || len(results.Playlists.Playlists) == 0 {
@@ -75,37 +223,208 @@ func (c *Client) FindPlaylistByStyle(
 	pl := results.Playlists.Playlists[0]
 
 	playlist := beer.Playlist{
-		Name:   pl.Name,
-		Tracks: []beer.Track{},
+		Name:        pl.Name,
+		Description: pl.Description,
+		Owner:       pl.Owner.DisplayName,
+		Link:        pl.ExternalURLs["spotify"],
+		Images:      toImages(pl.Images),
+		Provider:    ProviderName,
+		TotalTracks: int(pl.Tracks.Total),
+		Tracks:      []beer.Track{},
+	}
+
+	tracks, err := c.fetchTracks(ctx, pl.ID)
+	if err != nil {
+		// Playlist without tracks is still acceptable
+		return playlist, nil
+	}
+
+	playlist.Tracks = tracks
+	return playlist, nil
+}
+
+// FindPlaylistByURI loads a playlist from a Spotify URI
+// ("spotify:playlist:<id>") or an open.spotify.com playlist link.
+func (c *Client) FindPlaylistByURI(
+	ctx context.Context,
+	uri string,
+) (beer.Playlist, error) {
+	ctx, span := tracing.Start(ctx, "SpotifyClient.FindPlaylistByURI",
+		tracing.String("spotify.playlist_uri", uri))
+	defer span.End()
+
+	playlist, err := c.findPlaylistByURI(ctx, uri)
+	recordLookup(span, playlist, err)
+	return playlist, err
+}
+
+func (c *Client) findPlaylistByURI(
+	ctx context.Context,
+	uri string,
+) (beer.Playlist, error) {
+	id, err := parsePlaylistURI(uri)
+	if err != nil {
+		return beer.Playlist{}, err
 	}
 
-	// Fetch playlist tracks (limited for performance)
-	tracks, err := c.client.GetPlaylistItems(
+	pl, err := c.client.GetPlaylist(
 		ctx,
-		pl.ID,
-		spotify.Limit(10),
+		id,
+		spotify.Fields("id,name,description,owner,images,external_urls,tracks.total"),
 	)
+	if err != nil {
+		var apiErr spotify.Error
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+			return beer.Playlist{}, ErrPlaylistNotFound
+		}
+		return beer.Playlist{}, mapError(err)
+	}
+
+	playlist := beer.Playlist{
+		Name:        pl.Name,
+		Description: pl.Description,
+		Owner:       pl.Owner.DisplayName,
+		Link:        pl.ExternalURLs["spotify"],
+		Images:      toImages(pl.Images),
+		Provider:    ProviderName,
+		TotalTracks: int(pl.Tracks.Total),
+		Tracks:      []beer.Track{},
+	}
+
+	tracks, err := c.fetchTracks(ctx, id)
 	if err != nil {
 		// Playlist without tracks is still acceptable
 		return playlist, nil
 	}
 
-	for _, item := range tracks.Items {
-		if item.Track.Track == nil {
-			continue
+	playlist.Tracks = tracks
+	return playlist, nil
+}
+
+// CheckHealth fails while Spotify calls wait out a Retry-After backoff.
+// It sends no request, so probes never spend the rate limit.
+func (c *Client) CheckHealth(context.Context) error {
+	if until := c.scheduler.BlockedUntil(); !until.IsZero() {
+		return fmt.Errorf("%w: backing off until %s", ErrRateLimited, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckToken fails when no valid access token can be obtained. The
+// token is reused until it expires, so this only reaches the Accounts
+// service to renew it.
+func (c *Client) CheckToken(context.Context) error {
+	_, err := c.tokens.Token()
+	return err
+}
+
+// parsePlaylistURI extracts the playlist ID from a Spotify URI or link.
+func parsePlaylistURI(uri string) (spotify.ID, error) {
+	if id, ok := strings.CutPrefix(uri, "spotify:playlist:"); ok && id != "" {
+		return spotify.ID(id), nil
+	}
+
+	u, err := url.Parse(uri)
+	if err == nil && u.Host == "open.spotify.com" {
+		if id, ok := strings.CutPrefix(u.Path, "/playlist/"); ok && id != "" {
+			return spotify.ID(id), nil
 		}
+	}
+
+	return "", ErrInvalidPlaylistURI
+}
+
+// fetchTracks pages through the playlist items until the track limit
+// is reached or the playlist is exhausted.
+func (c *Client) fetchTracks(
+	ctx context.Context,
+	playlistID spotify.ID,
+) ([]beer.Track, error) {
+	tracks := make([]beer.Track, 0, c.trackLimit)
+	offset := 0
 
-		track := beer.Track{
-			Name: item.Track.Track.Name,
-			Link: item.Track.Track.ExternalURLs["spotify"],
+	for len(tracks) < c.trackLimit {
+		page, err := c.client.GetPlaylistItems(
+			ctx,
+			playlistID,
+			spotify.Limit(min(c.trackLimit-len(tracks), maxPageSize)),
+			spotify.Offset(offset),
+		)
+		if err != nil {
+			if len(tracks) > 0 {
+				// Keep what was already fetched
+				return tracks, nil
+			}
+			return nil, mapError(err)
 		}
 
-		if len(item.Track.Track.Artists) > 0 {
-			track.Artist = item.Track.Track.Artists[0].Name
+		for _, item := range page.Items {
+			t := item.Track.Track
+			if t == nil {
+				continue
+			}
+
+			if c.skipExplicit && t.Explicit {
+				continue
+			}
+
+			if len(tracks) == c.trackLimit {
+				break
+			}
+
+			tracks = append(tracks, toTrack(t))
 		}
 
-		playlist.Tracks = append(playlist.Tracks, track)
+		offset += len(page.Items)
+		if len(page.Items) == 0 || page.Next == "" {
+			break
+		}
 	}
 
-	return playlist, nil
+	return tracks, nil
+}
+
+func toTrack(t *spotify.FullTrack) beer.Track {
+	track := beer.Track{
+		Name:        t.Name,
+		Artists:     make([]string, 0, len(t.Artists)),
+		Album:       t.Album.Name,
+		AlbumImages: toImages(t.Album.Images),
+		Duration:    time.Duration(t.Duration) * time.Millisecond,
+		Explicit:    t.Explicit,
+		PreviewURL:  t.PreviewURL,
+		Link:        t.ExternalURLs["spotify"],
+	}
+
+	for _, artist := range t.Artists {
+		track.Artists = append(track.Artists, artist.Name)
+	}
+
+	if len(track.Artists) > 0 {
+		track.Artist = track.Artists[0]
+	}
+
+	return track
+}
+
+func toImages(images []spotify.Image) []beer.Image {
+	out := make([]beer.Image, 0, len(images))
+	for _, img := range images {
+		out = append(out, beer.Image{
+			URL:    img.URL,
+			Width:  int(img.Width),
+			Height: int(img.Height),
+		})
+	}
+	return out
+}
+
+// mapError translates Spotify API errors into package errors.
+func mapError(err error) error {
+	var apiErr spotify.Error
+	if errors.As(err, &apiErr) &&
+		apiErr.Status == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return err
 }
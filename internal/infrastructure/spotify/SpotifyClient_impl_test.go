@@ -0,0 +1,563 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/tracing"
+	"karhub-beer-machine/internal/infrastructure/otlp"
+	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
+	"karhub-beer-machine/internal/infrastructure/spotify/spotifytest"
+)
+
+/*
+	Helpers
+*/
+
+func newFakeServer(t *testing.T) *spotifytest.Server {
+	t.Helper()
+
+	server := spotifytest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddPlaylist(spotifytest.Playlist{
+		ID:          "ipa1",
+		Name:        "IPA Party",
+		Description: "Hoppy tunes",
+		Owner:       "Karhub",
+		ImageURL:    "https://img.test/ipa.jpg",
+		Tracks: []spotifytest.Track{
+			{
+				ID:         "t1",
+				Name:       "Hoppy Song",
+				Artists:    []string{"The Hops", "Malt"},
+				Album:      "Dry Hopped",
+				AlbumArt:   "https://img.test/dry-hopped.jpg",
+				DurationMs: 215000,
+				PreviewURL: "https://p.test/t1.mp3",
+			},
+			{
+				ID:       "t2",
+				Name:     "Bitter End",
+				Artists:  []string{"Bitterness"},
+				Explicit: true,
+			},
+		},
+	})
+
+	return server
+}
+
+func newClient(
+	t *testing.T,
+	server *spotifytest.Server,
+	opts ...spotifyinfra.ClientOption,
+) *spotifyinfra.Client {
+	t.Helper()
+
+	opts = append([]spotifyinfra.ClientOption{
+		spotifyinfra.WithCredentials(spotifytest.ClientID, spotifytest.ClientSecret),
+		spotifyinfra.WithBaseURL(server.BaseURL()),
+		spotifyinfra.WithTokenURL(server.TokenURL()),
+	}, opts...)
+
+	client, err := spotifyinfra.NewSpotifyClient(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return client
+}
+
+func rateLimitedScenario(retryAfter time.Duration) spotifytest.Scenario {
+	return spotifytest.Scenario{
+		RateLimited: 1,
+		RetryAfter:  retryAfter,
+	}
+}
+
+/*
+	TESTS
+*/
+
+func TestNewSpotifyClient(t *testing.T) {
+	server := newFakeServer(t)
+
+	tests := []struct {
+		name         string
+		clientID     string
+		clientSecret string
+		wantErr      bool
+	}{
+		{
+			name:         "valid credentials",
+			clientID:     spotifytest.ClientID,
+			clientSecret: spotifytest.ClientSecret,
+			wantErr:      false,
+		},
+		{
+			name:         "invalid credentials",
+			clientID:     spotifytest.ClientID,
+			clientSecret: "wrong",
+			wantErr:      true,
+		},
+		{
+			name:         "missing credentials",
+			clientID:     "",
+			clientSecret: "",
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := spotifyinfra.NewSpotifyClient(
+				context.Background(),
+				spotifyinfra.WithCredentials(tt.clientID, tt.clientSecret),
+				spotifyinfra.WithBaseURL(server.BaseURL()),
+				spotifyinfra.WithTokenURL(server.TokenURL()),
+			)
+
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_FindPlaylistByStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		style        string
+		scenario     spotifytest.Scenario
+		timeout      time.Duration
+		wantPlaylist string
+		wantTracks   int
+		wantErr      bool
+		wantErrIs    error
+	}{
+		{
+			name:         "playlist found",
+			style:        "IPA",
+			wantPlaylist: "IPA Party",
+			wantTracks:   2,
+		},
+		{
+			name:      "no playlist for style",
+			style:     "Dunkel",
+			wantErr:   true,
+			wantErrIs: spotifyinfra.ErrPlaylistNotFound,
+		},
+		{
+			name:      "empty search results",
+			style:     "IPA",
+			scenario:  spotifytest.Scenario{Empty: true},
+			wantErr:   true,
+			wantErrIs: spotifyinfra.ErrPlaylistNotFound,
+		},
+		{
+			name:  "rate limited then recovers",
+			style: "IPA",
+			scenario: spotifytest.Scenario{
+				RateLimited: 1,
+				RetryAfter:  time.Second,
+			},
+			wantPlaylist: "IPA Party",
+			wantTracks:   2,
+		},
+		{
+			name:      "rate limited beyond queue deadline",
+			style:     "IPA",
+			scenario:  rateLimitedScenario(time.Minute),
+			wantErr:   true,
+			wantErrIs: spotifyinfra.ErrRateLimited,
+		},
+		{
+			name:     "server error",
+			style:    "IPA",
+			scenario: spotifytest.Scenario{ServerErrors: 1},
+			wantErr:  true,
+		},
+		{
+			name:      "slow response exceeds deadline",
+			style:     "IPA",
+			scenario:  spotifytest.Scenario{Latency: time.Second},
+			timeout:   50 * time.Millisecond,
+			wantErr:   true,
+			wantErrIs: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			client := newClient(t, server)
+			server.SetScenario(tt.scenario)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			playlist, err := client.FindPlaylistByStyle(ctx, tt.style)
+
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if playlist.Name != tt.wantPlaylist {
+				t.Errorf("expected playlist %s, got %s", tt.wantPlaylist, playlist.Name)
+			}
+
+			if len(playlist.Tracks) != tt.wantTracks {
+				t.Errorf("expected %d tracks, got %d", tt.wantTracks, len(playlist.Tracks))
+			}
+		})
+	}
+}
+
+func TestClient_FindPlaylistByStyle_MapsTracks(t *testing.T) {
+	server := newFakeServer(t)
+	client := newClient(t, server)
+
+	playlist, err := client.FindPlaylistByStyle(context.Background(), "IPA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if playlist.Description != "Hoppy tunes" || playlist.Owner != "Karhub" {
+		t.Errorf("unexpected playlist metadata: %+v", playlist)
+	}
+
+	if playlist.TotalTracks != 2 {
+		t.Errorf("expected 2 total tracks, got %d", playlist.TotalTracks)
+	}
+
+	if len(playlist.Images) != 1 || playlist.Images[0].URL != "https://img.test/ipa.jpg" {
+		t.Errorf("unexpected playlist images: %+v", playlist.Images)
+	}
+
+	got := playlist.Tracks[0]
+
+	if got.Name != "Hoppy Song" {
+		t.Errorf("expected track Hoppy Song, got %s", got.Name)
+	}
+
+	if got.Artist != "The Hops" {
+		t.Errorf("expected artist The Hops, got %s", got.Artist)
+	}
+
+	if !reflect.DeepEqual(got.Artists, []string{"The Hops", "Malt"}) {
+		t.Errorf("unexpected artists %v", got.Artists)
+	}
+
+	if got.Album != "Dry Hopped" {
+		t.Errorf("expected album Dry Hopped, got %s", got.Album)
+	}
+
+	if len(got.AlbumImages) != 1 || got.AlbumImages[0].URL != "https://img.test/dry-hopped.jpg" {
+		t.Errorf("unexpected album images: %+v", got.AlbumImages)
+	}
+
+	if got.Duration != 215*time.Second {
+		t.Errorf("expected duration 3m35s, got %v", got.Duration)
+	}
+
+	if got.PreviewURL != "https://p.test/t1.mp3" {
+		t.Errorf("unexpected preview URL %s", got.PreviewURL)
+	}
+
+	if got.Link != "https://open.spotify.com/track/t1" {
+		t.Errorf("unexpected link %s", got.Link)
+	}
+
+	if !playlist.Tracks[1].Explicit {
+		t.Errorf("expected second track to be explicit")
+	}
+}
+
+func TestClient_FindPlaylistByStyle_TrackLimit(t *testing.T) {
+	tracks := make([]spotifytest.Track, 0, 120)
+	for i := range 120 {
+		tracks = append(tracks, spotifytest.Track{
+			ID:       fmt.Sprintf("t%d", i),
+			Name:     fmt.Sprintf("Song %d", i),
+			Artists:  []string{"Band"},
+			Explicit: i%2 == 1,
+		})
+	}
+
+	tests := []struct {
+		name          string
+		opts          []spotifyinfra.ClientOption
+		wantTracks    int
+		wantExplicit  bool
+		wantLastTrack string
+	}{
+		{
+			name:          "default limit",
+			wantTracks:    spotifyinfra.DefaultTrackLimit,
+			wantExplicit:  true,
+			wantLastTrack: "Song 9",
+		},
+		{
+			name:          "limit spanning several pages",
+			opts:          []spotifyinfra.ClientOption{spotifyinfra.WithTrackLimit(110)},
+			wantTracks:    110,
+			wantExplicit:  true,
+			wantLastTrack: "Song 109",
+		},
+		{
+			name:          "limit larger than playlist",
+			opts:          []spotifyinfra.ClientOption{spotifyinfra.WithTrackLimit(500)},
+			wantTracks:    120,
+			wantExplicit:  true,
+			wantLastTrack: "Song 119",
+		},
+		{
+			name: "explicit tracks filtered",
+			opts: []spotifyinfra.ClientOption{
+				spotifyinfra.WithTrackLimit(55),
+				spotifyinfra.WithoutExplicitTracks(),
+			},
+			wantTracks:    55,
+			wantExplicit:  false,
+			wantLastTrack: "Song 108",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := spotifytest.NewServer()
+			t.Cleanup(server.Close)
+			server.AddPlaylist(spotifytest.Playlist{
+				ID:     "long",
+				Name:   "Long Stout Session",
+				Tracks: tracks,
+			})
+
+			client := newClient(t, server, tt.opts...)
+
+			playlist, err := client.FindPlaylistByStyle(context.Background(), "Stout")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(playlist.Tracks) != tt.wantTracks {
+				t.Fatalf("expected %d tracks, got %d", tt.wantTracks, len(playlist.Tracks))
+			}
+
+			if playlist.TotalTracks != len(tracks) {
+				t.Errorf("expected %d total tracks, got %d", len(tracks), playlist.TotalTracks)
+			}
+
+			if last := playlist.Tracks[len(playlist.Tracks)-1].Name; last != tt.wantLastTrack {
+				t.Errorf("expected last track %s, got %s", tt.wantLastTrack, last)
+			}
+
+			if !tt.wantExplicit {
+				for _, track := range playlist.Tracks {
+					if track.Explicit {
+						t.Fatalf("unexpected explicit track %s", track.Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestClient_FindPlaylistByURI(t *testing.T) {
+	tests := []struct {
+		name         string
+		uri          string
+		wantPlaylist string
+		wantErrIs    error
+	}{
+		{
+			name:         "spotify uri",
+			uri:          "spotify:playlist:ipa1",
+			wantPlaylist: "IPA Party",
+		},
+		{
+			name:         "open.spotify.com link",
+			uri:          "https://open.spotify.com/playlist/ipa1?si=xyz",
+			wantPlaylist: "IPA Party",
+		},
+		{
+			name:      "unknown playlist",
+			uri:       "spotify:playlist:missing",
+			wantErrIs: spotifyinfra.ErrPlaylistNotFound,
+		},
+		{
+			name:      "not a playlist uri",
+			uri:       "spotify:track:t1",
+			wantErrIs: spotifyinfra.ErrInvalidPlaylistURI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			client := newClient(t, server)
+
+			playlist, err := client.FindPlaylistByURI(context.Background(), tt.uri)
+
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected error %v, got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if playlist.Name != tt.wantPlaylist {
+				t.Errorf("expected playlist %s, got %s", tt.wantPlaylist, playlist.Name)
+			}
+
+			if len(playlist.Tracks) != 2 || playlist.TotalTracks != 2 {
+				t.Errorf("expected 2 tracks, got %d (total %d)", len(playlist.Tracks), playlist.TotalTracks)
+			}
+		})
+	}
+}
+
+func TestClient_PropagatesTraceContext(t *testing.T) {
+	remote, _ := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	t.Run("without a tracer, forwards the caller's trace context", func(t *testing.T) {
+		server := newFakeServer(t)
+		client := newClient(t, server)
+
+		ctx := tracing.ContextWithRemoteSpanContext(context.Background(), remote)
+		if _, err := client.FindPlaylistByStyle(ctx, "IPA"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		headers := server.Headers("/v1/search")
+		if len(headers) == 0 {
+			t.Fatalf("expected a search request")
+		}
+
+		for _, h := range headers {
+			if got := h.Get(tracing.TraceParentHeader); got != remote.TraceParent() {
+				t.Fatalf("expected traceparent %q, got %q", remote.TraceParent(), got)
+			}
+		}
+	})
+
+	t.Run("with a tracer, sends a client span per request", func(t *testing.T) {
+		exporter := otlp.NewInMemoryExporter()
+		tracer := otlp.NewTracer(exporter)
+		tracing.SetDefault(tracer)
+		t.Cleanup(func() { tracing.SetDefault(nil) })
+
+		server := newFakeServer(t)
+		client := newClient(t, server)
+
+		ctx := tracing.ContextWithRemoteSpanContext(context.Background(), remote)
+		if _, err := client.FindPlaylistByStyle(ctx, "IPA"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := tracer.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		spans := make(map[tracing.SpanID]otlp.SpanData)
+		var lookup otlp.SpanData
+		for _, s := range exporter.Spans() {
+			spans[s.SpanContext.SpanID] = s
+			if s.Name == "SpotifyClient.FindPlaylistByStyle" {
+				lookup = s
+			}
+		}
+
+		if lookup.ParentSpanID != remote.SpanID || lookup.SpanContext.TraceID != remote.TraceID {
+			t.Fatalf("expected lookup span to continue the remote trace, got %+v", lookup)
+		}
+
+		headers := append(server.Headers("/v1/search"), server.Headers("/v1/playlists/ipa1/tracks")...)
+		if len(headers) != 2 {
+			t.Fatalf("expected 2 API requests, got %d", len(headers))
+		}
+
+		for _, h := range headers {
+			sc, ok := tracing.ParseTraceParent(h.Get(tracing.TraceParentHeader))
+			if !ok {
+				t.Fatalf("expected a valid traceparent, got %q", h.Get(tracing.TraceParentHeader))
+			}
+
+			span, found := spans[sc.SpanID]
+			if !found || span.Kind != tracing.SpanKindClient {
+				t.Fatalf("expected traceparent to name an exported client span, got %+v", span)
+			}
+			if span.ParentSpanID != lookup.SpanContext.SpanID {
+				t.Errorf("expected client span %q to be a child of the lookup span", span.Name)
+			}
+			if status, _ := span.Attribute("http.response.status_code"); status != 200 {
+				t.Errorf("expected status 200, got %v", status)
+			}
+		}
+	})
+}
+
+func TestClient_CheckHealth(t *testing.T) {
+	server := newFakeServer(t)
+	scheduler := spotifyinfra.NewScheduler(10, 10, time.Second)
+	client := newClient(t, server, spotifyinfra.WithScheduler(scheduler))
+
+	if err := client.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scheduler.Backoff(time.Minute)
+
+	if err := client.CheckHealth(context.Background()); !errors.Is(err, spotifyinfra.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited during a backoff, got %v", err)
+	}
+
+	// No probe may spend the rate limit
+	if got := server.Requests("/v1/search"); got != 0 {
+		t.Fatalf("expected no API request, got %d", got)
+	}
+}
+
+func TestClient_CheckToken(t *testing.T) {
+	server := newFakeServer(t)
+	client := newClient(t, server)
+
+	for range 3 {
+		if err := client.CheckToken(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The token fetched at startup is still valid
+	if got := server.Requests("/api/token"); got != 1 {
+		t.Fatalf("expected the token to be reused, got %d token requests", got)
+	}
+}
@@ -2,11 +2,15 @@ package spotify
 
 import (
 	"context"
+	"time"
 
 	"karhub-beer-machine/internal/application/beer"
 )
 
-// Stub implements beer.SpotifyGateway as a fallback.
+// StubProviderName identifies playlists served by the stub.
+const StubProviderName = "stub"
+
+// Stub implements beer.MusicProvider as a fallback for Spotify.
 type Stub struct{}
 
 // NewSpotifyStub creates a Spotify stub gateway.
@@ -19,12 +23,20 @@ func (s *Stub) FindPlaylistByStyle(
 	styleName string,
 ) (beer.Playlist, error) {
 	return beer.Playlist{
-		Name: styleName + " Playlist (stub)",
+		Name:        styleName + " Playlist (stub)",
+		Description: "Stub playlist for " + styleName,
+		Owner:       "Karhub",
+		Link:        "https://open.spotify.com",
+		Provider:    StubProviderName,
+		TotalTracks: 1,
 		Tracks: []beer.Track{
 			{
-				Name:   "Stub Song",
-				Artist: "Stub Artist",
-				Link:   "https://open.spotify.com",
+				Name:     "Stub Song",
+				Artist:   "Stub Artist",
+				Artists:  []string{"Stub Artist"},
+				Album:    "Stub Album",
+				Duration: 3 * time.Minute,
+				Link:     "https://open.spotify.com",
 			},
 		},
 	}, nil
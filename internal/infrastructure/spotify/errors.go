@@ -1,6 +1,20 @@
 package spotify
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"karhub-beer-machine/internal/application/beer"
+)
 
 // ErrPlaylistNotFound is returned when no playlist is found for a beer style.
-var ErrPlaylistNotFound = errors.New("playlist not found for beer style")
+// It wraps beer.ErrMusicNotFound.
+var ErrPlaylistNotFound = fmt.Errorf("spotify: %w", beer.ErrMusicNotFound)
+
+// ErrRateLimited is returned when a Spotify call is shed because the
+// rate limit (or a Retry-After backoff) would keep it queued too long.
+var ErrRateLimited = errors.New("spotify rate limit exceeded")
+
+// ErrInvalidPlaylistURI is returned when a playlist URI is not a Spotify
+// playlist URI or link.
+var ErrInvalidPlaylistURI = errors.New("invalid spotify playlist uri")
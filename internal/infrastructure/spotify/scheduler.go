@@ -0,0 +1,258 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default pacing for outbound Spotify calls.
+const (
+	DefaultRequestsPerSecond = 10
+	DefaultBurst             = 10
+	DefaultMaxQueueWait      = 2 * time.Second
+
+	// defaultRetryAfter is used when a 429 carries no usable Retry-After.
+	defaultRetryAfter = 5 * time.Second
+
+	// maxRateLimitAttempts bounds how many 429s a single call absorbs.
+	maxRateLimitAttempts = 3
+)
+
+// Scheduler paces outbound Spotify calls with a token bucket and a
+// backoff window shared by every caller, so a 429 seen by one request
+// holds back all the others until Retry-After has elapsed.
+//
+// Callers never queue longer than maxWait (or their context deadline,
+// whichever is shorter); they are shed with ErrRateLimited instead.
+type Scheduler struct {
+	rate    float64
+	burst   float64
+	maxWait time.Duration
+
+	mu           sync.Mutex
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewScheduler creates a Scheduler allowing requestsPerSecond calls on
+// average, bursts of up to burst calls, and at most maxWait of queueing.
+func NewScheduler(
+	requestsPerSecond float64,
+	burst int,
+	maxWait time.Duration,
+) *Scheduler {
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = DefaultRequestsPerSecond
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &Scheduler{
+		rate:    requestsPerSecond,
+		burst:   float64(burst),
+		maxWait: maxWait,
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// Wait blocks until the caller may send a request. It returns
+// ErrRateLimited right away when the required wait exceeds the queue
+// deadline, and the context error if ctx is done while waiting.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	delay, err := s.reserve(ctx)
+	if err != nil {
+		return err
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// Backoff blocks every caller for d, unless an equal or longer
+// backoff is already in place.
+func (s *Scheduler) Backoff(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(s.blockedUntil) {
+		s.blockedUntil = until
+	}
+}
+
+// BlockedUntil returns the end of the current backoff window,
+// or the zero time if Spotify has not asked us to back off.
+func (s *Scheduler) BlockedUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Now().After(s.blockedUntil) {
+		return time.Time{}
+	}
+	return s.blockedUntil
+}
+
+// reserve takes a token and returns how long the caller must wait
+// before using it. No token is taken when the caller is shed.
+func (s *Scheduler) reserve(ctx context.Context) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	// Refill the bucket for the time elapsed since the last call
+	s.tokens += now.Sub(s.last).Seconds() * s.rate
+	if s.tokens > s.burst {
+		s.tokens = s.burst
+	}
+	s.last = now
+
+	var delay time.Duration
+	if s.tokens < 1 {
+		delay = time.Duration((1 - s.tokens) / s.rate * float64(time.Second))
+	}
+
+	if backoff := s.blockedUntil.Sub(now); backoff > delay {
+		delay = backoff
+	}
+
+	limit := s.maxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := deadline.Sub(now); remaining < limit {
+			limit = remaining
+		}
+	}
+
+	if delay > limit {
+		return 0, ErrRateLimited
+	}
+
+	s.tokens--
+	return delay, nil
+}
+
+// cancel returns a token taken by reserve whose caller gave up before
+// using it, so cancelled waits do not eat into the request budget.
+func (s *Scheduler) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens++
+	if s.tokens > s.burst {
+		s.tokens = s.burst
+	}
+}
+
+// rateLimitedTransport sends every request through a Scheduler and
+// turns 429 responses into a shared backoff instead of blocking the
+// calling goroutine for the whole Retry-After window.
+type rateLimitedTransport struct {
+	base      http.RoundTripper
+	scheduler *Scheduler
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := t.scheduler.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		t.scheduler.Backoff(retryAfter(resp))
+
+		// Only body-less requests can be replayed as-is
+		if attempt == maxRateLimitAttempts || req.Body != nil {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// retryAfter parses the Retry-After header of a 429 response: a number
+// of seconds or an HTTP-date. A date in the past means no wait.
+func retryAfter(resp *http.Response) time.Duration {
+	header := resp.Header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return defaultRetryAfter
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return defaultRetryAfter
+}
+
+// Outcomes of Spotify calls, as told to a CallObserver.
+const (
+	OutcomeOK          = "ok"
+	OutcomeClientError = "client_error"
+	OutcomeRateLimited = "rate_limited"
+	OutcomeServerError = "server_error"
+	OutcomeShed        = "shed"
+	OutcomeError       = "error"
+)
+
+// CallObserver is told the outcome and latency of every Web API call.
+type CallObserver func(outcome string, latency time.Duration)
+
+// observedTransport reports every call, pacing and 429 retries included,
+// to a CallObserver.
+type observedTransport struct {
+	base     http.RoundTripper
+	observer CallObserver
+}
+
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.observer(callOutcome(resp, err), time.Since(start))
+	return resp, err
+}
+
+func callOutcome(resp *http.Response, err error) string {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return OutcomeShed
+	case err != nil:
+		return OutcomeError
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return OutcomeRateLimited
+	case resp.StatusCode >= 500:
+		return OutcomeServerError
+	case resp.StatusCode >= 400:
+		return OutcomeClientError
+	default:
+		return OutcomeOK
+	}
+}
@@ -0,0 +1,220 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	spotifyinfra "karhub-beer-machine/internal/infrastructure/spotify"
+	"karhub-beer-machine/internal/infrastructure/spotify/spotifytest"
+)
+
+func TestScheduler_Wait(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      float64
+		burst     int
+		maxWait   time.Duration
+		backoff   time.Duration
+		calls     int
+		wantErrAt int // 1-based index of the first shed call, 0 = none
+	}{
+		{
+			name:    "calls within burst pass",
+			rate:    1,
+			burst:   3,
+			maxWait: 0,
+			calls:   3,
+		},
+		{
+			name:      "call beyond burst is shed",
+			rate:      1,
+			burst:     2,
+			maxWait:   10 * time.Millisecond,
+			calls:     3,
+			wantErrAt: 3,
+		},
+		{
+			name:    "call beyond burst waits within queue deadline",
+			rate:    100,
+			burst:   1,
+			maxWait: time.Second,
+			calls:   3,
+		},
+		{
+			name:      "backoff longer than queue deadline sheds",
+			rate:      100,
+			burst:     10,
+			maxWait:   100 * time.Millisecond,
+			backoff:   time.Minute,
+			calls:     1,
+			wantErrAt: 1,
+		},
+		{
+			name:    "backoff shorter than queue deadline waits",
+			rate:    100,
+			burst:   10,
+			maxWait: time.Second,
+			backoff: 20 * time.Millisecond,
+			calls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := spotifyinfra.NewScheduler(tt.rate, tt.burst, tt.maxWait)
+			if tt.backoff > 0 {
+				scheduler.Backoff(tt.backoff)
+			}
+
+			for i := 1; i <= tt.calls; i++ {
+				err := scheduler.Wait(context.Background())
+
+				if i == tt.wantErrAt {
+					if !errors.Is(err, spotifyinfra.ErrRateLimited) {
+						t.Fatalf("call %d: expected ErrRateLimited, got %v", i, err)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("call %d: unexpected error: %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduler_Wait_ContextDeadline(t *testing.T) {
+	scheduler := spotifyinfra.NewScheduler(100, 10, time.Minute)
+	scheduler.Backoff(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := scheduler.Wait(ctx)
+
+	if !errors.Is(err, spotifyinfra.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("expected caller to be shed immediately, took %v", elapsed)
+	}
+}
+
+func TestScheduler_SharedBackoffAcrossClients(t *testing.T) {
+	server := newFakeServer(t)
+	scheduler := spotifyinfra.NewScheduler(100, 10, 100*time.Millisecond)
+
+	first := newClient(t, server, spotifyinfra.WithScheduler(scheduler))
+	second := newClient(t, server, spotifyinfra.WithScheduler(scheduler))
+
+	server.SetScenario(rateLimitedScenario(time.Minute))
+
+	if _, err := first.FindPlaylistByStyle(context.Background(), "IPA"); !errors.Is(err, spotifyinfra.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	if scheduler.BlockedUntil().IsZero() {
+		t.Fatalf("expected backoff to be recorded")
+	}
+
+	if _, err := second.FindPlaylistByStyle(context.Background(), "IPA"); !errors.Is(err, spotifyinfra.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	if got := server.Requests("/v1/search"); got != 1 {
+		t.Errorf("expected 1 search request to reach Spotify, got %d", got)
+	}
+}
+
+func TestScheduler_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario spotifytest.Scenario
+		want     time.Duration
+	}{
+		{
+			name:     "seconds",
+			scenario: spotifytest.Scenario{RateLimited: 1, RetryAfter: time.Minute},
+			want:     time.Minute,
+		},
+		{
+			name:     "http date",
+			scenario: spotifytest.Scenario{RateLimited: 1, RetryAfter: time.Minute, RetryAfterDate: true},
+			want:     time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			scheduler := spotifyinfra.NewScheduler(100, 10, 100*time.Millisecond)
+			client := newClient(t, server, spotifyinfra.WithScheduler(scheduler))
+
+			server.SetScenario(tt.scenario)
+			_, _ = client.FindPlaylistByStyle(context.Background(), "IPA")
+
+			// HTTP-dates have a one second resolution
+			got := time.Until(scheduler.BlockedUntil())
+			if got < tt.want-2*time.Second || got > tt.want {
+				t.Errorf("expected a backoff of about %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduler_CallObserver(t *testing.T) {
+	server := newFakeServer(t)
+
+	var (
+		mu       sync.Mutex
+		outcomes []string
+	)
+	observe := func(outcome string, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		if latency <= 0 {
+			t.Errorf("expected a positive latency for %s, got %v", outcome, latency)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	client := newClient(t, server,
+		spotifyinfra.WithScheduler(spotifyinfra.NewScheduler(100, 10, 100*time.Millisecond)),
+		spotifyinfra.WithCallObserver(observe),
+	)
+
+	last := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(outcomes) == 0 {
+			return ""
+		}
+		return outcomes[len(outcomes)-1]
+	}
+
+	if _, err := client.FindPlaylistByStyle(context.Background(), "IPA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := last(); got != spotifyinfra.OutcomeOK {
+		t.Fatalf("expected %q, got %q", spotifyinfra.OutcomeOK, got)
+	}
+
+	server.SetScenario(spotifytest.Scenario{ServerErrors: 1})
+	_, _ = client.FindPlaylistByStyle(context.Background(), "IPA")
+	if got := last(); got != spotifyinfra.OutcomeServerError {
+		t.Fatalf("expected %q, got %q", spotifyinfra.OutcomeServerError, got)
+	}
+
+	// The 429 turns into a backoff longer than the queue allows
+	server.SetScenario(rateLimitedScenario(time.Minute))
+	_, _ = client.FindPlaylistByStyle(context.Background(), "IPA")
+	if got := last(); got != spotifyinfra.OutcomeShed {
+		t.Fatalf("expected %q, got %q", spotifyinfra.OutcomeShed, got)
+	}
+}
@@ -0,0 +1,459 @@
+// Package spotifytest provides a fake Spotify Web API server built on
+// httptest, so the Spotify adapters can be exercised fully offline.
+package spotifytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ClientID is the client ID accepted by the fake token endpoint.
+	ClientID = "test-client-id"
+
+	// ClientSecret is the client secret accepted by the fake token endpoint.
+	ClientSecret = "test-client-secret"
+
+	// AccessToken is the bearer token issued by the fake token endpoint.
+	AccessToken = "test-access-token"
+)
+
+// Playlist is a playlist served by the fake server.
+type Playlist struct {
+	ID          string
+	Name        string
+	Description string
+	Owner       string
+	ImageURL    string
+	Tracks      []Track
+}
+
+// Track is a track inside a fake playlist.
+type Track struct {
+	ID         string
+	Name       string
+	Artists    []string
+	Album      string
+	AlbumArt   string
+	DurationMs int
+	Explicit   bool
+	PreviewURL string
+}
+
+// Scenario controls how the fake server misbehaves.
+// The zero value serves every request normally.
+type Scenario struct {
+	// RateLimited is the number of API requests answered with 429
+	// before the server starts responding normally again.
+	RateLimited int
+
+	// RetryAfter is sent in the Retry-After header of 429 responses.
+	// It is rounded down to whole seconds, as Spotify does.
+	RetryAfter time.Duration
+
+	// RetryAfterDate sends RetryAfter as an HTTP-date instead of a
+	// number of seconds, as RFC 9110 also allows.
+	RetryAfterDate bool
+
+	// ServerErrors is the number of API requests answered with 503
+	// before the server starts responding normally again.
+	ServerErrors int
+
+	// Empty makes search return no playlists.
+	Empty bool
+
+	// Latency delays every API response.
+	Latency time.Duration
+}
+
+// Server is a fake Spotify Web API and Accounts service.
+type Server struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	playlists []Playlist
+	scenario  Scenario
+	requests  map[string]int
+	headers   map[string][]http.Header
+}
+
+// NewServer starts a fake Spotify server. Callers must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		requests: make(map[string]int),
+		headers:  make(map[string][]http.Header),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/token", s.handleToken)
+	mux.HandleFunc("/v1/search", s.authorized(s.handleSearch))
+	mux.HandleFunc("/v1/playlists/", s.authorized(s.handlePlaylists))
+
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// BaseURL returns the Web API base URL, suitable for spotify.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.server.URL + "/v1/"
+}
+
+// TokenURL returns the client credentials token endpoint.
+func (s *Server) TokenURL() string {
+	return s.server.URL + "/api/token"
+}
+
+// AddPlaylist adds a playlist to the fake catalog.
+func (s *Server) AddPlaylist(p Playlist) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.playlists = append(s.playlists, p)
+}
+
+// SetScenario replaces the current scenario.
+func (s *Server) SetScenario(sc Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scenario = sc
+}
+
+// Requests returns how many requests were received for a path
+// (e.g. "/v1/search" or "/api/token").
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+// Headers returns the headers of every API request received for a path,
+// in arrival order.
+func (s *Server) Headers(path string) []http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.headers[path])
+}
+
+/*
+	---------- Handlers ----------
+*/
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.count(r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "unsupported_grant_type",
+		})
+		return
+	}
+
+	// oauth2 may send credentials either as basic auth or in the body
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+
+	if id != ClientID || secret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error": "invalid_client",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	limit := intParam(r, "limit", 20)
+
+	s.mu.Lock()
+	empty := s.scenario.Empty
+	matches := make([]Playlist, 0, len(s.playlists))
+	for _, p := range s.playlists {
+		if strings.Contains(strings.ToLower(p.Name), query) {
+			matches = append(matches, p)
+		}
+	}
+	s.mu.Unlock()
+
+	if empty {
+		matches = nil
+	}
+
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	items := make([]map[string]any, 0, len(matches))
+	for _, p := range matches {
+		items = append(items, s.simplePlaylist(p))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"playlists": map[string]any{
+			"href":   s.server.URL + r.URL.String(),
+			"items":  items,
+			"limit":  limit,
+			"offset": 0,
+			"total":  total,
+		},
+	})
+}
+
+func (s *Server) handlePlaylists(w http.ResponseWriter, r *http.Request) {
+	// /v1/playlists/{id} or /v1/playlists/{id}/tracks
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/playlists/")
+	id, suffix, _ := strings.Cut(rest, "/")
+
+	playlist, found := s.findPlaylist(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	switch suffix {
+	case "":
+		writeJSON(w, http.StatusOK, s.simplePlaylist(playlist))
+	case "tracks":
+		s.handlePlaylistItems(w, r, playlist)
+	default:
+		writeError(w, http.StatusNotFound, "Service not found")
+	}
+}
+
+func (s *Server) handlePlaylistItems(
+	w http.ResponseWriter,
+	r *http.Request,
+	playlist Playlist,
+) {
+	id := playlist.ID
+
+	limit := intParam(r, "limit", 100)
+	offset := intParam(r, "offset", 0)
+
+	tracks := playlist.Tracks
+	if offset > len(tracks) {
+		offset = len(tracks)
+	}
+	tracks = tracks[offset:]
+	if len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+
+	items := make([]map[string]any, 0, len(tracks))
+	for _, t := range tracks {
+		items = append(items, map[string]any{
+			"added_at": "2024-01-01T00:00:00Z",
+			"is_local": false,
+			"track":    fullTrack(t),
+		})
+	}
+
+	var next string
+	if end := offset + len(tracks); end < len(playlist.Tracks) {
+		next = fmt.Sprintf(
+			"%splaylists/%s/tracks?offset=%d&limit=%d",
+			s.BaseURL(), id, end, limit,
+		)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"href":   s.server.URL + r.URL.String(),
+		"items":  items,
+		"limit":  limit,
+		"offset": offset,
+		"next":   next,
+		"total":  len(playlist.Tracks),
+	})
+}
+
+// authorized wraps an API handler with bearer token validation
+// and the configured failure scenario.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.count(r.URL.Path)
+		s.recordHeaders(r)
+
+		if r.Header.Get("Authorization") != "Bearer "+AccessToken {
+			writeError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+
+		s.mu.Lock()
+		latency := s.scenario.Latency
+		rateLimited := s.scenario.RateLimited > 0
+		retryAfter := s.scenario.RetryAfter
+		retryAfterDate := s.scenario.RetryAfterDate
+		serverError := !rateLimited && s.scenario.ServerErrors > 0
+		if rateLimited {
+			s.scenario.RateLimited--
+		} else if serverError {
+			s.scenario.ServerErrors--
+		}
+		s.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case rateLimited:
+			if retryAfterDate {
+				w.Header().Set("Retry-After", time.Now().Add(retryAfter).UTC().Format(http.TimeFormat))
+			} else {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+			}
+			writeError(w, http.StatusTooManyRequests, "API rate limit exceeded")
+		case serverError:
+			writeError(w, http.StatusServiceUnavailable, "Service unavailable")
+		default:
+			next(w, r)
+		}
+	}
+}
+
+/*
+	---------- Helpers ----------
+*/
+
+func (s *Server) count(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[path]++
+}
+
+func (s *Server) recordHeaders(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headers[r.URL.Path] = append(s.headers[r.URL.Path], r.Header.Clone())
+}
+
+func (s *Server) findPlaylist(id string) (Playlist, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.playlists {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Playlist{}, false
+}
+
+func (s *Server) simplePlaylist(p Playlist) map[string]any {
+	return map[string]any{
+		"id":            p.ID,
+		"name":          p.Name,
+		"description":   p.Description,
+		"images":        images(p.ImageURL),
+		"owner":         map[string]any{"id": strings.ToLower(p.Owner), "display_name": p.Owner},
+		"href":          s.BaseURL() + "playlists/" + p.ID,
+		"uri":           "spotify:playlist:" + p.ID,
+		"public":        true,
+		"external_urls": map[string]string{"spotify": "https://open.spotify.com/playlist/" + p.ID},
+		"tracks": map[string]any{
+			"href":  s.BaseURL() + "playlists/" + p.ID + "/tracks",
+			"total": len(p.Tracks),
+		},
+	}
+}
+
+func fullTrack(t Track) map[string]any {
+	artists := make([]map[string]any, 0, len(t.Artists))
+	for i, name := range t.Artists {
+		artists = append(artists, map[string]any{
+			"id":   fmt.Sprintf("%s-artist-%d", t.ID, i),
+			"name": name,
+		})
+	}
+
+	return map[string]any{
+		"type":          "track",
+		"id":            t.ID,
+		"name":          t.Name,
+		"uri":           "spotify:track:" + t.ID,
+		"artists":       artists,
+		"duration_ms":   t.DurationMs,
+		"explicit":      t.Explicit,
+		"preview_url":   t.PreviewURL,
+		"external_urls": map[string]string{"spotify": "https://open.spotify.com/track/" + t.ID},
+		"album": map[string]any{
+			"id":     t.ID + "-album",
+			"name":   t.Album,
+			"images": images(t.AlbumArt),
+		},
+	}
+}
+
+// images returns the Spotify image list for a single cover URL.
+func images(url string) []map[string]any {
+	if url == "" {
+		return []map[string]any{}
+	}
+
+	return []map[string]any{
+		{"url": url, "width": 640, "height": 640},
+	}
+}
+
+func intParam(r *http.Request, name string, fallback int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || v < 0 {
+		return fallback
+	}
+	return v
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{
+			"status":  status,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package spotify
+
+import (
+	"errors"
+	"net/http"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+// tracingTransport wraps every attempt sent to Spotify, 429 retries
+// included, in a client span and propagates it in the traceparent
+// header.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartKind(req.Context(), tracing.SpanKindClient, "HTTP "+req.Method,
+		tracing.String("http.request.method", req.Method),
+		tracing.String("server.address", req.URL.Host),
+		tracing.String("url.path", req.URL.Path),
+	)
+	defer span.End()
+
+	// A RoundTripper must not modify the request it was given
+	req = req.Clone(ctx)
+	if sc := span.SpanContext(); sc.IsValid() {
+		req.Header.Set(tracing.TraceParentHeader, sc.TraceParent())
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.RecordError(errors.New(http.StatusText(resp.StatusCode)))
+	}
+	return resp, nil
+}
+
+// recordLookup describes the outcome of a playlist lookup on span. Not
+// found is an answer, not a failure.
+func recordLookup(span tracing.Span, playlist beer.Playlist, err error) {
+	switch {
+	case errors.Is(err, beer.ErrMusicNotFound):
+		span.SetAttributes(tracing.Bool("playlist.found", false))
+	case err != nil:
+		span.RecordError(err)
+	default:
+		span.SetAttributes(
+			tracing.Bool("playlist.found", true),
+			tracing.Int("playlist.tracks", len(playlist.Tracks)),
+		)
+	}
+}
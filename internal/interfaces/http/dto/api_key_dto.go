@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// ---------- Requests ----------
+
+// CreateAPIKeyRequest represents the HTTP payload to issue an API key.
+// ExpiresIn is a Go duration (e.g. "720h"); empty means no expiry.
+type CreateAPIKeyRequest struct {
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Role      string `json:"role"`
+	ExpiresIn string `json:"expiresIn"`
+}
+
+// ---------- Responses ----------
+
+// APIKeyResponse represents an API key, without its secret. Status is
+// active, expired or revoked.
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner,omitempty"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// APIKeySecretResponse represents a newly created or rotated API key
+// with its plaintext secret, which is never returned again.
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Secret string `json:"secret"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // ---------- Requests ----------
 
 // CreateBeerStyleRequest represents the HTTP payload to create a beer style.
@@ -22,6 +24,29 @@ type FindBestBeerStyleRequest struct {
 	Temperature float64 `json:"temperature"`
 }
 
+// TrackRequest represents a curated track in a playlist override payload.
+type TrackRequest struct {
+	Name        string   `json:"name"`
+	Artists     []string `json:"artists"`
+	Album       string   `json:"album"`
+	AlbumImages []string `json:"albumImages"`
+	DurationMs  int64    `json:"durationMs"`
+	Explicit    bool     `json:"explicit"`
+	PreviewURL  string   `json:"previewUrl"`
+	Link        string   `json:"link"`
+}
+
+// PlaylistOverrideRequest represents the HTTP payload to pin a playlist to a
+// beer style. Either Name (with Tracks) or ExternalURI must be set.
+type PlaylistOverrideRequest struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Link        string         `json:"link"`
+	Images      []string       `json:"images"`
+	Tracks      []TrackRequest `json:"tracks"`
+	ExternalURI string         `json:"externalUri"`
+}
+
 // ---------- Responses ----------
 
 // BeerStyleResponse represents a beer style in HTTP responses.
@@ -32,17 +57,38 @@ type BeerStyleResponse struct {
 	MaxTemp float64 `json:"maxTemp"`
 }
 
+// ImageResponse represents a cover image in HTTP responses.
+type ImageResponse struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
 // TrackResponse represents a track in a playlist response.
 type TrackResponse struct {
-	Name   string `json:"name"`
-	Artist string `json:"artist"`
-	Link   string `json:"link"`
+	Name        string          `json:"name"`
+	Artist      string          `json:"artist"`
+	Artists     []string        `json:"artists"`
+	Album       string          `json:"album,omitempty"`
+	AlbumImages []ImageResponse `json:"albumImages"`
+	DurationMs  int64           `json:"durationMs"`
+	Explicit    bool            `json:"explicit"`
+	PreviewURL  string          `json:"previewUrl,omitempty"`
+	Link        string          `json:"link"`
 }
 
 // PlaylistResponse represents a playlist in HTTP responses.
 type PlaylistResponse struct {
-	Name   string          `json:"name"`
-	Tracks []TrackResponse `json:"tracks"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Owner       string          `json:"owner,omitempty"`
+	Link        string          `json:"link,omitempty"`
+	Provider    string          `json:"provider,omitempty"`
+	Freshness   string          `json:"freshness,omitempty"`
+	FetchedAt   *time.Time      `json:"fetchedAt,omitempty"`
+	Images      []ImageResponse `json:"images"`
+	TotalTracks int             `json:"totalTracks"`
+	Tracks      []TrackResponse `json:"tracks"`
 }
 
 // FindBestBeerStyleResponse represents the response for best beer style endpoint.
@@ -50,3 +96,10 @@ type FindBestBeerStyleResponse struct {
 	BeerStyle string           `json:"beerStyle"`
 	Playlist  PlaylistResponse `json:"playlist"`
 }
+
+// PlaylistOverrideResponse represents the playlist pinned to a beer style.
+type PlaylistOverrideResponse struct {
+	StyleID     string            `json:"styleId"`
+	ExternalURI string            `json:"externalUri,omitempty"`
+	Playlist    *PlaylistResponse `json:"playlist,omitempty"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+// ---------- Responses ----------
+
+// CacheStatsResponse represents the counters of one cache.
+type CacheStatsResponse struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRatio  float64 `json:"hitRatio"`
+	Evictions uint64  `json:"evictions"`
+	Keys      int     `json:"keys"`
+	Cost      int64   `json:"cost"`
+	MaxCost   int64   `json:"maxCost,omitempty"`
+	Errors    uint64  `json:"errors"`
+}
+
+// CacheEntryResponse represents a cached key. TTLMs is 0 for keys that
+// never expire.
+type CacheEntryResponse struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttlMs"`
+}
+
+// CacheEntriesResponse lists the keys of a cache.
+type CacheEntriesResponse struct {
+	Cache   string               `json:"cache"`
+	Entries []CacheEntryResponse `json:"entries"`
+}
+
+// CachePurgeResponse reports how many keys a purge removed.
+type CachePurgeResponse struct {
+	Cache  string `json:"cache"`
+	Purged int    `json:"purged"`
+}
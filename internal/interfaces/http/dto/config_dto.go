@@ -0,0 +1,18 @@
+package dto
+
+// ---------- Responses ----------
+
+// ConfigChangeResponse represents a setting whose value changed. Applied
+// is false for settings that only take effect on restart.
+type ConfigChangeResponse struct {
+	Key     string `json:"key"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Applied bool   `json:"applied"`
+}
+
+// ConfigReloadResponse lists what a configuration reload changed.
+type ConfigReloadResponse struct {
+	Changes         []ConfigChangeResponse `json:"changes"`
+	RestartRequired bool                   `json:"restartRequired"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+// ---------- Responses ----------
+
+// HealthResponse represents the health of the service. Probes only get
+// the status; the detailed report adds the build and each dependency.
+type HealthResponse struct {
+	Status    string                `json:"status"`
+	Version   string                `json:"version,omitempty"`
+	Commit    string                `json:"commit,omitempty"`
+	GoVersion string                `json:"goVersion,omitempty"`
+	Checks    []HealthCheckResponse `json:"checks,omitempty"`
+}
+
+// HealthCheckResponse represents the outcome of one dependency check.
+type HealthCheckResponse struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Optional   bool    `json:"optional,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
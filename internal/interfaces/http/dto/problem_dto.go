@@ -0,0 +1,15 @@
+package dto
+
+// ---------- Responses ----------
+
+// ProblemResponse is an RFC 9457 problem details body, served as
+// application/problem+json. RequestID lets callers quote the failed
+// request to operators.
+type ProblemResponse struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+)
+
+type APIKeyHandler struct {
+	createUC *auth.CreateAPIKeyUseCase
+	listUC   *auth.ListAPIKeysUseCase
+	revokeUC *auth.RevokeAPIKeyUseCase
+	rotateUC *auth.RotateAPIKeyUseCase
+}
+
+func NewAPIKeyHandler(
+	createUC *auth.CreateAPIKeyUseCase,
+	listUC *auth.ListAPIKeysUseCase,
+	revokeUC *auth.RevokeAPIKeyUseCase,
+	rotateUC *auth.RotateAPIKeyUseCase,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		createUC: createUC,
+		listUC:   listUC,
+		revokeUC: revokeUC,
+		rotateUC: rotateUC,
+	}
+}
+
+/*
+POST /admin/api-keys
+*/
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	input := auth.CreateAPIKeyInput{
+		Name:  req.Name,
+		Owner: req.Owner,
+		Role:  auth.Role(req.Role),
+	}
+
+	if req.ExpiresIn != "" {
+		ttl, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil {
+			http.Error(w, "invalid expiresIn", http.StatusBadRequest)
+			return
+		}
+		input.TTL = ttl
+	}
+
+	created, err := h.createUC.Execute(input)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeSecret(w, http.StatusCreated, created)
+}
+
+/*
+GET /admin/api-keys
+*/
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.listUC.Execute()
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, toAPIKeyResponse(key, now))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+/*
+DELETE /admin/api-keys/{id}
+*/
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.revokeUC.Execute(r.PathValue("id")); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/*
+POST /admin/api-keys/{id}/rotate
+*/
+func (h *APIKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	rotated, err := h.rotateUC.Execute(r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	writeSecret(w, http.StatusOK, rotated)
+}
+
+// writeSecret answers with a key and its secret, which no cache may keep.
+func writeSecret(w http.ResponseWriter, status int, k auth.APIKeyWithSecret) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(dto.APIKeySecretResponse{
+		APIKeyResponse: toAPIKeyResponse(k.Key, time.Now()),
+		Secret:         k.Secret,
+	})
+}
+
+func toAPIKeyResponse(key auth.APIKey, now time.Time) dto.APIKeyResponse {
+	resp := dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		Role:       string(key.Role),
+		Status:     "active",
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  optionalTime(key.ExpiresAt),
+		LastUsedAt: optionalTime(key.LastUsedAt),
+		RevokedAt:  optionalTime(key.RevokedAt),
+	}
+
+	switch {
+	case key.Revoked():
+		resp.Status = "revoked"
+	case key.Expired(now):
+		resp.Status = "expired"
+	}
+
+	return resp
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+func (h *APIKeyHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidAPIKey):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, auth.ErrAPIKeyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, auth.ErrAPIKeyRevoked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,215 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+/*
+	Helper to build test server with the API key routes
+*/
+
+func setupAPIKeyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	configured := memory.NewAPIKeyRepository()
+	_ = configured.Save(auth.APIKey{ID: "admin", Name: "admin", Role: auth.RoleAdmin, Hash: auth.HashAPIKey(adminToken)})
+
+	issued := memory.NewAPIKeyRepository()
+
+	h := handlers.NewAPIKeyHandler(
+		auth.NewCreateAPIKeyUseCase(issued),
+		auth.NewListAPIKeysUseCase(issued),
+		auth.NewRevokeAPIKeyUseCase(issued),
+		auth.NewRotateAPIKeyUseCase(issued),
+	)
+
+	guard := middleware.NewGuard(auth.NewAPIKeyAuthenticator(configured, issued), auth.RoleNone)
+
+	mux := http.NewServeMux()
+	httpapi.RegisterAPIKeyRoutes(mux, h, guard)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doWithToken(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+type apiKeySecret struct {
+	ID     string `json:"id"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+	Secret string `json:"secret"`
+}
+
+/*
+	TESTS
+*/
+
+func TestAPIKeyHTTP_Lifecycle(t *testing.T) {
+	server := setupAPIKeyServer(t)
+
+	resp := doWithToken(t, http.MethodPost, server.URL+"/admin/api-keys", adminToken,
+		`{"name": "dashboard", "owner": "data-team", "role": "admin", "expiresIn": "720h"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.Errorf("expected the secret not to be cacheable")
+	}
+
+	var created apiKeySecret
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Secret == "" || created.Status != "active" {
+		t.Fatalf("expected an active key with its secret, got %+v", created)
+	}
+
+	// The new key authenticates, and the list never shows secrets
+	resp = doWithToken(t, http.MethodGet, server.URL+"/admin/api-keys", created.Secret, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if bytes.Contains(body, []byte(created.Secret)) || bytes.Contains(body, []byte(auth.HashAPIKey(created.Secret))) {
+		t.Errorf("expected the list not to expose secrets or hashes: %s", body)
+	}
+	if !bytes.Contains(body, []byte(`"lastUsedAt"`)) {
+		t.Errorf("expected the last use to be listed: %s", body)
+	}
+
+	// Rotation
+	resp = doWithToken(t, http.MethodPost, server.URL+"/admin/api-keys/"+created.ID+"/rotate", adminToken, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var rotated apiKeySecret
+	_ = json.NewDecoder(resp.Body).Decode(&rotated)
+
+	if resp := doWithToken(t, http.MethodGet, server.URL+"/admin/api-keys", created.Secret, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the rotated-out secret to be rejected, got %d", resp.StatusCode)
+	}
+
+	// Revocation
+	if resp := doWithToken(t, http.MethodDelete, server.URL+"/admin/api-keys/"+created.ID, adminToken, ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", resp.StatusCode)
+	}
+
+	if resp := doWithToken(t, http.MethodGet, server.URL+"/admin/api-keys", rotated.Secret, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the revoked key to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPIKeyHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		body           string
+		wantStatusCode int
+	}{
+		{
+			name:           "anonymous",
+			method:         http.MethodGet,
+			path:           "/admin/api-keys",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid role",
+			method:         http.MethodPost,
+			path:           "/admin/api-keys",
+			token:          adminToken,
+			body:           `{"name": "ci", "role": "root"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid expiry",
+			method:         http.MethodPost,
+			path:           "/admin/api-keys",
+			token:          adminToken,
+			body:           `{"name": "ci", "role": "reader", "expiresIn": "a month"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "revoke unknown key",
+			method:         http.MethodDelete,
+			path:           "/admin/api-keys/missing",
+			token:          adminToken,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "rotate unknown key",
+			method:         http.MethodPost,
+			path:           "/admin/api-keys/missing/rotate",
+			token:          adminToken,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupAPIKeyServer(t)
+
+			resp := doWithToken(t, tt.method, server.URL+tt.path, tt.token, tt.body)
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHTTP_RequiresAdmin(t *testing.T) {
+	server := setupAPIKeyServer(t)
+
+	resp := doWithToken(t, http.MethodPost, server.URL+"/admin/api-keys", adminToken, `{"name": "kiosk", "role": "operator"}`)
+
+	var operator apiKeySecret
+	_ = json.NewDecoder(resp.Body).Decode(&operator)
+
+	resp = doWithToken(t, http.MethodPost, server.URL+"/admin/api-keys", operator.Secret, `{"name": "escalate", "role": "admin"}`)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", resp.StatusCode)
+	}
+
+	// Rotating a revoked key is a conflict
+	doWithToken(t, http.MethodDelete, server.URL+"/admin/api-keys/"+operator.ID, adminToken, "")
+	resp = doWithToken(t, http.MethodPost, server.URL+"/admin/api-keys/"+operator.ID+"/rotate", adminToken, "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", resp.StatusCode)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/logging"
 	domain "karhub-beer-machine/internal/domain/beer"
 	"karhub-beer-machine/internal/interfaces/http/dto"
 
@@ -48,14 +49,14 @@ func (h *BeerHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.createUC.Execute(beer.CreateBeerStyleInput{
+	err := h.createUC.Execute(r.Context(), beer.CreateBeerStyleInput{
 		ID:      uuid.New().String(),
 		Name:    req.Name,
 		MinTemp: req.MinTemp,
 		MaxTemp: req.MaxTemp,
 	})
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -78,14 +79,14 @@ func (h *BeerHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.updateUC.Execute(beer.UpdateBeerStyleInput{
+	err := h.updateUC.Execute(r.Context(), beer.UpdateBeerStyleInput{
 		ID:      id,
 		Name:    req.Name,
 		MinTemp: req.MinTemp,
 		MaxTemp: req.MaxTemp,
 	})
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -102,8 +103,8 @@ func (h *BeerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.deleteUC.Execute(id); err != nil {
-		h.handleError(w, err)
+	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -113,10 +114,10 @@ func (h *BeerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 /*
 GET /beer-styles
 */
-func (h *BeerHandler) List(w http.ResponseWriter, _ *http.Request) {
+func (h *BeerHandler) List(w http.ResponseWriter, r *http.Request) {
 	styles, err := h.listUC.Execute()
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -134,8 +135,11 @@ func (h *BeerHandler) List(w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// TenantHeader identifies the tenant whose default music provider is used.
+const TenantHeader = "X-Tenant-ID"
+
 /*
-POST /beer-styles/best
+POST /beer-styles/best?provider={name}
 */
 func (h *BeerHandler) FindBest(w http.ResponseWriter, r *http.Request) {
 	var req dto.FindBestBeerStyleRequest
@@ -147,44 +151,93 @@ func (h *BeerHandler) FindBest(w http.ResponseWriter, r *http.Request) {
 
 	out, err := h.findBestUC.Execute(
 		r.Context(),
-		beer.FindBestBeerStyleInput{Temperature: req.Temperature},
+		beer.FindBestBeerStyleInput{
+			Temperature: req.Temperature,
+			Provider:    r.URL.Query().Get("provider"),
+			Tenant:      r.Header.Get(TenantHeader),
+		},
 	)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
+	resp := dto.FindBestBeerStyleResponse{
+		BeerStyle: out.BeerStyle,
+		Playlist:  toPlaylistResponse(out.Playlist),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toPlaylistResponse(p beer.Playlist) dto.PlaylistResponse {
 	playlist := dto.PlaylistResponse{
-		Name:   out.Playlist.Name,
-		Tracks: []dto.TrackResponse{},
+		Name:        p.Name,
+		Description: p.Description,
+		Owner:       p.Owner,
+		Link:        p.Link,
+		Provider:    p.Provider,
+		Freshness:   string(p.Freshness),
+		Images:      toImageResponses(p.Images),
+		TotalTracks: p.TotalTracks,
+		Tracks:      []dto.TrackResponse{},
+	}
+
+	if !p.FetchedAt.IsZero() {
+		fetchedAt := p.FetchedAt.UTC()
+		playlist.FetchedAt = &fetchedAt
 	}
 
-	for _, t := range out.Playlist.Tracks {
+	for _, t := range p.Tracks {
+		artists := t.Artists
+		if artists == nil {
+			artists = []string{}
+		}
+
 		playlist.Tracks = append(playlist.Tracks, dto.TrackResponse{
-			Name:   t.Name,
-			Artist: t.Artist,
-			Link:   t.Link,
+			Name:        t.Name,
+			Artist:      t.Artist,
+			Artists:     artists,
+			Album:       t.Album,
+			AlbumImages: toImageResponses(t.AlbumImages),
+			DurationMs:  t.Duration.Milliseconds(),
+			Explicit:    t.Explicit,
+			PreviewURL:  t.PreviewURL,
+			Link:        t.Link,
 		})
 	}
 
-	resp := dto.FindBestBeerStyleResponse{
-		BeerStyle: out.BeerStyle,
-		Playlist:  playlist,
-	}
+	return playlist
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(resp)
+func toImageResponses(images []beer.Image) []dto.ImageResponse {
+	resp := make([]dto.ImageResponse, 0, len(images))
+	for _, img := range images {
+		resp = append(resp, dto.ImageResponse{
+			URL:    img.URL,
+			Width:  img.Width,
+			Height: img.Height,
+		})
+	}
+	return resp
 }
 
-func (h *BeerHandler) handleError(w http.ResponseWriter, err error) {
+func (h *BeerHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case errors.Is(err, domain.ErrInvalidBeerStyle):
 		http.Error(w, err.Error(), http.StatusBadRequest)
 	case errors.Is(err, domain.ErrBeerStyleNotFound):
 		http.Error(w, err.Error(), http.StatusNotFound)
-	case errors.Is(err, domain.ErrEmptyBeerStyleList):
+	case errors.Is(err, domain.ErrEmptyBeerStyleList),
+		errors.Is(err, domain.ErrNoStyleForTemperature):
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, beer.ErrUnknownMusicProvider):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, beer.ErrMusicNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
 	default:
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"karhub-beer-machine/internal/application/auth"
 	"karhub-beer-machine/internal/application/beer"
 	domain "karhub-beer-machine/internal/domain/beer"
 	"karhub-beer-machine/internal/infrastructure/persistence/memory"
 	httpapi "karhub-beer-machine/internal/interfaces/http"
 	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
 )
 
 /*
@@ -38,6 +41,25 @@ func (s *spotifyMock) FindPlaylistByStyle(
 func setupServer(t *testing.T) *httptest.Server {
 	t.Helper()
 
+	return setupServerWithMusic(t, &spotifyMock{
+		playlist: beer.Playlist{Name: "IPA Party"},
+	})
+}
+
+func setupServerWithMusic(t *testing.T, music beer.MusicProvider) *httptest.Server {
+	t.Helper()
+
+	return setupServerWithGuard(t, music, middleware.NewGuard(auth.NewChainAuthenticator(), auth.RoleReader))
+}
+
+func setupServerWithGuard(
+	t *testing.T,
+	music beer.MusicProvider,
+	guard *middleware.Guard,
+	opts ...beer.FindBestBeerStyleOption,
+) *httptest.Server {
+	t.Helper()
+
 	repo, err := memory.NewBeerStyleRepository()
 	if err != nil {
 		t.Fatalf("failed to create repo: %v", err)
@@ -47,16 +69,12 @@ func setupServer(t *testing.T) *httptest.Server {
 	_ = repo.Create(domain.BeerStyle{ID: "1", Name: "Dunkel", MinTemp: -8, MaxTemp: 2})
 	_ = repo.Create(domain.BeerStyle{ID: "2", Name: "IPA", MinTemp: -7, MaxTemp: 10})
 
-	spotify := &spotifyMock{
-		playlist: beer.Playlist{Name: "IPA Party"},
-	}
-
 	// use cases
 	createUC := beer.NewCreateBeerStyleUseCase(repo)
 	updateUC := beer.NewUpdateBeerStyleUseCase(repo)
-	deleteUC := beer.NewDeleteBeerStyleUseCase(repo)
+	deleteUC := beer.NewDeleteBeerStyleUseCase(repo, nil)
 	listUC := beer.NewListBeerStylesUseCase(repo)
-	findBestUC := beer.NewFindBestBeerStyleUseCase(repo, spotify)
+	findBestUC := beer.NewFindBestBeerStyleUseCase(repo, music, opts...)
 
 	handler := handlers.NewBeerHandler(
 		createUC,
@@ -67,7 +85,7 @@ func setupServer(t *testing.T) *httptest.Server {
 	)
 
 	mux := http.NewServeMux()
-	httpapi.RegisterRoutes(mux, handler)
+	httpapi.RegisterRoutes(mux, handler, guard)
 
 	return httptest.NewServer(mux)
 }
@@ -150,3 +168,98 @@ func TestFindBestBeerStyleHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestFindBestBeerStyleHTTP_MusicNotFound(t *testing.T) {
+	server := setupServerWithMusic(t, &spotifyMock{
+		err: fmt.Errorf("spotify: %w", beer.ErrMusicNotFound),
+	})
+	defer server.Close()
+
+	resp, err := http.Post(
+		server.URL+"/beer-styles/best",
+		"application/json",
+		bytes.NewBufferString(`{"temperature": -7}`),
+	)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestFindBestBeerStyleHTTP_NoStyleForTemperature(t *testing.T) {
+	server := setupServerWithGuard(t,
+		&spotifyMock{playlist: beer.Playlist{Name: "IPA Party"}},
+		middleware.NewGuard(auth.NewChainAuthenticator(), auth.RoleReader),
+		beer.WithSelectionStrategy(beer.ContainingRangeStrategy),
+	)
+	defer server.Close()
+
+	resp, err := http.Post(
+		server.URL+"/beer-styles/best",
+		"application/json",
+		bytes.NewBufferString(`{"temperature": 30}`),
+	)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+}
+
+func TestBeerStylesHTTP_Authorization(t *testing.T) {
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "1", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("reader-key")})
+	_ = keys.Save(auth.APIKey{ID: "2", Name: "ops", Role: auth.RoleOperator, Hash: auth.HashAPIKey("operator-key")})
+	_ = keys.Save(auth.APIKey{ID: "3", Name: "ci", Role: auth.RoleAdmin, Hash: auth.HashAPIKey("admin-key")})
+
+	guard := middleware.NewGuard(auth.NewAPIKeyAuthenticator(keys), auth.RoleReader)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		key            string
+		wantStatusCode int
+	}{
+		{"anonymous list", http.MethodGet, "/beer-styles", "", http.StatusOK},
+		{"anonymous best", http.MethodPost, "/beer-styles/best", "", http.StatusOK},
+		{"anonymous delete", http.MethodDelete, "/beer-styles/1", "", http.StatusUnauthorized},
+		{"anonymous create", http.MethodPost, "/beer-styles", "", http.StatusUnauthorized},
+		{"invalid key on an open route", http.MethodGet, "/beer-styles", "guess", http.StatusUnauthorized},
+		{"reader delete", http.MethodDelete, "/beer-styles/1", "reader-key", http.StatusForbidden},
+		{"operator update", http.MethodPut, "/beer-styles/1", "operator-key", http.StatusForbidden},
+		{"admin delete", http.MethodDelete, "/beer-styles/1", "admin-key", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupServerWithGuard(t, &spotifyMock{playlist: beer.Playlist{Name: "IPA Party"}}, guard)
+			defer server.Close()
+
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, bytes.NewBufferString(`{"temperature": 0}`))
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			if tt.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.key)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, resp.StatusCode)
+			}
+		})
+	}
+}
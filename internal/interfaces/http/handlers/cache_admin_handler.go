@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+)
+
+type CacheAdminHandler struct {
+	statsUC   *beer.GetCacheStatsUseCase
+	inspectUC *beer.InspectCacheUseCase
+	purgeUC   *beer.PurgeCacheUseCase
+}
+
+func NewCacheAdminHandler(
+	statsUC *beer.GetCacheStatsUseCase,
+	inspectUC *beer.InspectCacheUseCase,
+	purgeUC *beer.PurgeCacheUseCase,
+) *CacheAdminHandler {
+	return &CacheAdminHandler{
+		statsUC:   statsUC,
+		inspectUC: inspectUC,
+		purgeUC:   purgeUC,
+	}
+}
+
+/*
+GET /admin/caches
+*/
+func (h *CacheAdminHandler) Stats(w http.ResponseWriter, _ *http.Request) {
+	resp := make(map[string]dto.CacheStatsResponse)
+	for name, s := range h.statsUC.Execute() {
+		resp[name] = dto.CacheStatsResponse{
+			Hits:      s.Hits,
+			Misses:    s.Misses,
+			HitRatio:  s.HitRatio,
+			Evictions: s.Evictions,
+			Keys:      s.Keys,
+			Cost:      s.Cost,
+			MaxCost:   s.MaxCost,
+			Errors:    s.Errors,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+/*
+GET /admin/caches/{name}/keys?prefix=&limit=
+*/
+func (h *CacheAdminHandler) Keys(w http.ResponseWriter, r *http.Request) {
+	input := beer.InspectCacheInput{
+		Cache:  r.PathValue("name"),
+		Prefix: r.URL.Query().Get("prefix"),
+	}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		input.Limit = limit
+	}
+
+	entries, err := h.inspectUC.Execute(input)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	resp := dto.CacheEntriesResponse{
+		Cache:   input.Cache,
+		Entries: make([]dto.CacheEntryResponse, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, dto.CacheEntryResponse{
+			Key:   e.Key,
+			TTLMs: e.TTL.Milliseconds(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+/*
+DELETE /admin/caches/{name}/keys?key= | ?prefix= | ?all=true
+*/
+func (h *CacheAdminHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	input := beer.PurgeCacheInput{
+		Cache:  r.PathValue("name"),
+		Key:    query.Get("key"),
+		Prefix: query.Get("prefix"),
+	}
+
+	if v := query.Get("all"); v != "" {
+		all, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid all", http.StatusBadRequest)
+			return
+		}
+		input.All = all
+	}
+
+	output, err := h.purgeUC.Execute(input)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dto.CachePurgeResponse{
+		Cache:  input.Cache,
+		Purged: output.Purged,
+	})
+}
+
+func (h *CacheAdminHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, beer.ErrInvalidCachePurge):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, beer.ErrCacheNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,235 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/infrastructure/cache"
+	"karhub-beer-machine/internal/infrastructure/cacheadmin"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+const (
+	adminToken  = "s3cret"
+	readerToken = "r3ader"
+)
+
+/*
+	Helper to build test server with the cache admin routes
+*/
+
+func setupCacheAdminServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	playlists, err := cache.NewRistrettoCache[string, string](1e4, 1<<20)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	playlists.Set("spotify:playlist:IPA", "IPA Party", time.Minute)
+	playlists.Set("spotify:playlist:Stout", "Dark", time.Minute)
+	playlists.Set("spotify:playlist-uri:abc", "abc", 0)
+	playlists.Wait()
+
+	caches := map[string]beer.AdministrableCache{
+		"playlists": cacheadmin.New[string](playlists),
+	}
+
+	h := handlers.NewCacheAdminHandler(
+		beer.NewGetCacheStatsUseCase(caches),
+		beer.NewInspectCacheUseCase(caches),
+		beer.NewPurgeCacheUseCase(caches),
+	)
+
+	mux := http.NewServeMux()
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "admin", Name: "admin", Role: auth.RoleAdmin, Hash: auth.HashAPIKey(adminToken)})
+	_ = keys.Save(auth.APIKey{ID: "ops", Name: "ops", Role: auth.RoleOperator, Hash: auth.HashAPIKey(operatorToken)})
+	_ = keys.Save(auth.APIKey{ID: "dashboard", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey(readerToken)})
+
+	httpapi.RegisterCacheAdminRoutes(mux, h, middleware.NewGuard(auth.NewAPIKeyAuthenticator(keys), auth.RoleReader))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doAdmin(t *testing.T, method, url, token string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+/*
+	TESTS
+*/
+
+func TestCacheAdminHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		wantStatusCode int
+	}{
+		{
+			name:           "stats",
+			method:         http.MethodGet,
+			path:           "/admin/caches",
+			token:          adminToken,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "stats without token",
+			method:         http.MethodGet,
+			path:           "/admin/caches",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "keys with wrong token",
+			method:         http.MethodGet,
+			path:           "/admin/caches/playlists/keys",
+			token:          "guess",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "purge without token",
+			method:         http.MethodDelete,
+			path:           "/admin/caches/playlists/keys?all=true",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "stats as reader",
+			method:         http.MethodGet,
+			path:           "/admin/caches",
+			token:          readerToken,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "keys as operator",
+			method:         http.MethodGet,
+			path:           "/admin/caches/playlists/keys",
+			token:          operatorToken,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "purge as operator",
+			method:         http.MethodDelete,
+			path:           "/admin/caches/playlists/keys?all=true",
+			token:          operatorToken,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "keys of unknown cache",
+			method:         http.MethodGet,
+			path:           "/admin/caches/recommendations/keys",
+			token:          adminToken,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "keys with invalid limit",
+			method:         http.MethodGet,
+			path:           "/admin/caches/playlists/keys?limit=many",
+			token:          adminToken,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "purge without selector",
+			method:         http.MethodDelete,
+			path:           "/admin/caches/playlists/keys",
+			token:          adminToken,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupCacheAdminServer(t)
+
+			resp := doAdmin(t, tt.method, server.URL+tt.path, tt.token)
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, resp.StatusCode)
+			}
+
+			if resp.StatusCode == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Errorf("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestCacheAdminHTTP_InspectAndPurge(t *testing.T) {
+	server := setupCacheAdminServer(t)
+
+	resp := doAdmin(t, http.MethodGet, server.URL+"/admin/caches/playlists/keys?prefix=spotify:playlist:", adminToken)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var keys struct {
+		Entries []struct {
+			Key   string `json:"key"`
+			TTLMs int64  `json:"ttlMs"`
+		} `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(keys.Entries) != 2 || keys.Entries[0].Key != "spotify:playlist:IPA" {
+		t.Fatalf("expected the 2 style playlists, got %+v", keys.Entries)
+	}
+	if ttl := keys.Entries[0].TTLMs; ttl <= 0 || ttl > time.Minute.Milliseconds() {
+		t.Errorf("expected a TTL up to a minute, got %dms", ttl)
+	}
+
+	resp = doAdmin(t, http.MethodDelete, server.URL+"/admin/caches/playlists/keys?prefix=spotify:playlist:", adminToken)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var purge struct {
+		Purged int `json:"purged"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&purge); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if purge.Purged != 2 {
+		t.Errorf("expected 2 keys purged, got %d", purge.Purged)
+	}
+
+	resp = doAdmin(t, http.MethodGet, server.URL+"/admin/caches", adminToken)
+
+	var stats map[string]struct {
+		Keys int `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if stats["playlists"].Keys != 1 {
+		t.Errorf("expected 1 key left, got %+v", stats)
+	}
+}
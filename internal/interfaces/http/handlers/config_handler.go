@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/reload"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// ReloadTrigger names reloads asked for through the API in the audit log.
+const ReloadTrigger = "api"
+
+type ConfigHandler struct {
+	reloadUC *reload.ReloadConfigUseCase
+}
+
+func NewConfigHandler(reloadUC *reload.ReloadConfigUseCase) *ConfigHandler {
+	return &ConfigHandler{
+		reloadUC: reloadUC,
+	}
+}
+
+/*
+POST /admin/config/reload
+*/
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	input := reload.ReloadConfigInput{Trigger: ReloadTrigger}
+
+	if principal, ok := middleware.PrincipalFrom(r.Context()); ok {
+		input.Actor = "sub:" + principal.Subject
+		if principal.KeyID != "" {
+			input.Actor = "key:" + principal.KeyID
+		}
+	}
+
+	output, err := h.reloadUC.Execute(r.Context(), input)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	resp := dto.ConfigReloadResponse{
+		Changes: make([]dto.ConfigChangeResponse, 0, len(output.Changes)),
+	}
+	for _, c := range output.Changes {
+		resp.Changes = append(resp.Changes, dto.ConfigChangeResponse{
+			Key:     c.Key,
+			Old:     c.Old,
+			New:     c.New,
+			Applied: c.Applied,
+		})
+		if !c.Applied {
+			resp.RestartRequired = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ConfigHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, reload.ErrInvalidConfig):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/reload"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+type reloaderMock struct {
+	changes []reload.Change
+	err     error
+	calls   int
+}
+
+func (m *reloaderMock) Reload(context.Context) ([]reload.Change, error) {
+	m.calls++
+	return m.changes, m.err
+}
+
+func setupConfigServer(t *testing.T, reloader reload.Reloader) *httptest.Server {
+	t.Helper()
+
+	h := handlers.NewConfigHandler(reload.NewReloadConfigUseCase(reloader))
+
+	mux := http.NewServeMux()
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "admin", Name: "admin", Role: auth.RoleAdmin, Hash: auth.HashAPIKey(adminToken)})
+	_ = keys.Save(auth.APIKey{ID: "dashboard", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey(readerToken)})
+
+	httpapi.RegisterConfigRoutes(mux, h, middleware.NewGuard(auth.NewAPIKeyAuthenticator(keys), auth.RoleReader))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+/*
+	TESTS
+*/
+
+func TestConfigHandler_Reload(t *testing.T) {
+	tests := []struct {
+		name                string
+		token               string
+		reloader            *reloaderMock
+		wantStatusCode      int
+		wantCalls           int
+		wantChanges         int
+		wantRestartRequired bool
+	}{
+		{
+			name:  "applied changes",
+			token: adminToken,
+			reloader: &reloaderMock{changes: []reload.Change{
+				{Key: "cache.ttl", Old: "10m0s", New: "5m0s", Applied: true},
+			}},
+			wantStatusCode: http.StatusOK,
+			wantCalls:      1,
+			wantChanges:    1,
+		},
+		{
+			name:  "changes needing a restart",
+			token: adminToken,
+			reloader: &reloaderMock{changes: []reload.Change{
+				{Key: "cache.ttl", Old: "10m0s", New: "5m0s", Applied: true},
+				{Key: "http.port", Old: "8080", New: "9090"},
+			}},
+			wantStatusCode:      http.StatusOK,
+			wantCalls:           1,
+			wantChanges:         2,
+			wantRestartRequired: true,
+		},
+		{
+			name:           "invalid configuration",
+			token:          adminToken,
+			reloader:       &reloaderMock{err: fmt.Errorf("%w: cache.ttl: must be positive", reload.ErrInvalidConfig)},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantCalls:      1,
+		},
+		{
+			name:           "reader is forbidden",
+			token:          readerToken,
+			reloader:       &reloaderMock{},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "anonymous is unauthorized",
+			reloader:       &reloaderMock{},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupConfigServer(t, tt.reloader)
+
+			resp := doAdmin(t, http.MethodPost, server.URL+"/admin/config/reload", tt.token)
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, resp.StatusCode)
+			}
+			if tt.reloader.calls != tt.wantCalls {
+				t.Fatalf("expected %d reload(s), got %d", tt.wantCalls, tt.reloader.calls)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var body dto.ConfigReloadResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body.Changes) != tt.wantChanges {
+				t.Fatalf("expected %d change(s), got %d", tt.wantChanges, len(body.Changes))
+			}
+			if body.RestartRequired != tt.wantRestartRequired {
+				t.Fatalf("expected restartRequired %v, got %v", tt.wantRestartRequired, body.RestartRequired)
+			}
+		})
+	}
+}
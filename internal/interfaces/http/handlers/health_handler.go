@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"karhub-beer-machine/internal/application/health"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+)
+
+type HealthHandler struct {
+	checkUC *health.CheckHealthUseCase
+}
+
+func NewHealthHandler(checkUC *health.CheckHealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		checkUC: checkUC,
+	}
+}
+
+/*
+GET /livez
+*/
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	report := h.checkUC.Execute(r.Context(), health.CheckHealthInput{})
+	writeHealth(w, report, dto.HealthResponse{Status: string(report.Status)})
+}
+
+/*
+GET /readyz
+*/
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checkUC.Execute(r.Context(), health.CheckHealthInput{Dependencies: true})
+	writeHealth(w, report, dto.HealthResponse{Status: string(report.Status)})
+}
+
+/*
+GET /admin/health
+*/
+func (h *HealthHandler) Details(w http.ResponseWriter, r *http.Request) {
+	report := h.checkUC.Execute(r.Context(), health.CheckHealthInput{Dependencies: true})
+	writeHealth(w, report, detailedHealth(report))
+}
+
+// detailedHealth reports the build and every check, with its error.
+// Errors name internal addresses, so it is only served to operators.
+func detailedHealth(report health.Report) dto.HealthResponse {
+	resp := dto.HealthResponse{
+		Status:    string(report.Status),
+		Version:   report.Build.Version,
+		Commit:    report.Build.Commit,
+		GoVersion: report.Build.GoVersion,
+	}
+
+	for _, c := range report.Checks {
+		check := dto.HealthCheckResponse{
+			Name:       c.Name,
+			Status:     string(c.Status),
+			Optional:   c.Optional,
+			DurationMs: float64(c.Duration.Microseconds()) / 1000,
+		}
+		if c.Err != nil {
+			check.Error = c.Err.Error()
+		}
+		resp.Checks = append(resp.Checks, check)
+	}
+
+	return resp
+}
+
+// writeHealth answers 503 when a required dependency failed, so probes
+// only need the status code.
+func writeHealth(w http.ResponseWriter, report health.Report, resp dto.HealthResponse) {
+	status := http.StatusOK
+	if report.Status == health.StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
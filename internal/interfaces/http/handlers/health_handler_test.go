@@ -0,0 +1,181 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/health"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+const operatorToken = "0perator"
+
+func setupHealthServer(t *testing.T, repositoryErr, cacheErr error) *httptest.Server {
+	t.Helper()
+
+	registry := health.NewRegistry()
+	registry.Register("repository", health.CheckerFunc(func(context.Context) error {
+		return repositoryErr
+	}))
+	registry.Register("playlist_cache", health.CheckerFunc(func(context.Context) error {
+		return cacheErr
+	}), health.Optional())
+
+	build := health.Build{Version: "v1.2.0", Commit: "abc123", GoVersion: "go1.27"}
+
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "ops", Name: "ops", Role: auth.RoleOperator, Hash: auth.HashAPIKey(operatorToken)})
+	_ = keys.Save(auth.APIKey{ID: "dashboard", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey(readerToken)})
+	guard := middleware.NewGuard(auth.NewAPIKeyAuthenticator(keys), auth.RoleReader)
+
+	mux := http.NewServeMux()
+	httpapi.RegisterHealthRoutes(mux, handlers.NewHealthHandler(health.NewCheckHealthUseCase(registry, build)), guard)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+/*
+	TESTS
+*/
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		token         string
+		repositoryErr error
+		cacheErr      error
+		wantCode      int
+		wantStatus    string
+		wantChecks    int
+	}{
+		{
+			name:       "ready",
+			path:       "/readyz",
+			wantCode:   http.StatusOK,
+			wantStatus: "pass",
+		},
+		{
+			name:       "ready but degraded",
+			path:       "/readyz",
+			cacheErr:   errors.New("redis down"),
+			wantCode:   http.StatusOK,
+			wantStatus: "warn",
+		},
+		{
+			name:          "not ready",
+			path:          "/readyz",
+			repositoryErr: errors.New("catalog unavailable"),
+			wantCode:      http.StatusServiceUnavailable,
+			wantStatus:    "fail",
+		},
+		{
+			name:          "alive while not ready",
+			path:          "/livez",
+			repositoryErr: errors.New("catalog unavailable"),
+			wantCode:      http.StatusOK,
+			wantStatus:    "pass",
+		},
+		{
+			name:       "legacy health endpoint",
+			path:       "/health",
+			wantCode:   http.StatusOK,
+			wantStatus: "pass",
+		},
+		{
+			name:       "details",
+			path:       "/admin/health",
+			token:      operatorToken,
+			cacheErr:   errors.New("dial tcp 10.0.0.7:6379: connection refused"),
+			wantCode:   http.StatusOK,
+			wantStatus: "warn",
+			wantChecks: 2,
+		},
+		{
+			name:          "details while not ready",
+			path:          "/admin/health",
+			token:         operatorToken,
+			repositoryErr: errors.New("catalog unavailable"),
+			wantCode:      http.StatusServiceUnavailable,
+			wantStatus:    "fail",
+			wantChecks:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupHealthServer(t, tt.repositoryErr, tt.cacheErr)
+
+			resp := doAdmin(t, http.MethodGet, server.URL+tt.path, tt.token)
+
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, resp.StatusCode)
+			}
+
+			var body dto.HealthResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+
+			if body.Status != tt.wantStatus || len(body.Checks) != tt.wantChecks {
+				t.Fatalf("expected status %s with %d checks, got %+v", tt.wantStatus, tt.wantChecks, body)
+			}
+
+			// Probes are anonymous: they must not reveal the build
+			detailed := tt.wantChecks > 0
+			if detailed != (body.Version == "v1.2.0" && body.Commit == "abc123" && body.GoVersion == "go1.27") {
+				t.Errorf("expected build info only in the details, got %+v", body)
+			}
+
+			for _, check := range body.Checks {
+				if (check.Status == "pass") != (check.Error == "") {
+					t.Errorf("expected an error exactly on failed checks, got %+v", check)
+				}
+				if check.Name == "playlist_cache" && !check.Optional {
+					t.Errorf("expected the cache check to be optional")
+				}
+			}
+		})
+	}
+}
+
+func TestHealthHandler_DetailsNeedOperator(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{
+			name:     "anonymous",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "reader",
+			token:    readerToken,
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupHealthServer(t, nil, nil)
+
+			resp := doAdmin(t, http.MethodGet, server.URL+"/admin/health", tt.token)
+
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"karhub-beer-machine/internal/application/beer"
+	"karhub-beer-machine/internal/application/logging"
+	domain "karhub-beer-machine/internal/domain/beer"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+)
+
+type PlaylistOverrideHandler struct {
+	setUC    *beer.SetPlaylistOverrideUseCase
+	getUC    *beer.GetPlaylistOverrideUseCase
+	deleteUC *beer.DeletePlaylistOverrideUseCase
+}
+
+func NewPlaylistOverrideHandler(
+	setUC *beer.SetPlaylistOverrideUseCase,
+	getUC *beer.GetPlaylistOverrideUseCase,
+	deleteUC *beer.DeletePlaylistOverrideUseCase,
+) *PlaylistOverrideHandler {
+	return &PlaylistOverrideHandler{
+		setUC:    setUC,
+		getUC:    getUC,
+		deleteUC: deleteUC,
+	}
+}
+
+/*
+GET /beer-styles/{id}/playlist
+*/
+func (h *PlaylistOverrideHandler) Get(w http.ResponseWriter, r *http.Request) {
+	override, err := h.getUC.Execute(r.PathValue("id"))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toOverrideResponse(override))
+}
+
+/*
+PUT /beer-styles/{id}/playlist
+*/
+func (h *PlaylistOverrideHandler) Put(w http.ResponseWriter, r *http.Request) {
+	var req dto.PlaylistOverrideRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	override := beer.PlaylistOverride{
+		StyleID:     r.PathValue("id"),
+		ExternalURI: req.ExternalURI,
+	}
+
+	if req.Name != "" {
+		override.Playlist = toCuratedPlaylist(req)
+	}
+
+	if err := h.setUC.Execute(override); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toOverrideResponse(override))
+}
+
+/*
+DELETE /beer-styles/{id}/playlist
+*/
+func (h *PlaylistOverrideHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.deleteUC.Execute(r.PathValue("id")); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toCuratedPlaylist(req dto.PlaylistOverrideRequest) beer.Playlist {
+	playlist := beer.Playlist{
+		Name:        req.Name,
+		Description: req.Description,
+		Link:        req.Link,
+		Images:      toImages(req.Images),
+		Provider:    beer.CuratedProviderName,
+		TotalTracks: len(req.Tracks),
+		Tracks:      make([]beer.Track, 0, len(req.Tracks)),
+	}
+
+	for _, t := range req.Tracks {
+		track := beer.Track{
+			Name:        t.Name,
+			Artists:     append([]string{}, t.Artists...),
+			Album:       t.Album,
+			AlbumImages: toImages(t.AlbumImages),
+			Duration:    time.Duration(t.DurationMs) * time.Millisecond,
+			Explicit:    t.Explicit,
+			PreviewURL:  t.PreviewURL,
+			Link:        t.Link,
+		}
+
+		if len(t.Artists) > 0 {
+			track.Artist = t.Artists[0]
+		}
+
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	return playlist
+}
+
+func toImages(urls []string) []beer.Image {
+	images := make([]beer.Image, 0, len(urls))
+	for _, u := range urls {
+		images = append(images, beer.Image{URL: u})
+	}
+	return images
+}
+
+func toOverrideResponse(o beer.PlaylistOverride) dto.PlaylistOverrideResponse {
+	resp := dto.PlaylistOverrideResponse{
+		StyleID:     o.StyleID,
+		ExternalURI: o.ExternalURI,
+	}
+
+	if o.ExternalURI == "" {
+		playlist := toPlaylistResponse(o.Playlist)
+		resp.Playlist = &playlist
+	}
+
+	return resp
+}
+
+func (h *PlaylistOverrideHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, beer.ErrInvalidPlaylistOverride),
+		errors.Is(err, beer.ErrInvalidPlaylistURI):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrBeerStyleNotFound),
+		errors.Is(err, beer.ErrPlaylistOverrideNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,232 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/beer"
+	domain "karhub-beer-machine/internal/domain/beer"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+/*
+	Helper to build test server with playlist overrides
+*/
+
+func setupOverrideServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	repo, err := memory.NewBeerStyleRepository()
+	if err != nil {
+		t.Fatalf("failed to create repo: %v", err)
+	}
+
+	_ = repo.Create(domain.BeerStyle{ID: "1", Name: "Dunkel", MinTemp: -8, MaxTemp: 2})
+	_ = repo.Create(domain.BeerStyle{ID: "2", Name: "IPA", MinTemp: -7, MaxTemp: 10})
+
+	overrides := memory.NewPlaylistOverrideRepository()
+
+	music := beer.NewOverridingMusicProvider(
+		&spotifyMock{playlist: beer.Playlist{Name: "Search Result"}},
+		overrides,
+		nil,
+	)
+
+	beerHandler := handlers.NewBeerHandler(
+		beer.NewCreateBeerStyleUseCase(repo),
+		beer.NewUpdateBeerStyleUseCase(repo),
+		beer.NewDeleteBeerStyleUseCase(repo, overrides),
+		beer.NewListBeerStylesUseCase(repo),
+		beer.NewFindBestBeerStyleUseCase(repo, music),
+	)
+
+	overrideHandler := handlers.NewPlaylistOverrideHandler(
+		beer.NewSetPlaylistOverrideUseCase(repo, overrides),
+		beer.NewGetPlaylistOverrideUseCase(overrides),
+		beer.NewDeletePlaylistOverrideUseCase(overrides),
+	)
+
+	mux := http.NewServeMux()
+	// Anonymous callers are admins here: these tests are about the handlers
+	guard := middleware.NewGuard(auth.NewChainAuthenticator(), auth.RoleAdmin)
+	httpapi.RegisterRoutes(mux, beerHandler, guard)
+	httpapi.RegisterPlaylistOverrideRoutes(mux, overrideHandler, guard)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doJSON(t *testing.T, method, url string, body any) *http.Response {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+
+	req, err := http.NewRequest(method, url, &buf)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+/*
+	TESTS
+*/
+
+func TestPlaylistOverrideHTTP(t *testing.T) {
+	curated := map[string]any{
+		"name": "Staff Picks",
+		"tracks": []map[string]any{
+			{"name": "Dark Roast", "artists": []string{"Malt Society"}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           any
+		wantStatusCode int
+	}{
+		{
+			name:           "pin curated playlist",
+			method:         http.MethodPut,
+			path:           "/beer-styles/1/playlist",
+			body:           curated,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "pin external playlist",
+			method:         http.MethodPut,
+			path:           "/beer-styles/2/playlist",
+			body:           map[string]any{"externalUri": "spotify:playlist:abc"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "pin to unknown style",
+			method:         http.MethodPut,
+			path:           "/beer-styles/missing/playlist",
+			body:           curated,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "pin non playlist uri",
+			method:         http.MethodPut,
+			path:           "/beer-styles/2/playlist",
+			body:           map[string]any{"externalUri": "https://example.com/playlist/abc"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "pin invalid override",
+			method:         http.MethodPut,
+			path:           "/beer-styles/1/playlist",
+			body:           map[string]any{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "get missing override",
+			method:         http.MethodGet,
+			path:           "/beer-styles/2/playlist",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "delete missing override",
+			method:         http.MethodDelete,
+			path:           "/beer-styles/2/playlist",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupOverrideServer(t)
+
+			resp := doJSON(t, tt.method, server.URL+tt.path, tt.body)
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestPlaylistOverrideHTTP_FollowsRename(t *testing.T) {
+	server := setupOverrideServer(t)
+
+	resp := doJSON(t, http.MethodPut, server.URL+"/beer-styles/1/playlist", map[string]any{
+		"name": "Staff Picks",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, http.MethodPut, server.URL+"/beer-styles/1", map[string]any{
+		"name":    "Munich Dunkel",
+		"minTemp": -8,
+		"maxTemp": 2,
+	})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, http.MethodPost, server.URL+"/beer-styles/best", map[string]any{
+		"temperature": -7,
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		BeerStyle string `json:"beerStyle"`
+		Playlist  struct {
+			Name     string `json:"name"`
+			Provider string `json:"provider"`
+		} `json:"playlist"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if out.BeerStyle != "Munich Dunkel" {
+		t.Errorf("expected beerStyle Munich Dunkel, got %s", out.BeerStyle)
+	}
+
+	if out.Playlist.Name != "Staff Picks" || out.Playlist.Provider != beer.CuratedProviderName {
+		t.Errorf("expected curated playlist, got %+v", out.Playlist)
+	}
+
+	// Removing the override restores search
+	resp = doJSON(t, http.MethodDelete, server.URL+"/beer-styles/1/playlist", nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, http.MethodPost, server.URL+"/beer-styles/best", map[string]any{
+		"temperature": -7,
+	})
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if out.Playlist.Name != "Search Result" {
+		t.Errorf("expected search playlist, got %+v", out.Playlist)
+	}
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/logging"
+)
+
+// APIKeyHeader carries an API key, as an alternative to a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// anonymousSubject names callers that present no credential.
+const anonymousSubject = "anonymous"
+
+type principalKey struct{}
+
+// admission is what Require stores in the request context.
+type admission struct {
+	principal     auth.Principal
+	authenticated bool
+}
+
+// Guard authenticates requests and checks their role.
+type Guard struct {
+	authenticator auth.Authenticator
+	anonymous     auth.Role
+	limiter       atomic.Pointer[RateLimiter]
+}
+
+// GuardOption configures a Guard.
+type GuardOption func(*Guard)
+
+// WithRateLimiter makes Limit apply the limits of limiter.
+func WithRateLimiter(limiter *RateLimiter) GuardOption {
+	return func(g *Guard) {
+		g.SetRateLimiter(limiter)
+	}
+}
+
+// NewGuard creates a Guard. Requests without credentials get the
+// anonymous role: auth.RoleReader keeps reads open, auth.RoleNone
+// requires credentials on every guarded route.
+func NewGuard(authenticator auth.Authenticator, anonymous auth.Role, opts ...GuardOption) *Guard {
+	g := &Guard{
+		authenticator: authenticator,
+		anonymous:     anonymous,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// SetRateLimiter replaces the limits Limit applies; nil lifts them.
+// Requests in flight finish under the limiter they started with.
+func (g *Guard) SetRateLimiter(limiter *RateLimiter) {
+	g.limiter.Store(limiter)
+}
+
+// Require only lets requests through whose principal has at least role.
+//
+// A bearer token ("Authorization: Bearer <token>") or an X-API-Key header
+// is authenticated; an invalid one is rejected even if the route is open
+// to anonymous callers. Rejected credentials answer 401 Unauthorized,
+// valid ones with too low a role 403 Forbidden. Once an IP address has
+// used up its failed authentications (ClassAuthFailure), its credentials
+// are not even checked: it gets 429 Too Many Requests.
+func (g *Guard) Require(role auth.Role, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential, presented := credentialFrom(r)
+
+		principal := auth.Principal{Subject: anonymousSubject, Role: g.anonymous}
+
+		if presented {
+			if !g.allowAuthentication(w, r) {
+				return
+			}
+
+			var err error
+
+			principal, err = g.authenticator.Authenticate(r.Context(), credential)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidCredential) {
+					logging.FromContext(r.Context()).Error("authentication failed", "error", err)
+					http.Error(w, "authentication unavailable", http.StatusInternalServerError)
+					return
+				}
+
+				g.authenticationFailed(r)
+				unauthorized(w, `, error="invalid_token"`)
+				return
+			}
+		}
+
+		if !principal.Role.Allows(role) {
+			if !presented {
+				unauthorized(w, "")
+				return
+			}
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		admitted := admission{principal: principal, authenticated: presented}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, admitted)))
+	})
+}
+
+// PrincipalFrom returns the principal a Guard admitted the request with.
+func PrincipalFrom(ctx context.Context) (auth.Principal, bool) {
+	admitted, ok := ctx.Value(principalKey{}).(admission)
+	return admitted.principal, ok
+}
+
+// credentialFrom reads the bearer token or, failing that, the API key.
+// Any Authorization header counts as presented, so other schemes are
+// rejected instead of silently treated as anonymous.
+func credentialFrom(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, credential, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return "", true
+		}
+		return strings.TrimSpace(credential), true
+	}
+
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key, true
+	}
+
+	return "", false
+}
+
+func unauthorized(w http.ResponseWriter, challengeParams string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="karhub"`+challengeParams)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
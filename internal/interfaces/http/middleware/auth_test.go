@@ -0,0 +1,175 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/jwt"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+type failingAuthenticator struct{}
+
+func (failingAuthenticator) Authenticate(context.Context, string) (auth.Principal, error) {
+	return auth.Principal{}, errors.New("key store down")
+}
+
+func newGuard(t *testing.T, anonymous auth.Role) (*middleware.Guard, *jwt.HMACAuthenticator) {
+	t.Helper()
+
+	tokens, err := jwt.NewHMACAuthenticator([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "1", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("reader-key")})
+
+	authenticator := auth.NewChainAuthenticator(tokens, auth.NewAPIKeyAuthenticator(keys))
+
+	return middleware.NewGuard(authenticator, anonymous), tokens
+}
+
+/*
+	TESTS
+*/
+
+func TestGuard_Require(t *testing.T) {
+	guard, tokens := newGuard(t, auth.RoleReader)
+
+	adminJWT, _ := tokens.Sign("ci", auth.RoleAdmin, time.Hour)
+	readerJWT, _ := tokens.Sign("kiosk", auth.RoleReader, time.Hour)
+
+	tests := []struct {
+		name           string
+		role           auth.Role
+		headers        map[string]string
+		wantStatusCode int
+		wantSubject    string
+	}{
+		{
+			name:           "anonymous on a reader route",
+			role:           auth.RoleReader,
+			wantStatusCode: http.StatusOK,
+			wantSubject:    "anonymous",
+		},
+		{
+			name:           "anonymous on an admin route",
+			role:           auth.RoleAdmin,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "admin jwt",
+			role:           auth.RoleAdmin,
+			headers:        map[string]string{"Authorization": "Bearer " + adminJWT},
+			wantStatusCode: http.StatusOK,
+			wantSubject:    "ci",
+		},
+		{
+			name:           "reader jwt on an admin route",
+			role:           auth.RoleAdmin,
+			headers:        map[string]string{"Authorization": "Bearer " + readerJWT},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "api key as bearer",
+			role:           auth.RoleReader,
+			headers:        map[string]string{"Authorization": "Bearer reader-key"},
+			wantStatusCode: http.StatusOK,
+			wantSubject:    "dashboard",
+		},
+		{
+			name:           "api key header",
+			role:           auth.RoleReader,
+			headers:        map[string]string{middleware.APIKeyHeader: "reader-key"},
+			wantStatusCode: http.StatusOK,
+			wantSubject:    "dashboard",
+		},
+		{
+			name:           "unknown api key",
+			role:           auth.RoleReader,
+			headers:        map[string]string{middleware.APIKeyHeader: "guess"},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "other scheme",
+			role:           auth.RoleReader,
+			headers:        map[string]string{"Authorization": "Basic cmVhZGVyLWtleQ=="},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "empty bearer",
+			role:           auth.RoleReader,
+			headers:        map[string]string{"Authorization": "Bearer "},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject string
+
+			handler := guard.Require(tt.role, func(w http.ResponseWriter, r *http.Request) {
+				principal, _ := middleware.PrincipalFrom(r.Context())
+				subject = principal.Subject
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("expected a WWW-Authenticate challenge")
+			}
+
+			if subject != tt.wantSubject {
+				t.Errorf("expected subject %q, got %q", tt.wantSubject, subject)
+			}
+		})
+	}
+}
+
+func TestGuard_RequireCredentialsEverywhere(t *testing.T) {
+	guard, _ := newGuard(t, auth.RoleNone)
+
+	handler := guard.Require(auth.RoleReader, func(http.ResponseWriter, *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestGuard_AuthenticatorFailure(t *testing.T) {
+	guard := middleware.NewGuard(failingAuthenticator{}, auth.RoleReader)
+
+	handler := guard.Require(auth.RoleReader, func(http.ResponseWriter, *http.Request) {
+		t.Errorf("expected the request not to reach the handler")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.APIKeyHeader, "reader-key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,311 @@
+package middleware
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// DefaultMaxClients bounds how many clients a limiter tracks.
+const DefaultMaxClients = 10000
+
+// limiterShards spreads clients over independently locked LRU lists, so
+// concurrent requests from different clients rarely contend.
+const limiterShards = 16
+
+// Limit is a token bucket: Rate requests per second on average, in
+// bursts of up to Burst requests.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Decision is the answer of a limiter to one request.
+type Decision struct {
+	Allowed bool
+
+	// Limit is the number of requests allowed in a full window.
+	Limit int
+
+	// Remaining is how many more requests are allowed right now.
+	Remaining int
+
+	// Reset is how long until Remaining is back to Limit.
+	Reset time.Duration
+
+	// RetryAfter is how long a denied client should wait.
+	RetryAfter time.Duration
+}
+
+// LimiterOption configures a TokenBucketLimiter or a DailyQuota.
+type LimiterOption func(*limiterConfig)
+
+type limiterConfig struct {
+	maxClients int
+	now        func() time.Time
+}
+
+// WithMaxClients bounds how many clients are tracked: beyond it, the
+// least recently seen client is forgotten (and starts afresh).
+func WithMaxClients(n int) LimiterOption {
+	return func(c *limiterConfig) {
+		c.maxClients = n
+	}
+}
+
+// WithLimiterClock replaces time.Now, for tests.
+func WithLimiterClock(now func() time.Time) LimiterOption {
+	return func(c *limiterConfig) {
+		c.now = now
+	}
+}
+
+func newLimiterConfig(opts []LimiterOption) limiterConfig {
+	c := limiterConfig{
+		maxClients: DefaultMaxClients,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+/*
+	---------- Token bucket ----------
+*/
+
+// TokenBucketLimiter keeps one token bucket per client. It is safe for
+// concurrent use, and its memory is bounded by the max clients.
+type TokenBucketLimiter struct {
+	rate    float64
+	burst   float64
+	now     func() time.Time
+	buckets *clientTable[tokenBucket]
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter creates a TokenBucketLimiter. A burst below 1
+// is raised to 1.
+func NewTokenBucketLimiter(limit Limit, opts ...LimiterOption) *TokenBucketLimiter {
+	c := newLimiterConfig(opts)
+
+	return &TokenBucketLimiter{
+		rate:    limit.Rate,
+		burst:   float64(max(limit.Burst, 1)),
+		now:     c.now,
+		buckets: newClientTable[tokenBucket](c.maxClients),
+	}
+}
+
+// Allow takes a token from the bucket of client, if there is one.
+func (l *TokenBucketLimiter) Allow(client string) Decision {
+	now := l.now()
+
+	return l.buckets.update(client, func(b *tokenBucket, created bool) Decision {
+		if created {
+			b.tokens = l.burst
+		} else {
+			b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+		}
+		b.last = now
+
+		d := Decision{Limit: int(l.burst)}
+
+		if b.tokens >= 1 {
+			b.tokens--
+			d.Allowed = true
+		} else {
+			d.RetryAfter = l.duration(1 - b.tokens)
+		}
+
+		d.Remaining = int(b.tokens)
+		d.Reset = l.duration(l.burst - b.tokens)
+		return d
+	})
+}
+
+// Peek reports whether client has a token left, without taking it or
+// starting to track the client.
+func (l *TokenBucketLimiter) Peek(client string) Decision {
+	now := l.now()
+
+	d := Decision{Allowed: true, Limit: int(l.burst), Remaining: int(l.burst)}
+
+	l.buckets.view(client, func(b *tokenBucket) {
+		tokens := min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+
+		d.Allowed = tokens >= 1
+		d.Remaining = int(tokens)
+		d.Reset = l.duration(l.burst - tokens)
+		if !d.Allowed {
+			d.RetryAfter = l.duration(1 - tokens)
+		}
+	})
+
+	return d
+}
+
+// Window is how long an empty bucket takes to fill up.
+func (l *TokenBucketLimiter) Window() time.Duration {
+	return l.duration(l.burst)
+}
+
+// duration is how long the bucket takes to gain tokens.
+func (l *TokenBucketLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+/*
+	---------- Daily quota ----------
+*/
+
+// DailyQuota counts the requests of each client per UTC day. It is safe
+// for concurrent use.
+//
+// Unlike token buckets, counts are never evicted: a forgotten client
+// would get a fresh quota, so clients cycling through could reset anyone
+// else's. The store is emptied at the day boundary instead, so its memory
+// is bounded by the authenticated clients seen in a day.
+type DailyQuota struct {
+	limit  int
+	now    func() time.Time
+	seed   maphash.Seed
+	shards [limiterShards]quotaShard
+}
+
+// quotaShard holds the counts of a share of the clients for one day.
+type quotaShard struct {
+	mu   sync.Mutex
+	day  time.Time
+	used map[string]int
+}
+
+// NewDailyQuota creates a DailyQuota allowing limit requests per client
+// and day. WithMaxClients does not apply to it.
+func NewDailyQuota(limit int, opts ...LimiterOption) *DailyQuota {
+	c := newLimiterConfig(opts)
+
+	return &DailyQuota{
+		limit: limit,
+		now:   c.now,
+		seed:  maphash.MakeSeed(),
+	}
+}
+
+// Allow counts a request of client, unless its quota is used up.
+func (q *DailyQuota) Allow(client string) Decision {
+	now := q.now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	untilTomorrow := today.Add(24 * time.Hour).Sub(now)
+
+	s := &q.shards[maphash.String(q.seed, client)%limiterShards]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.day.Equal(today) {
+		s.day, s.used = today, make(map[string]int)
+	}
+
+	d := Decision{Limit: q.limit, Reset: untilTomorrow}
+
+	if s.used[client] < q.limit {
+		s.used[client]++
+		d.Allowed = true
+	} else {
+		d.RetryAfter = untilTomorrow
+	}
+
+	d.Remaining = q.limit - s.used[client]
+	return d
+}
+
+/*
+	---------- Bounded client table ----------
+*/
+
+// clientTable maps clients to state, forgetting the least recently seen
+// clients beyond its capacity.
+type clientTable[V any] struct {
+	seed   maphash.Seed
+	shards [limiterShards]clientShard[V]
+}
+
+type clientShard[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    list.List // front = most recently seen
+}
+
+type clientEntry[V any] struct {
+	client string
+	value  V
+}
+
+func newClientTable[V any](maxClients int) *clientTable[V] {
+	t := &clientTable[V]{seed: maphash.MakeSeed()}
+
+	capacity := max(maxClients/limiterShards, 1)
+	for i := range t.shards {
+		t.shards[i].capacity = capacity
+		t.shards[i].entries = make(map[string]*list.Element)
+	}
+
+	return t
+}
+
+// update calls fn with the state of client, created if needed, under the
+// lock of its shard.
+func (t *clientTable[V]) update(client string, fn func(value *V, created bool) Decision) Decision {
+	s := &t.shards[maphash.String(t.seed, client)%limiterShards]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, found := s.entries[client]; found {
+		s.order.MoveToFront(el)
+		return fn(&el.Value.(*clientEntry[V]).value, false)
+	}
+
+	if s.order.Len() >= s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*clientEntry[V]).client)
+	}
+
+	entry := &clientEntry[V]{client: client}
+	s.entries[client] = s.order.PushFront(entry)
+
+	return fn(&entry.value, true)
+}
+
+// view calls fn with the state of client, if it is tracked, under the
+// lock of its shard. It does not count as seeing the client.
+func (t *clientTable[V]) view(client string, fn func(value *V)) {
+	s := &t.shards[maphash.String(t.seed, client)%limiterShards]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, found := s.entries[client]; found {
+		fn(&el.Value.(*clientEntry[V]).value)
+	}
+}
+
+// len returns how many clients are tracked.
+func (t *clientTable[V]) len() int {
+	n := 0
+	for i := range t.shards {
+		t.shards[i].mu.Lock()
+		n += t.shards[i].order.Len()
+		t.shards[i].mu.Unlock()
+	}
+	return n
+}
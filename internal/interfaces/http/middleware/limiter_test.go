@@ -0,0 +1,241 @@
+package middleware_test
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// fakeClock is a settable clock, safe for concurrent use.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+/*
+	TESTS
+*/
+
+func TestTokenBucketLimiter_Allow(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	limiter := middleware.NewTokenBucketLimiter(
+		middleware.Limit{Rate: 2, Burst: 3},
+		middleware.WithLimiterClock(clock.Now),
+	)
+
+	steps := []struct {
+		name          string
+		advance       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantReset     time.Duration
+		wantRetry     time.Duration
+	}{
+		{name: "full bucket", wantAllowed: true, wantRemaining: 2, wantReset: 500 * time.Millisecond},
+		{name: "second of the burst", wantAllowed: true, wantRemaining: 1, wantReset: time.Second},
+		{name: "last of the burst", wantAllowed: true, wantRemaining: 0, wantReset: 1500 * time.Millisecond},
+		{name: "empty bucket", wantAllowed: false, wantRemaining: 0, wantReset: 1500 * time.Millisecond, wantRetry: 500 * time.Millisecond},
+		{name: "half a token later", advance: 250 * time.Millisecond, wantAllowed: false, wantRemaining: 0, wantReset: 1250 * time.Millisecond, wantRetry: 250 * time.Millisecond},
+		{name: "refilled one token", advance: 250 * time.Millisecond, wantAllowed: true, wantRemaining: 0, wantReset: 1500 * time.Millisecond},
+		{name: "refill caps at the burst", advance: time.Hour, wantAllowed: true, wantRemaining: 2, wantReset: 500 * time.Millisecond},
+	}
+
+	for _, step := range steps {
+		clock.Advance(step.advance)
+
+		d := limiter.Allow("kiosk")
+
+		if d.Allowed != step.wantAllowed {
+			t.Fatalf("%s: expected allowed %v, got %v", step.name, step.wantAllowed, d.Allowed)
+		}
+		if d.Limit != 3 {
+			t.Fatalf("%s: expected limit 3, got %d", step.name, d.Limit)
+		}
+		if d.Remaining != step.wantRemaining {
+			t.Fatalf("%s: expected remaining %d, got %d", step.name, step.wantRemaining, d.Remaining)
+		}
+		if d.Reset != step.wantReset {
+			t.Fatalf("%s: expected reset %v, got %v", step.name, step.wantReset, d.Reset)
+		}
+		if d.RetryAfter != step.wantRetry {
+			t.Fatalf("%s: expected retry after %v, got %v", step.name, step.wantRetry, d.RetryAfter)
+		}
+	}
+
+	if window := limiter.Window(); window != 1500*time.Millisecond {
+		t.Fatalf("expected window 1.5s, got %v", window)
+	}
+}
+
+func TestTokenBucketLimiter_ClientsAreIndependent(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	limiter := middleware.NewTokenBucketLimiter(
+		middleware.Limit{Rate: 1, Burst: 1},
+		middleware.WithLimiterClock(clock.Now),
+	)
+
+	if !limiter.Allow("a").Allowed {
+		t.Fatalf("expected first request of a to be allowed")
+	}
+	if limiter.Allow("a").Allowed {
+		t.Fatalf("expected second request of a to be denied")
+	}
+	if !limiter.Allow("b").Allowed {
+		t.Fatalf("expected b not to be limited by a")
+	}
+}
+
+func TestTokenBucketLimiter_ForgetsLeastRecentlySeenClients(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	limiter := middleware.NewTokenBucketLimiter(
+		middleware.Limit{Rate: 1, Burst: 1},
+		middleware.WithLimiterClock(clock.Now),
+		middleware.WithMaxClients(16),
+	)
+
+	limiter.Allow("kiosk")
+	if limiter.Allow("kiosk").Allowed {
+		t.Fatalf("expected kiosk to be limited")
+	}
+
+	for i := range 1000 {
+		limiter.Allow("client-" + strconv.Itoa(i))
+	}
+
+	// Evicted, the kiosk starts again with a full bucket
+	if !limiter.Allow("kiosk").Allowed {
+		t.Fatalf("expected kiosk to be forgotten")
+	}
+}
+
+func TestTokenBucketLimiter_Concurrent(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	limiter := middleware.NewTokenBucketLimiter(
+		middleware.Limit{Rate: 1, Burst: 100},
+		middleware.WithLimiterClock(clock.Now),
+		middleware.WithMaxClients(64),
+	)
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int64
+	)
+
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				if limiter.Allow("shared").Allowed {
+					allowed.Add(1)
+				}
+				limiter.Allow("client-" + strconv.Itoa(g*50+i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The clock is frozen: exactly the burst gets through
+	if got := allowed.Load(); got != 100 {
+		t.Fatalf("expected 100 allowed requests, got %d", got)
+	}
+}
+
+func TestDailyQuota_Allow(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 22, 0, 0, 0, time.UTC))
+	quota := middleware.NewDailyQuota(2, middleware.WithLimiterClock(clock.Now))
+
+	for i, wantRemaining := range []int{1, 0} {
+		d := quota.Allow("key:1")
+		if !d.Allowed || d.Remaining != wantRemaining {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", i, wantRemaining, d)
+		}
+		if d.Reset != 2*time.Hour {
+			t.Fatalf("request %d: expected reset at midnight in 2h, got %v", i, d.Reset)
+		}
+	}
+
+	d := quota.Allow("key:1")
+	if d.Allowed {
+		t.Fatalf("expected quota to be exceeded")
+	}
+	if d.RetryAfter != 2*time.Hour {
+		t.Fatalf("expected retry after 2h, got %v", d.RetryAfter)
+	}
+
+	if !quota.Allow("key:2").Allowed {
+		t.Fatalf("expected another key to have its own quota")
+	}
+
+	clock.Advance(2 * time.Hour)
+
+	d = quota.Allow("key:1")
+	if !d.Allowed || d.Remaining != 1 {
+		t.Fatalf("expected quota to reset at midnight UTC, got %+v", d)
+	}
+}
+
+func TestDailyQuota_NeverForgetsClients(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	quota := middleware.NewDailyQuota(1, middleware.WithLimiterClock(clock.Now), middleware.WithMaxClients(1))
+
+	if !quota.Allow("key:1").Allowed {
+		t.Fatalf("expected the first request to be allowed")
+	}
+
+	// Enough clients to push key:1 out of any bounded table
+	for i := range 100 {
+		quota.Allow("key:other-" + strconv.Itoa(i))
+	}
+
+	if quota.Allow("key:1").Allowed {
+		t.Fatalf("expected key:1 to keep its used up quota")
+	}
+
+	clock.Advance(12 * time.Hour)
+
+	if !quota.Allow("key:1").Allowed {
+		t.Fatalf("expected quota to reset at midnight UTC")
+	}
+}
+
+/*
+	BENCHMARKS
+*/
+
+func BenchmarkTokenBucketLimiter_Allow(b *testing.B) {
+	limiter := middleware.NewTokenBucketLimiter(middleware.Limit{Rate: 1e9, Burst: 1e9})
+
+	clients := make([]string, 1024)
+	for i := range clients {
+		clients[i] = "ip:10.0.0." + strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+
+	i := 0
+	for b.Loop() {
+		limiter.Allow(clients[i%len(clients)])
+		i++
+	}
+}
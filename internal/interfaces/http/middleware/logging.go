@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/application/tracing"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+)
+
+// Chain wraps handler with middlewares, the first one outermost.
+func Chain(handler http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// AccessLog logs one line per request, with its method, route, status,
+// latency and response size. It also stores a logger tagged with the
+// request ID, and trace ID when traced, in the request context (see
+// logging.FromContext), so it goes after RequestID and Trace.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestLogger := logger
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				requestLogger = requestLogger.With("request_id", id)
+			}
+			if sc := tracing.SpanContextFromContext(r.Context()); sc.IsValid() {
+				requestLogger = requestLogger.With("trace_id", sc.TraceID.String())
+			}
+
+			inner := r.WithContext(logging.WithLogger(r.Context(), requestLogger))
+			rec := newResponseRecorder(w)
+
+			next.ServeHTTP(rec, inner)
+			propagatePattern(r, inner)
+
+			level := slog.LevelInfo
+			if rec.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			// The mux records the matched pattern on the request
+			requestLogger.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("route", r.Pattern),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes", rec.bytes),
+			)
+		})
+	}
+}
+
+// Recover turns a panicking handler into a 500 problem response, and
+// logs the panic with its stack. It goes after AccessLog, so the request
+// is logged with its 500 status and the panic with its request ID.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := newResponseRecorder(w)
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			// The handler aborted the response on purpose
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			logging.FromContext(r.Context()).Error("panic serving request",
+				"panic", fmt.Sprint(v),
+				"stack", string(debug.Stack()),
+			)
+
+			// Too late for a response: let the client see a broken one
+			if rec.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+
+			writeProblem(w, r, http.StatusInternalServerError, "the server failed to handle the request")
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// propagatePattern copies the route pattern the mux recorded on inner,
+// a copy of r with a new context, back to r for outer middleware.
+func propagatePattern(r, inner *http.Request) {
+	r.Pattern = inner.Pattern
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(dto.ProblemResponse{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: RequestIDFromContext(r.Context()),
+	})
+}
+
+// responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,192 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"karhub-beer-machine/internal/application/logging"
+	"karhub-beer-machine/internal/interfaces/http/dto"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// logRecords decodes the JSON lines written by a slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func newObservedServer(buf *bytes.Buffer, mux *http.ServeMux) http.Handler {
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	return middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.AccessLog(logger),
+		middleware.Recover,
+	)
+}
+
+/*
+	TESTS
+*/
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{name: "propagated", incoming: "req-42.a:b", wantSame: true},
+		{name: "generated when missing", incoming: ""},
+		{name: "generated when malformed", incoming: "bad id\r\nX-Injected: 1"},
+		{name: "generated when too long", incoming: strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			h := middleware.RequestID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				seen = middleware.RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.incoming)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(middleware.RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("expected the response and context to share an ID, got %q and %q", got, seen)
+			}
+			if (got == tt.incoming) != tt.wantSame {
+				t.Fatalf("expected propagated %v, got %q for %q", tt.wantSame, got, tt.incoming)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /beer-styles/{id}/playlist", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("looking up playlist")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/beer-styles/ipa/playlist", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+
+	newObservedServer(&buf, mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d", len(records))
+	}
+
+	// Loggers from the context carry the request ID
+	if records[0]["msg"] != "looking up playlist" || records[0]["request_id"] != "req-1" {
+		t.Fatalf("expected a handler record with the request ID, got %v", records[0])
+	}
+
+	access := records[1]
+	want := map[string]any{
+		"msg":        "request",
+		"level":      "INFO",
+		"request_id": "req-1",
+		"method":     "GET",
+		"route":      "GET /beer-styles/{id}/playlist",
+		"path":       "/beer-styles/ipa/playlist",
+		"status":     float64(http.StatusNotFound),
+		"bytes":      float64(len("not found")),
+	}
+	for k, v := range want {
+		if access[k] != v {
+			t.Fatalf("expected %s %v, got %v", k, v, access[k])
+		}
+	}
+	if _, ok := access["latency"]; !ok {
+		t.Fatalf("expected a latency, got %v", access)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/boom", func(http.ResponseWriter, *http.Request) {
+		panic("kaboom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-2")
+	rec := httptest.NewRecorder()
+
+	newObservedServer(&buf, mux).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected a problem response, got %q", ct)
+	}
+
+	var problem dto.ProblemResponse
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if problem.Status != http.StatusInternalServerError || problem.Instance != "/boom" || problem.RequestID != "req-2" {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+	if strings.Contains(problem.Detail, "kaboom") {
+		t.Fatalf("expected the panic not to leak to clients, got %q", problem.Detail)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d", len(records))
+	}
+
+	panicked := records[0]
+	if panicked["panic"] != "kaboom" || panicked["request_id"] != "req-2" {
+		t.Fatalf("expected the panic logged with its request ID, got %v", panicked)
+	}
+	if stack, _ := panicked["stack"].(string); !strings.Contains(stack, "TestRecover") {
+		t.Fatalf("expected the stack to be logged, got %q", stack)
+	}
+
+	if records[1]["status"] != float64(http.StatusInternalServerError) || records[1]["level"] != "ERROR" {
+		t.Fatalf("expected the request logged as a 500 error, got %v", records[1])
+	}
+}
+
+func TestRecover_AbortHandler(t *testing.T) {
+	h := middleware.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to propagate, got %v", v)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
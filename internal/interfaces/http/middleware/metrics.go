@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// unmatchedRoute labels requests no route matched, so stray paths do
+// not each become a series.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a nonstandard method, which the
+// client chooses freely.
+const otherMethod = "OTHER"
+
+// RequestObserver is told about every request served, e.g. to export
+// metrics.
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, latency time.Duration)
+}
+
+// Instrument reports every request to observer, labeled by the route
+// pattern it matched rather than its path, and by its method if it is a
+// standard one (OTHER otherwise). It goes before Recover, so
+// panics are reported as 500s.
+func Instrument(observer RequestObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newResponseRecorder(w)
+
+			next.ServeHTTP(rec, r)
+
+			// The mux records the matched pattern on the request
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			observer.ObserveRequest(methodLabel(r.Method), route, rec.status, time.Since(start))
+		})
+	}
+}
+
+// methodLabel returns method if it is a standard HTTP method, and
+// otherMethod otherwise.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type requestObserverMock struct {
+	mu       sync.Mutex
+	requests []observedRequest
+}
+
+func (m *requestObserverMock) ObserveRequest(method, route string, status int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, observedRequest{method, route, status})
+}
+
+/*
+	TESTS
+*/
+
+func TestInstrument(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /beer-styles/{id}/playlist", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/boom", func(http.ResponseWriter, *http.Request) {
+		panic("kaboom")
+	})
+
+	observer := &requestObserverMock{}
+	h := middleware.Chain(mux, middleware.Instrument(observer), middleware.Recover)
+
+	tests := []struct {
+		method string
+		path   string
+		want   observedRequest
+	}{
+		{
+			path: "/beer-styles/ipa/playlist",
+			want: observedRequest{http.MethodGet, "GET /beer-styles/{id}/playlist", http.StatusNoContent},
+		},
+		{
+			path: "/beer-styles/stout/playlist",
+			want: observedRequest{http.MethodGet, "GET /beer-styles/{id}/playlist", http.StatusNoContent},
+		},
+		{
+			path: "/no/such/route",
+			want: observedRequest{http.MethodGet, "unmatched", http.StatusNotFound},
+		},
+		{
+			path: "/boom",
+			want: observedRequest{http.MethodGet, "/boom", http.StatusInternalServerError},
+		},
+		{
+			method: "X-RANDOM-1f3a",
+			path:   "/no/such/route",
+			want:   observedRequest{"OTHER", "unmatched", http.StatusNotFound},
+		},
+	}
+
+	for i, tt := range tests {
+		method := tt.method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, tt.path, nil))
+
+		if got := observer.requests[i]; got != tt.want {
+			t.Fatalf("%s: expected %+v, got %+v", tt.path, tt.want, got)
+		}
+	}
+}
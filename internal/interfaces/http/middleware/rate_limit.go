@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Route classes, each with its own token bucket per client.
+const (
+	ClassRead      = "read"
+	ClassRecommend = "recommend"
+	ClassWrite     = "write"
+	ClassAdmin     = "admin"
+
+	// ClassAuthFailure limits rejected credentials per IP address, on
+	// every guarded route. It is checked before authenticating, so
+	// credentials cannot be guessed faster than it allows.
+	ClassAuthFailure = "auth_failure"
+)
+
+// quotaWindow is the window of daily quotas, in RateLimit-Policy.
+const quotaWindow = 24 * time.Hour
+
+// RateLimiter limits requests per client: a token bucket per route class
+// and, for authenticated callers, an optional daily quota shared by all
+// classes.
+//
+// API keys are limited by key, bearer tokens by subject, and anonymous
+// callers by IP address.
+type RateLimiter struct {
+	classes       map[string]*TokenBucketLimiter
+	quota         *DailyQuota
+	forwardedHops int
+}
+
+// RateLimiterOption configures a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithDailyQuota caps the requests of each authenticated caller per day.
+func WithDailyQuota(quota *DailyQuota) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.quota = quota
+	}
+}
+
+// WithForwardedHops trusts the given number of reverse proxies in front
+// of the server: the client IP is then read from X-Forwarded-For, that
+// many entries from the right. Zero, the default, uses the peer address.
+func WithForwardedHops(hops int) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.forwardedHops = hops
+	}
+}
+
+// NewRateLimiter creates a RateLimiter. Route classes without a limiter
+// are only subject to the daily quota.
+func NewRateLimiter(classes map[string]*TokenBucketLimiter, opts ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{classes: classes}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Limit applies the rate limits of class to the requests Require admitted.
+// The rate limiter is looked up on every request, so one set with
+// SetRateLimiter applies to the routes already registered.
+//
+// Responses carry RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// and RateLimit-Policy headers for the most restrictive limit; rejected
+// requests answer 429 Too Many Requests with Retry-After.
+func (g *Guard) Limit(class string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := g.limiter.Load()
+		if l == nil {
+			next(w, r)
+			return
+		}
+
+		bucket := l.classes[class]
+		if bucket == nil && l.quota == nil {
+			next(w, r)
+			return
+		}
+
+		client, authenticated := l.clientOf(r)
+
+		var (
+			decision Decision
+			policies []string
+			limited  bool
+			message  string
+		)
+
+		if bucket != nil {
+			decision = bucket.Allow(client)
+			policies = append(policies, policy(decision.Limit, bucket.Window()))
+			limited = true
+
+			if !decision.Allowed {
+				message = "rate limit exceeded"
+			}
+		}
+
+		if l.quota != nil && authenticated {
+			policies = append(policies, policy(l.quota.limit, quotaWindow))
+
+			// A throttled request does not count against the quota
+			if message == "" {
+				quota := l.quota.Allow(client)
+
+				if !limited || !quota.Allowed || quota.Remaining < decision.Remaining {
+					decision = quota
+				}
+				limited = true
+
+				if !quota.Allowed {
+					message = "daily quota exceeded"
+				}
+			}
+		}
+
+		if !limited {
+			next(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+		h.Set("RateLimit-Policy", strings.Join(policies, ", "))
+
+		if message != "" {
+			h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+			http.Error(w, message, http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// allowAuthentication answers 429 Too Many Requests, and returns false,
+// when the IP address of r has used up its failed authentications. No
+// token is taken: only failures do, through authenticationFailed.
+func (g *Guard) allowAuthentication(w http.ResponseWriter, r *http.Request) bool {
+	l := g.limiter.Load()
+	if l == nil || l.classes[ClassAuthFailure] == nil {
+		return true
+	}
+
+	decision := l.classes[ClassAuthFailure].Peek("ip:" + l.clientIP(r))
+	if decision.Allowed {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+	http.Error(w, "too many failed authentications", http.StatusTooManyRequests)
+	return false
+}
+
+// authenticationFailed takes a failed authentication token from the IP
+// address of r.
+func (g *Guard) authenticationFailed(r *http.Request) {
+	l := g.limiter.Load()
+	if l == nil || l.classes[ClassAuthFailure] == nil {
+		return
+	}
+
+	l.classes[ClassAuthFailure].Allow("ip:" + l.clientIP(r))
+}
+
+// clientOf identifies the caller of a request admitted by Require, and
+// reports whether it is authenticated.
+func (l *RateLimiter) clientOf(r *http.Request) (string, bool) {
+	if a, ok := r.Context().Value(principalKey{}).(admission); ok && a.authenticated {
+		if a.principal.KeyID != "" {
+			return "key:" + a.principal.KeyID, true
+		}
+		return "sub:" + a.principal.Subject, true
+	}
+
+	return "ip:" + l.clientIP(r), false
+}
+
+// clientIP returns the peer address or, behind trusted proxies, the
+// address the outermost of them saw. Entries further left are written
+// by the client and can be spoofed.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.forwardedHops > 0 {
+		var hops []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			for _, hop := range strings.Split(header, ",") {
+				hops = append(hops, strings.TrimSpace(hop))
+			}
+		}
+
+		if i := len(hops) - l.forwardedHops; i >= 0 && hops[i] != "" {
+			return hops[i]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func policy(limit int, window time.Duration) string {
+	return fmt.Sprintf("%d;w=%d", limit, ceilSeconds(window))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,308 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/infrastructure/persistence/memory"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+func newLimitedGuard(t *testing.T, clock *fakeClock, opts ...middleware.RateLimiterOption) *middleware.Guard {
+	t.Helper()
+
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "1", Name: "kiosk", Role: auth.RoleReader, Hash: auth.HashAPIKey("kiosk-key")})
+	_ = keys.Save(auth.APIKey{ID: "2", Name: "dashboard", Role: auth.RoleReader, Hash: auth.HashAPIKey("dashboard-key")})
+
+	limiter := middleware.NewRateLimiter(
+		map[string]*middleware.TokenBucketLimiter{
+			middleware.ClassRecommend: middleware.NewTokenBucketLimiter(
+				middleware.Limit{Rate: 1, Burst: 2},
+				middleware.WithLimiterClock(clock.Now),
+			),
+		},
+		opts...,
+	)
+
+	return middleware.NewGuard(
+		auth.NewAPIKeyAuthenticator(keys),
+		auth.RoleReader,
+		middleware.WithRateLimiter(limiter),
+	)
+}
+
+func limitedHandler(guard *middleware.Guard, class string) http.Handler {
+	return guard.Require(auth.RoleReader, guard.Limit(class, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func serve(h http.Handler, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/beer-styles/best", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+/*
+	TESTS
+*/
+
+func TestGuard_Limit(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	h := limitedHandler(newLimitedGuard(t, clock), middleware.ClassRecommend)
+
+	kiosk := map[string]string{middleware.APIKeyHeader: "kiosk-key"}
+
+	steps := []struct {
+		name           string
+		remoteAddr     string
+		headers        map[string]string
+		wantStatusCode int
+		wantRemaining  string
+	}{
+		{name: "first request", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusOK, wantRemaining: "1"},
+		{name: "second request", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusOK, wantRemaining: "0"},
+		{name: "burst exhausted", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusTooManyRequests, wantRemaining: "0"},
+		{name: "same key from another address", remoteAddr: "10.0.0.2:1000", headers: kiosk, wantStatusCode: http.StatusTooManyRequests, wantRemaining: "0"},
+		{name: "another key from the same address", remoteAddr: "10.0.0.1:1000", headers: map[string]string{middleware.APIKeyHeader: "dashboard-key"}, wantStatusCode: http.StatusOK, wantRemaining: "1"},
+		{name: "anonymous from the same address", remoteAddr: "10.0.0.1:1000", wantStatusCode: http.StatusOK, wantRemaining: "1"},
+	}
+
+	for _, step := range steps {
+		rec := serve(h, step.remoteAddr, step.headers)
+
+		if rec.Code != step.wantStatusCode {
+			t.Fatalf("%s: expected status %d, got %d", step.name, step.wantStatusCode, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Fatalf("%s: expected RateLimit-Limit 2, got %q", step.name, got)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != step.wantRemaining {
+			t.Fatalf("%s: expected RateLimit-Remaining %s, got %q", step.name, step.wantRemaining, got)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=2" {
+			t.Fatalf("%s: expected RateLimit-Policy 2;w=2, got %q", step.name, got)
+		}
+
+		retryAfter := rec.Header().Get("Retry-After")
+		if step.wantStatusCode == http.StatusTooManyRequests && retryAfter != "1" {
+			t.Fatalf("%s: expected Retry-After 1, got %q", step.name, retryAfter)
+		}
+		if step.wantStatusCode == http.StatusOK && retryAfter != "" {
+			t.Fatalf("%s: expected no Retry-After, got %q", step.name, retryAfter)
+		}
+	}
+
+	clock.Advance(time.Second)
+
+	if rec := serve(h, "10.0.0.1:1000", kiosk); rec.Code != http.StatusOK {
+		t.Fatalf("expected a token after a second, got status %d", rec.Code)
+	}
+}
+
+func TestGuard_LimitDailyQuota(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC))
+	guard := newLimitedGuard(t, clock, middleware.WithDailyQuota(
+		middleware.NewDailyQuota(3, middleware.WithLimiterClock(clock.Now)),
+	))
+
+	// The quota is shared by every class, limited or not
+	recommend := limitedHandler(guard, middleware.ClassRecommend)
+	read := limitedHandler(guard, middleware.ClassRead)
+
+	kiosk := map[string]string{middleware.APIKeyHeader: "kiosk-key"}
+
+	rec := serve(recommend, "10.0.0.1:1000", kiosk)
+	if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=2, 3;w=86400" {
+		t.Fatalf("expected both policies, got %q", got)
+	}
+
+	// The quota now has 2 left, the bucket 1: the bucket is reported
+	if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Fatalf("expected the bucket limit, got %q", got)
+	}
+
+	for range 2 {
+		if rec := serve(read, "10.0.0.1:1000", kiosk); rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+	}
+
+	rec = serve(read, "10.0.0.1:1000", kiosk)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "3600" {
+		t.Fatalf("expected Retry-After until midnight, got %q", got)
+	}
+	if got := rec.Header().Get("RateLimit-Limit"); got != "3" {
+		t.Fatalf("expected the quota limit, got %q", got)
+	}
+
+	// Anonymous callers are only rate limited
+	for range 5 {
+		if rec := serve(read, "10.0.0.1:1000", nil); rec.Code != http.StatusOK {
+			t.Fatalf("expected anonymous status 200, got %d", rec.Code)
+		}
+	}
+
+	clock.Advance(time.Hour)
+
+	if rec := serve(read, "10.0.0.1:1000", kiosk); rec.Code != http.StatusOK {
+		t.Fatalf("expected quota to reset, got status %d", rec.Code)
+	}
+}
+
+func TestGuard_LimitForwardedFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		hops      int
+		forwarded string
+		// sameAs is a second X-Forwarded-For expected to share the bucket
+		sameAs string
+	}{
+		{
+			name:      "peer address without trusted proxies",
+			forwarded: "203.0.113.7",
+			sameAs:    "198.51.100.9",
+		},
+		{
+			name:      "rightmost entry behind one proxy",
+			hops:      1,
+			forwarded: "198.51.100.9, 203.0.113.7",
+			sameAs:    "spoofed, 203.0.113.7",
+		},
+		{
+			name:      "peer address when the header is too short",
+			hops:      2,
+			forwarded: "203.0.113.7",
+			sameAs:    "198.51.100.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(time.Now())
+			h := limitedHandler(
+				newLimitedGuard(t, clock, middleware.WithForwardedHops(tt.hops)),
+				middleware.ClassRecommend,
+			)
+
+			for _, forwarded := range []string{tt.forwarded, tt.sameAs} {
+				serve(h, "10.0.0.1:1000", map[string]string{"X-Forwarded-For": forwarded})
+			}
+
+			rec := serve(h, "10.0.0.1:1000", map[string]string{"X-Forwarded-For": tt.forwarded})
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("expected a shared bucket, got status %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestGuard_LimitFailedAuthentications(t *testing.T) {
+	clock := newFakeClock(time.Now())
+
+	keys := memory.NewAPIKeyRepository()
+	_ = keys.Save(auth.APIKey{ID: "1", Name: "kiosk", Role: auth.RoleReader, Hash: auth.HashAPIKey("kiosk-key")})
+
+	guard := middleware.NewGuard(
+		auth.NewAPIKeyAuthenticator(keys),
+		auth.RoleReader,
+		middleware.WithRateLimiter(middleware.NewRateLimiter(map[string]*middleware.TokenBucketLimiter{
+			middleware.ClassAuthFailure: middleware.NewTokenBucketLimiter(
+				middleware.Limit{Rate: 1, Burst: 2},
+				middleware.WithLimiterClock(clock.Now),
+			),
+		})),
+	)
+	h := limitedHandler(guard, middleware.ClassRead)
+
+	wrong := map[string]string{middleware.APIKeyHeader: "guess"}
+	kiosk := map[string]string{middleware.APIKeyHeader: "kiosk-key"}
+
+	steps := []struct {
+		name           string
+		remoteAddr     string
+		headers        map[string]string
+		wantStatusCode int
+	}{
+		{name: "valid keys take no token", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusOK},
+		{name: "valid keys take no token", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusOK},
+		{name: "first wrong key", remoteAddr: "10.0.0.1:1000", headers: wrong, wantStatusCode: http.StatusUnauthorized},
+		{name: "second wrong key", remoteAddr: "10.0.0.1:1000", headers: wrong, wantStatusCode: http.StatusUnauthorized},
+		{name: "throttled before authenticating", remoteAddr: "10.0.0.1:1000", headers: wrong, wantStatusCode: http.StatusTooManyRequests},
+		{name: "even a valid key", remoteAddr: "10.0.0.1:1000", headers: kiosk, wantStatusCode: http.StatusTooManyRequests},
+		{name: "anonymous callers are not authenticated", remoteAddr: "10.0.0.1:1000", wantStatusCode: http.StatusOK},
+		{name: "another address", remoteAddr: "10.0.0.2:1000", headers: wrong, wantStatusCode: http.StatusUnauthorized},
+	}
+
+	for _, step := range steps {
+		rec := serve(h, step.remoteAddr, step.headers)
+
+		if rec.Code != step.wantStatusCode {
+			t.Fatalf("%s: expected status %d, got %d", step.name, step.wantStatusCode, rec.Code)
+		}
+		if step.wantStatusCode == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+			t.Fatalf("%s: expected Retry-After 1, got %q", step.name, rec.Header().Get("Retry-After"))
+		}
+	}
+
+	clock.Advance(time.Second)
+
+	if rec := serve(h, "10.0.0.1:1000", kiosk); rec.Code != http.StatusOK {
+		t.Fatalf("expected a token after a second, got status %d", rec.Code)
+	}
+}
+
+func TestGuard_LimitWithoutLimiter(t *testing.T) {
+	guard, _ := newGuard(t, auth.RoleReader)
+	h := limitedHandler(guard, middleware.ClassRecommend)
+
+	for range 10 {
+		rec := serve(h, "10.0.0.1:1000", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "" {
+			t.Fatalf("expected no rate limit headers, got %q", got)
+		}
+	}
+}
+
+func TestGuard_SetRateLimiter(t *testing.T) {
+	guard, _ := newGuard(t, auth.RoleReader)
+	h := limitedHandler(guard, middleware.ClassRecommend)
+
+	if rec := serve(h, "10.0.0.1:1000", nil); rec.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("expected no rate limit before one is set")
+	}
+
+	// Applies to the routes registered before it
+	guard.SetRateLimiter(middleware.NewRateLimiter(map[string]*middleware.TokenBucketLimiter{
+		middleware.ClassRecommend: middleware.NewTokenBucketLimiter(middleware.Limit{Rate: 1, Burst: 1}),
+	}))
+
+	if rec := serve(h, "10.0.0.1:1000", nil); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "1" {
+		t.Fatalf("expected the new limit to apply, got status %d and limit %q", rec.Code, rec.Header().Get("RateLimit-Limit"))
+	}
+	if rec := serve(h, "10.0.0.1:1000", nil); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rec.Code)
+	}
+
+	guard.SetRateLimiter(nil)
+
+	if rec := serve(h, "10.0.0.1:1000", nil); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("expected the limit to be lifted, got status %d and limit %q", rec.Code, rec.Header().Get("RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"karhub-beer-machine/internal/application/tracing"
+)
+
+// Trace serves every request within a server span, continuing the trace
+// of the caller when it sends a valid traceparent header. The span is
+// named after the route pattern the request matched. It goes before
+// AccessLog, so request logs carry the trace ID.
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if sc, ok := tracing.ParseTraceParent(r.Header.Get(tracing.TraceParentHeader)); ok {
+			ctx = tracing.ContextWithRemoteSpanContext(ctx, sc)
+		}
+
+		ctx, span := tracing.StartKind(ctx, tracing.SpanKindServer, r.Method,
+			tracing.String("http.request.method", r.Method),
+			tracing.String("url.path", r.URL.Path),
+		)
+		defer span.End()
+
+		inner := r.WithContext(ctx)
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, inner)
+		propagatePattern(r, inner)
+
+		route := r.Pattern
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		// Patterns such as "GET /beer-styles/{id}/playlist" name the method
+		name := route
+		if !strings.Contains(route, " ") {
+			name = r.Method + " " + route
+		}
+
+		span.SetName(name)
+		span.SetAttributes(
+			tracing.String("http.route", route),
+			tracing.Int("http.response.status_code", rec.status),
+		)
+		if rec.status >= http.StatusInternalServerError {
+			span.RecordError(errors.New(http.StatusText(rec.status)))
+		}
+	})
+}
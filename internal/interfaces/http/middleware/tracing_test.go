@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"karhub-beer-machine/internal/application/tracing"
+	"karhub-beer-machine/internal/infrastructure/otlp"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+/*
+	TESTS
+*/
+
+func TestTrace(t *testing.T) {
+	exporter := otlp.NewInMemoryExporter()
+	tracer := otlp.NewTracer(exporter)
+	tracing.SetDefault(tracer)
+	t.Cleanup(func() { tracing.SetDefault(nil) })
+
+	var handlerTrace tracing.SpanContext
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /beer-styles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerTrace = tracing.SpanContextFromContext(r.Context())
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var logs bytes.Buffer
+	h := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Trace,
+		middleware.AccessLog(slog.New(slog.NewJSONHandler(&logs, nil))),
+		middleware.Recover,
+	)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		traceParent string
+		wantName    string
+		wantRoute   string
+		wantStatus  int
+		wantFailed  bool
+	}{
+		{
+			name:        "continues the caller's trace",
+			method:      http.MethodGet,
+			path:        "/beer-styles/ipa",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantName:    "GET /beer-styles/{id}",
+			wantRoute:   "GET /beer-styles/{id}",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "starts a trace on a malformed traceparent",
+			method:      http.MethodGet,
+			path:        "/beer-styles/ipa",
+			traceParent: "00-nothex-00f067aa0ba902b7-01",
+			wantName:    "GET /beer-styles/{id}",
+			wantRoute:   "GET /beer-styles/{id}",
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "marks server errors",
+			method:     http.MethodPost,
+			path:       "/fail",
+			wantName:   "POST /fail",
+			wantRoute:  "/fail",
+			wantStatus: http.StatusServiceUnavailable,
+			wantFailed: true,
+		},
+		{
+			name:       "unmatched route",
+			method:     http.MethodGet,
+			path:       "/nowhere",
+			wantName:   "GET unmatched",
+			wantRoute:  "unmatched",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			logs.Reset()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.traceParent != "" {
+				req.Header.Set(tracing.TraceParentHeader, tt.traceParent)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if err := tracer.ForceFlush(context.Background()); err != nil {
+				t.Fatalf("failed to flush: %v", err)
+			}
+
+			spans := exporter.Spans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+
+			if span.Name != tt.wantName || span.Kind != tracing.SpanKindServer {
+				t.Errorf("expected server span %q, got %q", tt.wantName, span.Name)
+			}
+			if route, _ := span.Attribute("http.route"); route != tt.wantRoute {
+				t.Errorf("expected route %q, got %v", tt.wantRoute, route)
+			}
+			if status, _ := span.Attribute("http.response.status_code"); status != tt.wantStatus {
+				t.Errorf("expected status %d, got %v", tt.wantStatus, status)
+			}
+			if span.Failed() != tt.wantFailed {
+				t.Errorf("expected failed %v, got %v", tt.wantFailed, span.Failed())
+			}
+
+			remote, continued := tracing.ParseTraceParent(tt.traceParent)
+			if continued && (span.SpanContext.TraceID != remote.TraceID || span.ParentSpanID != remote.SpanID) {
+				t.Errorf("expected a child of the remote span, got %+v", span)
+			}
+			if !continued && span.ParentSpanID.IsValid() {
+				t.Errorf("expected a root span, got parent %s", span.ParentSpanID)
+			}
+
+			if tt.wantStatus == http.StatusOK && handlerTrace != span.SpanContext {
+				t.Errorf("expected the handler to run within the server span")
+			}
+
+			records := logRecords(t, &logs)
+			if len(records) != 1 || records[0]["trace_id"] != span.SpanContext.TraceID.String() {
+				t.Errorf("expected the access log to carry trace ID %s, got %v", span.SpanContext.TraceID, records)
+			}
+		})
+	}
+}
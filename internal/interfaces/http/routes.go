@@ -3,23 +3,28 @@ package http
 import (
 	"net/http"
 
+	"karhub-beer-machine/internal/application/auth"
 	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
 )
 
 // RegisterRoutes sets up the HTTP routes for the beer machine application.
-func RegisterRoutes(mux *http.ServeMux, h *handlers.BeerHandler) {
-	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status": "ok"}`))
-	})
+// Listing styles and recommendations need the reader role, catalog
+// changes the admin role. Each route is rate limited by its class.
+func RegisterRoutes(mux *http.ServeMux, h *handlers.BeerHandler, guard *middleware.Guard) {
+	create := guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassWrite, h.Create))
+	list := guard.Require(auth.RoleReader, guard.Limit(middleware.ClassRead, h.List))
+	update := guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassWrite, h.Update))
+	remove := guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassWrite, h.Delete))
+	findBest := guard.Require(auth.RoleReader, guard.Limit(middleware.ClassRecommend, h.FindBest))
 
 	// CRUD
 	mux.HandleFunc("/beer-styles", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			h.Create(w, r)
+			create.ServeHTTP(w, r)
 		case http.MethodGet:
-			h.List(w, r)
+			list.ServeHTTP(w, r)
 		default:
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -28,9 +33,9 @@ func RegisterRoutes(mux *http.ServeMux, h *handlers.BeerHandler) {
 	mux.HandleFunc("/beer-styles/", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPut:
-			h.Update(w, r)
+			update.ServeHTTP(w, r)
 		case http.MethodDelete:
-			h.Delete(w, r)
+			remove.ServeHTTP(w, r)
 		default:
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -42,6 +47,67 @@ func RegisterRoutes(mux *http.ServeMux, h *handlers.BeerHandler) {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		h.FindBest(w, r)
+		findBest.ServeHTTP(w, r)
 	})
 }
+
+// RegisterPlaylistOverrideRoutes sets up the admin routes that pin playlists
+// to beer styles; reading a pin only needs the reader role.
+func RegisterPlaylistOverrideRoutes(
+	mux *http.ServeMux,
+	h *handlers.PlaylistOverrideHandler,
+	guard *middleware.Guard,
+) {
+	mux.Handle("GET /beer-styles/{id}/playlist", guard.Require(auth.RoleReader, guard.Limit(middleware.ClassRead, h.Get)))
+	mux.Handle("PUT /beer-styles/{id}/playlist", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassWrite, h.Put)))
+	mux.Handle("DELETE /beer-styles/{id}/playlist", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassWrite, h.Delete)))
+}
+
+// RegisterCacheAdminRoutes sets up the routes to inspect the caches,
+// behind the operator role, and to purge them, behind the admin role.
+func RegisterCacheAdminRoutes(
+	mux *http.ServeMux,
+	h *handlers.CacheAdminHandler,
+	guard *middleware.Guard,
+) {
+	mux.Handle("GET /admin/caches", guard.Require(auth.RoleOperator, guard.Limit(middleware.ClassAdmin, h.Stats)))
+	mux.Handle("GET /admin/caches/{name}/keys", guard.Require(auth.RoleOperator, guard.Limit(middleware.ClassAdmin, h.Keys)))
+	mux.Handle("DELETE /admin/caches/{name}/keys", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.Purge)))
+}
+
+// RegisterAPIKeyRoutes sets up the routes to issue, list, revoke and
+// rotate API keys, all behind the admin role.
+func RegisterAPIKeyRoutes(
+	mux *http.ServeMux,
+	h *handlers.APIKeyHandler,
+	guard *middleware.Guard,
+) {
+	mux.Handle("POST /admin/api-keys", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.Create)))
+	mux.Handle("GET /admin/api-keys", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.List)))
+	mux.Handle("DELETE /admin/api-keys/{id}", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.Revoke)))
+	mux.Handle("POST /admin/api-keys/{id}/rotate", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.Rotate)))
+}
+
+// RegisterHealthRoutes serves the liveness and readiness probes. They
+// are neither authenticated nor rate limited, so orchestrators can always
+// reach them, and only tell the overall status. /health is kept as an
+// alias of /livez. The report detailing each dependency is behind the
+// operator role.
+func RegisterHealthRoutes(mux *http.ServeMux, h *handlers.HealthHandler, guard *middleware.Guard) {
+	mux.HandleFunc("GET /livez", h.Livez)
+	mux.HandleFunc("GET /health", h.Livez)
+	mux.HandleFunc("GET /readyz", h.Readyz)
+	mux.Handle("GET /admin/health", guard.Require(auth.RoleOperator, guard.Limit(middleware.ClassAdmin, h.Details)))
+}
+
+// RegisterMetricsRoutes serves the Prometheus metrics to readers. They
+// are not rate limited, so scrapes never go missing.
+func RegisterMetricsRoutes(mux *http.ServeMux, metrics http.Handler, guard *middleware.Guard) {
+	mux.Handle("GET /metrics", guard.Require(auth.RoleReader, metrics.ServeHTTP))
+}
+
+// RegisterConfigRoutes sets up the route reloading the configuration,
+// behind the admin role.
+func RegisterConfigRoutes(mux *http.ServeMux, h *handlers.ConfigHandler, guard *middleware.Guard) {
+	mux.Handle("POST /admin/config/reload", guard.Require(auth.RoleAdmin, guard.Limit(middleware.ClassAdmin, h.Reload)))
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"karhub-beer-machine/internal/application/health"
+)
+
+// Shutdown defaults.
+const (
+	DefaultGracePeriod  = 20 * time.Second
+	DefaultCloseTimeout = 5 * time.Second
+)
+
+// Server runs an *http.Server until its context is done, then shuts down
+// in order: readiness starts failing, new connections are refused,
+// in-flight requests drain within the grace period, and finally the
+// registered closers release the dependencies behind the handlers.
+type Server struct {
+	server       *http.Server
+	readiness    *health.ShutdownCheck
+	drainDelay   time.Duration
+	gracePeriod  time.Duration
+	closeTimeout time.Duration
+	closers      []closer
+}
+
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithReadiness makes check fail as soon as shutdown begins.
+func WithReadiness(check *health.ShutdownCheck) ServerOption {
+	return func(s *Server) {
+		s.readiness = check
+	}
+}
+
+// WithDrainDelay keeps accepting connections for d after readiness
+// starts failing, so load balancers stop routing before they get refused.
+func WithDrainDelay(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.drainDelay = d
+	}
+}
+
+// WithGracePeriod bounds how long in-flight requests may take to finish
+// before their connections are closed.
+func WithGracePeriod(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.gracePeriod = d
+	}
+}
+
+// WithCloseTimeout bounds each closer.
+func WithCloseTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.closeTimeout = d
+	}
+}
+
+// WithCloser runs close once the requests drained. Closers run in the
+// order they were registered, so register dependents before what they use.
+func WithCloser(name string, close func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.closers = append(s.closers, closer{name: name, close: close})
+	}
+}
+
+// NewServer wraps server with a graceful shutdown.
+func NewServer(server *http.Server, opts ...ServerOption) *Server {
+	s := &Server{
+		server:       server,
+		gracePeriod:  DefaultGracePeriod,
+		closeTimeout: DefaultCloseTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// ListenAndServe listens on the server address and calls Serve.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	addr := s.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections on listener until ctx is done, then shuts
+// down. It returns nil when every request drained and every closer
+// succeeded.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- s.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		// The server failed on its own: still release the dependencies
+		return errors.Join(err, s.close())
+	case <-ctx.Done():
+	}
+
+	logger := slog.Default()
+	logger.Info("shutting down", "grace_period", s.gracePeriod, "drain_delay", s.drainDelay)
+
+	if s.readiness != nil {
+		s.readiness.Begin()
+	}
+	time.Sleep(s.drainDelay)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.gracePeriod)
+	defer cancel()
+
+	var drainErr error
+	if err := s.server.Shutdown(drainCtx); err != nil {
+		logger.Warn("grace period exceeded, closing remaining connections", "error", err)
+		drainErr = fmt.Errorf("draining requests: %w", err)
+		_ = s.server.Close()
+	}
+
+	// Serve returns http.ErrServerClosed as soon as shutdown starts
+	<-served
+
+	return errors.Join(drainErr, s.close())
+}
+
+// close runs every closer in order, each within the close timeout.
+func (s *Server) close() error {
+	var errs []error
+
+	for _, c := range s.closers {
+		ctx, cancel := context.WithTimeout(context.Background(), s.closeTimeout)
+		err := c.close(ctx)
+		cancel()
+
+		if err != nil {
+			slog.Default().Error("failed to close", "name", c.name, "error", err)
+			errs = append(errs, fmt.Errorf("closing %s: %w", c.name, err))
+			continue
+		}
+		slog.Default().Info("closed", "name", c.name)
+	}
+
+	return errors.Join(errs...)
+}
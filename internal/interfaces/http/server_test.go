@@ -0,0 +1,258 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"karhub-beer-machine/internal/application/auth"
+	"karhub-beer-machine/internal/application/health"
+	httpapi "karhub-beer-machine/internal/interfaces/http"
+	"karhub-beer-machine/internal/interfaces/http/handlers"
+	"karhub-beer-machine/internal/interfaces/http/middleware"
+)
+
+// closeRecorder remembers the order closers ran in.
+type closeRecorder struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (r *closeRecorder) closer(name string, err error) httpapi.ServerOption {
+	return httpapi.WithCloser(name, func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.closed = append(r.closed, name)
+		return err
+	})
+}
+
+func (r *closeRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.closed)
+}
+
+// startServer serves handler on a loopback port until the returned
+// cancel is called, and reports Serve's result on the returned channel.
+func startServer(
+	t *testing.T,
+	handler http.Handler,
+	opts ...httpapi.ServerOption,
+) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server := httpapi.NewServer(&http.Server{Handler: handler}, opts...)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, listener)
+	}()
+
+	return "http://" + listener.Addr().String(), cancel, served
+}
+
+// get sends a request on a fresh connection.
+func get(url string) (*http.Response, error) {
+	client := &http.Client{
+		Transport: &http.Transport{DisableKeepAlives: true},
+		Timeout:   2 * time.Second,
+	}
+	return client.Get(url)
+}
+
+func waitServed(t *testing.T, served <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-served:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server did not stop")
+		return nil
+	}
+}
+
+/*
+	TESTS
+*/
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	shutdown := health.NewShutdownCheck()
+	registry := health.NewRegistry()
+	registry.Register("shutdown", shutdown)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	mux := http.NewServeMux()
+	httpapi.RegisterHealthRoutes(mux, handlers.NewHealthHandler(
+		health.NewCheckHealthUseCase(registry, health.Build{}),
+	), middleware.NewGuard(auth.NewChainAuthenticator(), auth.RoleNone))
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+
+	recorder := &closeRecorder{}
+	url, cancel, served := startServer(t, mux,
+		httpapi.WithReadiness(shutdown),
+		httpapi.WithDrainDelay(300*time.Millisecond),
+		httpapi.WithGracePeriod(5*time.Second),
+		recorder.closer("gateway", nil),
+		recorder.closer("cache", nil),
+	)
+
+	resp, err := get(url + "/readyz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected ready before shutdown, got %d", resp.StatusCode)
+	}
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := get(url + "/slow")
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inFlight <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	cancel()
+
+	// Within the drain delay, connections are still accepted but readiness fails
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := get(url + "/readyz")
+		if err != nil {
+			t.Fatalf("expected connections accepted during the drain delay: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected readiness to fail once shutdown began, got %d", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Then new connections are refused while the slow request is in flight
+	deadline = time.Now().Add(2 * time.Second)
+	for {
+		resp, err := get(url + "/readyz")
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("expected new connections refused after the drain delay")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if closed := recorder.names(); len(closed) != 0 {
+		t.Fatalf("expected nothing closed before requests drained, got %v", closed)
+	}
+
+	close(release)
+
+	if got := <-inFlight; got.err != nil || got.body != "done" {
+		t.Fatalf("expected the in-flight request to complete, got %q, %v", got.body, got.err)
+	}
+
+	if err := waitServed(t, served); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if closed := recorder.names(); !slices.Equal(closed, []string{"gateway", "cache"}) {
+		t.Fatalf("expected closers run in order, got %v", closed)
+	}
+}
+
+func TestServer_GracePeriodExceeded(t *testing.T) {
+	started := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	recorder := &closeRecorder{}
+	url, cancel, served := startServer(t, handler,
+		httpapi.WithGracePeriod(100*time.Millisecond),
+		recorder.closer("cache", nil),
+	)
+
+	inFlight := make(chan error, 1)
+	go func() {
+		resp, err := get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		inFlight <- err
+	}()
+	<-started
+
+	cancel()
+
+	err := waitServed(t, served)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the grace period exceeded, got %v", err)
+	}
+
+	if err := <-inFlight; err == nil {
+		t.Fatalf("expected the stuck request cut off, got nil")
+	}
+
+	if closed := recorder.names(); !slices.Equal(closed, []string{"cache"}) {
+		t.Fatalf("expected closers run anyway, got %v", closed)
+	}
+}
+
+func TestServer_CloserErrors(t *testing.T) {
+	failure := errors.New("flush failed")
+
+	recorder := &closeRecorder{}
+	_, cancel, served := startServer(t, http.NotFoundHandler(),
+		recorder.closer("cache", failure),
+		recorder.closer("tracer", nil),
+	)
+
+	cancel()
+
+	err := waitServed(t, served)
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the closer error, got %v", err)
+	}
+
+	if closed := recorder.names(); !slices.Equal(closed, []string{"cache", "tracer"}) {
+		t.Fatalf("expected every closer run despite the failure, got %v", closed)
+	}
+}